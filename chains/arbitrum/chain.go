@@ -0,0 +1,123 @@
+package arbitrum
+
+import (
+	"context"
+	"math/big"
+	"sync"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/ethereum"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/arbitrum"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func init() {
+	chains.Register(chains.Arbitrum, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: parseConfig,
+	})
+}
+
+func parseConfig(chainCfg *core.ChainConfig) error {
+	if _, err := arbitrum.ParseOpts(chainCfg.Opts); err != nil {
+		return err
+	}
+	return chain.CheckConfig(chainCfg)
+}
+
+// InitializeChain creates an arbitrum nitro chain. The maintainer syncs the blocks confirmed on l1 by the rollup,
+// and the messenger proves the receipts against the nitro headers.
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	opts, err := arbitrum.ParseOpts(chainCfg.Opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	source, err := opts.NewRollupSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := arbitrum.Dial(ctx, chainCfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s := &syncer{client: client, source: source}
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(s.syncHeaderToMap),
+		chain.OptOfMos(chain.MosOfProof))
+}
+
+type syncer struct {
+	client *arbitrum.Client
+	source arbitrum.RollupSource
+
+	lock   sync.Mutex
+	next   *arbitrum.Confirmed // the latest confirmed block, it is synced when the maintainer gets to it
+	header *arbitrum.Header
+}
+
+// syncHeaderToMap syncs the latest confirmed block on l1 when the maintainer gets to it, the blocks before are
+// passed. The blocks after the latest confirmed block wait for the next confirmation, which takes the challenge
+// period of the rollup.
+func (s *syncer) syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.header == nil || s.header.Number.Cmp(latestBlock) < 0 {
+		syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+		if err != nil {
+			m.Log.Error("Get current synced Height failed", "err", err)
+			return err
+		}
+		if latestBlock.Cmp(syncedHeight) <= 0 {
+			m.Log.Info("CurrentBlock less than synchronized headerHeight", "synced height", syncedHeight,
+				"current height", latestBlock)
+			return nil
+		}
+		confirmed, err := s.source.LatestConfirmed(context.Background())
+		if err != nil {
+			return err
+		}
+		header, err := s.client.HeaderByHash(context.Background(), confirmed.BlockHash)
+		if err != nil {
+			return err
+		}
+		if header.Number.Cmp(latestBlock) < 0 {
+			m.Log.Debug("Block not confirmed yet", "block", latestBlock, "confirmed", header.Number)
+			return ethereum.NotFound
+		}
+		s.next, s.header = confirmed, header
+	}
+	if s.header.Number.Cmp(latestBlock) != 0 {
+		return nil
+	}
+
+	m.Log.Info("find sync block", "current height", latestBlock, "node", s.next.NodeNum,
+		"assertion", s.next.AssertionHash, "l1Block", s.header.L1BlockNumber())
+	input, err := arbitrum.NewConfirmedData(s.next, s.header)
+	if err != nil {
+		m.Log.Error("Failed to abi pack", "err", err)
+		return err
+	}
+
+	id := big.NewInt(0).SetUint64(uint64(m.Cfg.Id))
+	msgpayload := []interface{}{id, input}
+	message := msg.NewSyncToMap(m.Cfg.Id, m.Cfg.MapChainID, msgpayload, m.MsgCh)
+
+	err = m.Router.Send(message)
+	if err != nil {
+		m.Log.Error("Subscription error: failed to route message", "err", err)
+		return err
+	}
+
+	err = m.WaitUntilMsgHandled(1)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package avalanche
+
+import (
+	"context"
+	"math/big"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/ethereum"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/avalanche"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func init() {
+	chains.Register(chains.Avalanche, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: chain.CheckConfig,
+	})
+}
+
+// InitializeChain creates an avalanche C-Chain. The blocks accepted by snowman are final, so the blocks are
+// processed without confirmations unless the config sets blockConfirmations.
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	if v, ok := chainCfg.Opts[chain.BlockConfirmationsOpt]; !ok || v == "" {
+		chainCfg.Opts[chain.BlockConfirmationsOpt] = "0"
+	}
+	client, err := avalanche.Dial(context.Background(), chainCfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s := &syncer{client: client}
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(s.syncHeaderToMap),
+		chain.OptOfInitHeight(mapprotocol.HeaderCountOfAvalanche), chain.OptOfMos(chain.MosOfProof))
+}
+
+type syncer struct {
+	client *avalanche.Client
+}
+
+// syncHeaderToMap syncs the headers in batches of HeaderCountOfAvalanche, the batch ending at the block
+func (s *syncer) syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
+	remainder := big.NewInt(0).Mod(new(big.Int).Add(latestBlock, mapprotocol.Big1), big.NewInt(mapprotocol.HeaderCountOfAvalanche))
+	if remainder.Cmp(mapprotocol.Big0) != 0 {
+		return nil
+	}
+	// synced height check
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	if err != nil {
+		m.Log.Error("Get current synced Height failed", "err", err)
+		return err
+	}
+	if latestBlock.Cmp(syncedHeight) <= 0 {
+		m.Log.Info("CurrentBlock less than synchronized headerHeight", "synced height", syncedHeight,
+			"current height", latestBlock)
+		return nil
+	}
+	m.Log.Info("find sync block", "current height", latestBlock)
+	headers := make([]*avalanche.Header, mapprotocol.HeaderCountOfAvalanche)
+	for i := 0; i < mapprotocol.HeaderCountOfAvalanche; i++ {
+		headerHeight := new(big.Int).Sub(latestBlock, new(big.Int).SetInt64(int64(i)))
+		header, err := s.client.HeaderByNumber(context.Background(), headerHeight)
+		if err != nil {
+			return err
+		}
+		headers[mapprotocol.HeaderCountOfAvalanche-i-1] = header
+	}
+
+	input, err := avalanche.PackHeaders(headers)
+	if err != nil {
+		m.Log.Error("Failed to abi pack", "err", err)
+		return err
+	}
+
+	id := big.NewInt(0).SetUint64(uint64(m.Cfg.Id))
+	msgpayload := []interface{}{id, input}
+	message := msg.NewSyncToMap(m.Cfg.Id, m.Cfg.MapChainID, msgpayload, m.MsgCh)
+
+	err = m.Router.Send(message)
+	if err != nil {
+		m.Log.Error("Subscription error: failed to route message", "err", err)
+		return err
+	}
+
+	err = m.WaitUntilMsgHandled(1)
+	if err != nil {
+		return err
+	}
+	return nil
+}
@@ -7,31 +7,85 @@ import (
 
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/log15"
-	ethcommon "github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/chains"
 	connection "github.com/mapprotocol/compass/connections/ethereum"
 	"github.com/mapprotocol/compass/core"
 	"github.com/mapprotocol/compass/internal/bsc"
 	"github.com/mapprotocol/compass/internal/chain"
-	"github.com/mapprotocol/compass/internal/tx"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
 )
 
+func init() {
+	chains.Register(chains.Bsc, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: parseConfig,
+	})
+}
+
+func parseConfig(chainCfg *core.ChainConfig) error {
+	opts, err := bsc.ParseOpts(chainCfg.Opts)
+	if err != nil {
+		return err
+	}
+	cfg, err := chain.ParseConfig(chainCfg)
+	if err != nil {
+		return err
+	}
+	// the attestations finalizing a block are in the two headers after it
+	if opts.FastFinality && cfg.BlockConfirmations.Cmp(big.NewInt(mapprotocol.HeaderCountOfBscFinal-1)) < 0 {
+		return fmt.Errorf("%s must be at least %d in the fast finality mode", chain.BlockConfirmationsOpt,
+			mapprotocol.HeaderCountOfBscFinal-1)
+	}
+	return nil
+}
+
+// InitializeChain creates a bsc chain. In the fast finality mode the headers synced and proven are finalized by
+// the vote attestations of the two headers after them, instead of followed by HeaderCountOfBsc headers.
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (core.Chain, error) {
-	return chain.New(chainCfg, logger, sysErr, m, role, connection.NewConnection, chain.OptOfSync2Map(syncHeaderToMap),
-		chain.OptOfInitHeight(mapprotocol.HeaderCountOfBsc), chain.OptOfMos(mosHandler))
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	opts, err := bsc.ParseOpts(chainCfg.Opts)
+	if err != nil {
+		return nil, err
+	}
+	client, err := ethclient.Dial(chainCfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	epochs := bsc.NewEpochs(client, opts.Forks)
+	if !opts.FastFinality {
+		s := &syncer{epochs: epochs}
+		return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(s.syncHeaderToMap),
+			chain.OptOfInitHeight(mapprotocol.HeaderCountOfBsc), chain.OptOfMos(chain.MosOfProof))
+	}
+	s := &finalitySyncer{epochs: epochs, validators: bsc.NewValidatorCache(epochs)}
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(s.syncHeaderToMap),
+		chain.OptOfInitHeight(mapprotocol.HeaderCountOfBscFinal), chain.OptOfMos(chain.MosOfProof))
+}
+
+type syncer struct {
+	epochs *bsc.Epochs
 }
 
-func syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
-	remainder := big.NewInt(0).Mod(new(big.Int).Sub(latestBlock, new(big.Int).SetInt64(mapprotocol.HeaderCountOfBsc-1)),
-		big.NewInt(mapprotocol.EpochOfBsc))
-	if remainder.Cmp(mapprotocol.Big0) != 0 {
+// syncHeaderToMap syncs the epoch header with the headers after it, the epochs are as long as the forks of the
+// epoch header set
+func (s *syncer) syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
+	epochBlock := new(big.Int).Sub(latestBlock, big.NewInt(mapprotocol.HeaderCountOfBsc-1))
+	if epochBlock.Sign() < 0 {
+		return nil
+	}
+	isEpoch, err := s.epochs.IsStart(context.Background(), epochBlock.Uint64())
+	if err != nil {
+		m.Log.Error("Failed to find the epoch", "block", epochBlock, "err", err)
+		return err
+	}
+	if !isEpoch {
 		return nil
 	}
 	// synced height check
-	syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
 	if err != nil {
 		m.Log.Error("Get current synced Height failed", "err", err)
 		return err
@@ -79,68 +133,59 @@ func syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
 	return nil
 }
 
-func mosHandler(m *chain.Messenger, latestBlock *big.Int) (int, error) {
-	if !m.Cfg.SyncToMap {
-		return 0, nil
-	}
-	m.Log.Debug("Querying block for events", "block", latestBlock)
-	query := m.BuildQuery(m.Cfg.McsContract, m.Cfg.Events, latestBlock, latestBlock)
-	// querying for logs
-	logs, err := m.Conn.Client().FilterLogs(context.Background(), query)
+type finalitySyncer struct {
+	epochs     *bsc.Epochs
+	validators *bsc.ValidatorCache
+}
+
+// syncHeaderToMap syncs the epoch header with the two headers after it, once their vote attestations are checked
+// to finalize it
+func (s *finalitySyncer) syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
+	epochBlock := new(big.Int).Sub(latestBlock, big.NewInt(mapprotocol.HeaderCountOfBscFinal-1))
+	if epochBlock.Sign() < 0 {
+		return nil
+	}
+	isEpoch, err := s.epochs.IsStart(context.Background(), epochBlock.Uint64())
 	if err != nil {
-		return 0, fmt.Errorf("unable to Filter Logs: %w", err)
+		m.Log.Error("Failed to find the epoch", "block", epochBlock, "err", err)
+		return err
 	}
+	if !isEpoch {
+		return nil
+	}
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	if err != nil {
+		m.Log.Error("Get current synced Height failed", "err", err)
+		return err
+	}
+	if latestBlock.Cmp(syncedHeight) <= 0 {
+		m.Log.Info("CurrentBlock less than synchronized headerHeight", "synced height", syncedHeight,
+			"current height", latestBlock)
+		return nil
+	}
+	headers, err := bsc.FinalizedHeaders(context.Background(), m.Conn.Client(), epochBlock, s.validators)
+	if err != nil {
+		m.Log.Error("Epoch header is not finalized", "block", epochBlock, "err", err)
+		return err
+	}
+	m.Log.Info("find finalized sync block", "current height", latestBlock, "epoch", epochBlock)
 
-	m.Log.Debug("event", "latestBlock ", latestBlock, " logs ", len(logs))
-	count := 0
-	// read through the log events and handle their deposit event if handler is recognized
-	for _, log := range logs {
-		// evm event to msg
-		var message msg.Message
-		// getOrderId
-		orderId := log.Data[:32]
-		method := m.GetMethod(log.Topics[0])
-		// when syncToMap we need to assemble a tx proof
-		txsHash, err := tx.GetTxsHashByBlockNumber(m.Conn.Client(), latestBlock)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-		}
-		receipts, err := tx.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-		}
-
-		headers := make([]types.Header, mapprotocol.HeaderCountOfBsc)
-		for i := 0; i < mapprotocol.HeaderCountOfBsc; i++ {
-			headerHeight := new(big.Int).Add(latestBlock, new(big.Int).SetInt64(int64(i)))
-			header, err := m.Conn.Client().HeaderByNumber(context.Background(), headerHeight)
-			if err != nil {
-				return 0, err
-			}
-			headers[i] = *header
-		}
-
-		params := make([]bsc.Header, 0, len(headers))
-		for _, h := range headers {
-			params = append(params, bsc.ConvertHeader(h))
-		}
-
-		payload, err := bsc.AssembleProof(params, log, receipts, method, m.Cfg.Id)
-		if err != nil {
-			return 0, fmt.Errorf("unable to Parse Log: %w", err)
-		}
-
-		msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
-		message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
-
-		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index,
-			"orderId", ethcommon.Bytes2Hex(orderId))
-		err = m.Router.Send(message)
-		if err != nil {
-			m.Log.Error("Subscription error: failed to route message", "err", err)
-		}
-		count++
+	params := make([]bsc.Header, 0, len(headers))
+	for _, h := range headers {
+		params = append(params, bsc.ConvertHeader(*h))
+	}
+	input, err := mapprotocol.Bsc.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Pack(params)
+	if err != nil {
+		m.Log.Error("Failed to abi pack", "err", err)
+		return err
 	}
 
-	return count, nil
+	id := big.NewInt(0).SetUint64(uint64(m.Cfg.Id))
+	message := msg.NewSyncToMap(m.Cfg.Id, m.Cfg.MapChainID, []interface{}{id, input}, m.MsgCh)
+	err = m.Router.Send(message)
+	if err != nil {
+		m.Log.Error("Subscription error: failed to route message", "err", err)
+		return err
+	}
+	return m.WaitUntilMsgHandled(1)
 }
@@ -0,0 +1,28 @@
+package bsc
+
+import (
+	"testing"
+
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/bsc"
+	"github.com/mapprotocol/compass/internal/chain"
+)
+
+func TestParseConfig(t *testing.T) {
+	cases := []struct {
+		opts map[string]string
+		ok   bool
+	}{
+		{map[string]string{bsc.FastFinalityOpt: "true", chain.BlockConfirmationsOpt: "2"}, true},
+		{map[string]string{bsc.FastFinalityOpt: "true", chain.BlockConfirmationsOpt: "1"}, false},
+		{map[string]string{bsc.FastFinalityOpt: "true"}, true},
+		{map[string]string{chain.BlockConfirmationsOpt: "1"}, true},
+	}
+	for _, c := range cases {
+		c.opts[chain.McsOpt] = "0x0000000000000000000000000000000000000001"
+		cfg := &core.ChainConfig{Name: "bsc", Id: 56, Endpoint: "http://localhost:8545", From: "0x0", Opts: c.opts}
+		if err := parseConfig(cfg); (err == nil) != c.ok {
+			t.Errorf("config %v: %v", c.opts, err)
+		}
+	}
+}
@@ -26,8 +26,24 @@ type Chain struct {
 	stop   chan<- int
 }
 
+func init() {
+	chains.Register(chains.Eth2, &chains.Type{
+		Initialize:  initialize,
+		ParseConfig: chain.CheckConfig,
+	})
+}
+
+func initialize(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	c, err := InitializeChain(chainCfg, logger, sysErr, m, role, mc)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (*Chain, error) {
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (*Chain, error) {
 	cfg, err := chain.ParseConfig(chainCfg)
 	if err != nil {
 		return nil, err
@@ -43,6 +59,16 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
+	jn, err := chain.SetupJournal(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
+	dl, err := chain.SetupDeadLetter(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := make(chan int)
 	conn := eth2.NewConnection(cfg.Endpoint, cfg.Eth2Endpoint, cfg.Http, kp, logger, cfg.GasLimit, cfg.MaxGasPrice,
 		cfg.GasMultiplier, cfg.EgsApiKey, cfg.EgsSpeed)
@@ -61,7 +87,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 
 	// simplified a little bit
 	var listen chains.Listener
-	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs)
+	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs, mc, chain.OptOfDeadLetter(dl))
 	if role == mapprotocol.RoleOfMaintainer {
 		fn := mapprotocol.Map2EthHeight(cfg.From, cfg.LightNode, conn.Client())
 		height, err := fn()
@@ -69,8 +95,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "eth2 get init headerHeight failed")
 		}
 		logger.Info("map2eth2 Current situation", "height", height, "lightNode", cfg.LightNode)
-		mapprotocol.SyncOtherMap[cfg.Id] = height
-		mapprotocol.Map2OtherHeight[cfg.Id] = fn
+		mc.SetMap2OtherHeight(cfg.Id, height, fn)
 		listen = NewMaintainer(cs, conn.Eth2Client())
 	} else if role == mapprotocol.RoleOfMessenger {
 		err = conn.EnsureHasBytecode(cfg.McsContract)
@@ -84,10 +109,10 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "eth2 get init verifyHeight failed")
 		}
 		logger.Info("Map2eth2 Current verify range", "left", left, "right", right, "lightNode", cfg.LightNode)
-		mapprotocol.Map2OtherVerifyRange[cfg.Id] = fn
+		mc.SetMap2OtherVerifyRange(cfg.Id, fn)
 		listen = NewMessenger(cs)
 	}
-	wri := chain.NewWriter(conn, cfg, logger, stop, sysErr)
+	wri := chain.NewWriter(conn, cfg, logger, stop, sysErr, m, jn, mc)
 
 	return &Chain{
 		cfg:    chainCfg,
@@ -104,7 +129,12 @@ func (c *Chain) SetRouter(r *core.Router) {
 }
 
 func (c *Chain) Start() error {
-	err := c.listen.Sync()
+	err := c.writer.Start()
+	if err != nil {
+		return err
+	}
+
+	err = c.listen.Sync()
 	if err != nil {
 		return err
 	}
@@ -133,7 +163,7 @@ func (c *Chain) Stop() {
 	}
 }
 
-// EthClient return EthClient for global map connection
+// EthClient return EthClient for the map connection
 func (c *Chain) EthClient() *ethclient.Client {
 	return c.conn.Client()
 }
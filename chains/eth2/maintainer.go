@@ -9,7 +9,6 @@ import (
 	"time"
 
 	log "github.com/ChainSafe/log15"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/mapprotocol/compass/internal/constant"
 	"github.com/mapprotocol/compass/internal/eth2"
 	"github.com/mapprotocol/compass/msg"
@@ -23,6 +22,8 @@ type Maintainer struct {
 	*chain.CommonSync
 	syncedHeight *big.Int
 	eth2Client   *eth2.Client
+	committees   *eth2.SyncCommitteeTracker
+	updates      *eth2.UpdateBuilder
 }
 
 func NewMaintainer(cs *chain.CommonSync, eth2Client *eth2.Client) *Maintainer {
@@ -30,6 +31,8 @@ func NewMaintainer(cs *chain.CommonSync, eth2Client *eth2.Client) *Maintainer {
 		CommonSync:   cs,
 		eth2Client:   eth2Client,
 		syncedHeight: new(big.Int),
+		committees:   eth2.NewSyncCommitteeTracker(eth2Client),
+		updates:      eth2.NewUpdateBuilder(eth2Client),
 	}
 }
 
@@ -80,7 +83,7 @@ func (m *Maintainer) sync() error {
 				continue
 			}
 
-			startNumber, endNumber, err := mapprotocol.GetEth22MapNumber(m.Cfg.Id)
+			startNumber, endNumber, err := m.Map.GetEth22MapNumber(m.Cfg.Id)
 			if err != nil {
 				m.Log.Error("Get startNumber failed", "err", err)
 				time.Sleep(constant.BlockRetryInterval)
@@ -126,6 +129,13 @@ func (m *Maintainer) sync() error {
 			}
 
 			err = m.sendRegularLightClientUpdate(lastFinalizedSlotOnContract, lastFinalizedSlotOnEth)
+			if errors.Is(err, eth2.ErrUnsupportedFork) {
+				// the sync stops until compass supports the fork
+				m.Log.Crit("Beacon chain fork not supported, upgrade compass", "err", err)
+				util.Alarm(context.Background(), fmt.Sprintf("eth2 sync stopped, upgrade compass for the beacon chain fork: %s", err.Error()))
+				time.Sleep(constant.BlockRetryInterval)
+				continue
+			}
 			if err != nil {
 				m.Log.Error("Failed to listen header for block", "block", currentBlock, "err", err)
 				if !errors.Is(err, constant.ErrUnWantedSync) {
@@ -160,8 +170,8 @@ func (m *Maintainer) sync() error {
 }
 
 func (m *Maintainer) updateSyncHeight() error {
-	syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-	//syncedHeight, err := mapprotocol.Get2MapByLight()
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	//syncedHeight, err := m.Map.Get2MapByLight()
 	if err != nil {
 		m.Log.Error("Get synced Height failed", "err", err)
 		return err
@@ -198,18 +208,22 @@ func (m *Maintainer) sendRegularLightClientUpdate(lastFinalizedSlotOnContract, l
 
 	var (
 		err             error
+		version         string
 		lightUpdateData = &eth2.LightClientUpdate{}
 	)
 	m.Log.Info("Period check", "periodOnContract", lastEth2PeriodOnContract, "endPeriod", endPeriod,
 		"slotOnEth", lastFinalizedSlotOnEth, "slotOnContract", lastFinalizedSlotOnContract)
 	if lastEth2PeriodOnContract == endPeriod {
-		lightUpdateData, err = m.getFinalityLightClientUpdate(lastFinalizedSlotOnContract)
+		lightUpdateData, version, err = m.getFinalityLightClientUpdate(lastFinalizedSlotOnContract)
 	} else {
-		lightUpdateData, err = m.getLightClientUpdateForLastPeriod(lastEth2PeriodOnContract)
+		lightUpdateData, version, err = m.getLightClientUpdateForLastPeriod(lastEth2PeriodOnContract)
 	}
 	if err != nil {
 		return err
 	}
+	if err = m.verifyLightClientUpdate(lastFinalizedSlotOnContract, version, lightUpdateData); err != nil {
+		return err
+	}
 	lightClientInput, err := mapprotocol.Eth2.Methods[mapprotocol.MethodOfGetUpdatesBytes].Inputs.Pack(lightUpdateData)
 	if err != nil {
 		m.Log.Error("Failed to abi pack", "err", err)
@@ -231,10 +245,27 @@ func (m *Maintainer) sendRegularLightClientUpdate(lastFinalizedSlotOnContract, l
 	return nil
 }
 
-func (m *Maintainer) getFinalityLightClientUpdate(lastFinalizedSlotOnContract *big.Int) (*eth2.LightClientUpdate, error) {
+// verifyLightClientUpdate checks the update of the version against the sync committees tracked from the slot the
+// light client on map has finalized, an update it would reject is not sent
+func (m *Maintainer) verifyLightClientUpdate(slotOnContract *big.Int, version string, update *eth2.LightClientUpdate) error {
+	ctx := context.Background()
+	if !m.committees.Bootstrapped() {
+		if err := m.committees.Bootstrap(ctx, slotOnContract.Uint64()); err != nil {
+			return fmt.Errorf("bootstrap sync committee at slot %s: %w", slotOnContract, err)
+		}
+	}
+	if err := m.committees.Verify(ctx, version, update); err != nil {
+		return err
+	}
+	m.Log.Info("Light client update verified", "version", version, "attestedSlot", update.AttestedHeader.Slot,
+		"finalizedSlot", update.FinalizedHeader.Slot, "signatureSlot", update.SignatureSlot)
+	return nil
+}
+
+func (m *Maintainer) getFinalityLightClientUpdate(lastFinalizedSlotOnContract *big.Int) (*eth2.LightClientUpdate, string, error) {
 	resp, err := m.eth2Client.FinallyUpdate(context.Background())
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	bitvector512 := util.NewBitvector512(util.FromHexString(resp.Data.SyncAggregate.SyncCommitteeBits))
@@ -245,98 +276,24 @@ func (m *Maintainer) getFinalityLightClientUpdate(lastFinalizedSlotOnContract *b
 
 	if count*3 < bitvector512.Len()*2 {
 		m.Log.Warn(fmt.Sprintf("not enought sync committe count %d", count))
-		return nil, constant.ErrUnWantedSync
+		return nil, "", constant.ErrUnWantedSync
 	}
 
-	//signatureSlot, err := m.getSignatureSlot(resp.Data.AttestedHeader.Beacon.Slot, &resp.Data.SyncAggregate)
-	//if err != nil {
-	//	return nil, err
-	//}
-	signatureSlot, err := strconv.ParseUint(resp.Data.SignatureSlot, 10, 64)
-	if err != nil {
-		return nil, err
-	}
-
-	fhSlot, _ := big.NewInt(0).SetString(resp.Data.FinalizedHeader.Beacon.Slot, 10)
-	fhProposerIndex, ok := big.NewInt(0).SetString(resp.Data.FinalizedHeader.Beacon.ProposerIndex, 10)
+	fhSlot, ok := big.NewInt(0).SetString(resp.Data.FinalizedHeader.Beacon.Slot, 10)
 	if !ok {
-		return nil, errors.New("FinalizedHeader Slot Not Number")
+		return nil, "", errors.New("FinalizedHeader Slot Not Number")
 	}
-
 	if fhSlot.Cmp(lastFinalizedSlotOnContract) <= 0 {
 		m.Log.Warn("Finally slot less than slot on contract", "slot", fhSlot.Int64(), "contract.Int64()", lastFinalizedSlotOnContract.Int64())
-		return nil, constant.ErrUnWantedSync
+		return nil, "", constant.ErrUnWantedSync
 	}
 
 	m.Log.Info("Slot compare", "fhSlot", resp.Data.FinalizedHeader.Beacon.Slot, "fsOnContract ", lastFinalizedSlotOnContract)
-	slot, _ := big.NewInt(0).SetString(resp.Data.AttestedHeader.Beacon.Slot, 10)
-	proposerIndex, ok := big.NewInt(0).SetString(resp.Data.AttestedHeader.Beacon.ProposerIndex, 10)
-	if !ok {
-		return nil, errors.New("AttestedHeader Slot Not Number")
-	}
-	finalityBranch := make([][32]byte, 0, len(resp.Data.FinalityBranch))
-	for _, fb := range resp.Data.FinalityBranch {
-		finalityBranch = append(finalityBranch, common.HexToHash(fb))
-	}
-
-	exeFinalityBranch := make([][32]byte, 0)
-	execution := &eth2.ContractExecution{}
-	fmt.Println("resp.Version ", resp.Version)
-	if resp.Version == "capella" {
-		branches := make([]string, 0, len(resp.Data.FinalizedHeader.ExecutionBranch))
-		branches = append(branches, resp.Data.FinalizedHeader.ExecutionBranch...)
-		exeFinalityBranch = eth2.GenerateByApi(branches)
-		execution, err = eth2.ConvertExecution(&resp.Data.FinalizedHeader.Execution)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		block, err := m.eth2Client.GetBlocks(context.Background(), resp.Data.FinalizedHeader.Beacon.Slot)
-		if err != nil {
-			return nil, err
-		}
-		branches, txRoot, wdRoot, err := eth2.Generate(resp.Data.FinalizedHeader.Beacon.Slot, m.Cfg.Eth2Endpoint)
-		if err != nil {
-			return nil, err
-		}
-		exeFinalityBranch = branches
-		block.Data.Message.Body.ExecutionPayload.TransactionsRoot = txRoot
-		block.Data.Message.Body.ExecutionPayload.WithdrawalsRoot = wdRoot
-		execution, err = eth2.ConvertExecution(&block.Data.Message.Body.ExecutionPayload)
-		if err != nil {
-			return nil, err
-		}
+	update, err := m.updates.Build(context.Background(), resp.Version, resp.Data.Update())
+	if err != nil {
+		return nil, "", err
 	}
-
-	return &eth2.LightClientUpdate{
-		SignatureSlot: signatureSlot,
-		SyncAggregate: eth2.ContractSyncAggregate{
-			SyncCommitteeBits:      util.FromHexString(resp.Data.SyncAggregate.SyncCommitteeBits),
-			SyncCommitteeSignature: util.FromHexString(resp.Data.SyncAggregate.SyncCommitteeSignature),
-		},
-		AttestedHeader: eth2.BeaconBlockHeader{
-			Slot:          slot.Uint64(),
-			ProposerIndex: proposerIndex.Uint64(),
-			ParentRoot:    common.HexToHash(resp.Data.AttestedHeader.Beacon.ParentRoot),
-			StateRoot:     common.HexToHash(resp.Data.AttestedHeader.Beacon.StateRoot),
-			BodyRoot:      common.HexToHash(resp.Data.AttestedHeader.Beacon.BodyRoot),
-		},
-		NextSyncCommittee: eth2.ContractSyncCommittee{
-			Pubkeys:         make([]byte, 0),
-			AggregatePubkey: make([]byte, 0),
-		},
-		NextSyncCommitteeBranch: nil,
-		FinalityBranch:          finalityBranch,
-		FinalizedHeader: eth2.BeaconBlockHeader{
-			Slot:          fhSlot.Uint64(),
-			ProposerIndex: fhProposerIndex.Uint64(),
-			ParentRoot:    common.HexToHash(resp.Data.FinalizedHeader.Beacon.ParentRoot),
-			StateRoot:     common.HexToHash(resp.Data.FinalizedHeader.Beacon.StateRoot),
-			BodyRoot:      common.HexToHash(resp.Data.FinalizedHeader.Beacon.BodyRoot),
-		},
-		ExecutionBranch:    exeFinalityBranch,
-		FinalizedExecution: execution,
-	}, nil
+	return update, resp.Version, nil
 }
 
 func (m *Maintainer) getSignatureSlot(slot string, sa *eth2.SyncAggregate) (uint64, error) {
@@ -365,15 +322,15 @@ func (m *Maintainer) getSignatureSlot(slot string, sa *eth2.SyncAggregate) (uint
 	return signatureSlot, nil
 }
 
-func (m *Maintainer) getLightClientUpdateForLastPeriod(lastEth2PeriodOnContract uint64) (*eth2.LightClientUpdate, error) {
+func (m *Maintainer) getLightClientUpdateForLastPeriod(lastEth2PeriodOnContract uint64) (*eth2.LightClientUpdate, string, error) {
 	headers, err := m.eth2Client.BeaconHeaders(context.Background(), constant.HeadBlockIdOfEth2)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
 
 	headerSlot, ok := big.NewInt(0).SetString(headers.Data.Header.Message.Slot, 10)
 	if !ok {
-		return nil, errors.New("BeaconHeaders Slot Not Number")
+		return nil, "", errors.New("BeaconHeaders Slot Not Number")
 	}
 
 	lastPeriod := m.getPeriodForSlot(headerSlot.Uint64())
@@ -382,93 +339,13 @@ func (m *Maintainer) getLightClientUpdateForLastPeriod(lastEth2PeriodOnContract
 	}
 	resp, err := m.eth2Client.LightClientUpdate(context.Background(), lastPeriod)
 	if err != nil {
-		return nil, err
+		return nil, "", err
 	}
-	slot, _ := big.NewInt(0).SetString(resp.Data.AttestedHeader.Beacon.Slot, 10)
-	proposerIndex, ok := big.NewInt(0).SetString(resp.Data.AttestedHeader.Beacon.ProposerIndex, 10)
-	if !ok {
-		return nil, errors.New("AttestedHeader Slot Not Number")
-	}
-
-	nextSyncCommitteeBranch := make([][32]byte, 0, len(resp.Data.NextSyncCommitteeBranch))
-	for _, b := range resp.Data.NextSyncCommitteeBranch {
-		nextSyncCommitteeBranch = append(nextSyncCommitteeBranch, common.HexToHash(b))
-	}
-	pubKeys := make([]byte, 0, len(resp.Data.NextSyncCommittee.Pubkeys)*48)
-	for _, pk := range resp.Data.NextSyncCommittee.Pubkeys {
-		pubKeys = append(pubKeys, util.FromHexString(pk)...)
-	}
-	finalityBranch := make([][32]byte, 0, len(resp.Data.FinalityBranch))
-	for _, fb := range resp.Data.FinalityBranch {
-		finalityBranch = append(finalityBranch, common.HexToHash(fb))
-	}
-	fhSlot, _ := big.NewInt(0).SetString(resp.Data.FinalizedHeader.Beacon.Slot, 10)
-	fhProposerIndex, ok := big.NewInt(0).SetString(resp.Data.FinalizedHeader.Beacon.ProposerIndex, 10)
-	if !ok {
-		return nil, errors.New("FinalizedHeader  Slot Not Number")
-	}
-
-	exeFinalityBranch := make([][32]byte, 0)
-	execution := &eth2.ContractExecution{}
-	signatureSlot, err := strconv.ParseUint(resp.Data.SignatureSlot, 10, 64)
+	update, err := m.updates.Build(context.Background(), resp.Version, &resp.Data)
 	if err != nil {
-		return nil, err
-	}
-	fmt.Println("resp.Version ", resp.Version)
-	if resp.Version == "capella" {
-		branches := make([]string, 0, len(resp.Data.FinalizedHeader.ExecutionBranch))
-		branches = append(branches, resp.Data.FinalizedHeader.ExecutionBranch...)
-		exeFinalityBranch = eth2.GenerateByApi(branches)
-		execution, err = eth2.ConvertExecution(&resp.Data.FinalizedHeader.Execution)
-		if err != nil {
-			return nil, err
-		}
-	} else {
-		block, err := m.eth2Client.GetBlocks(context.Background(), resp.Data.FinalizedHeader.Beacon.Slot)
-		if err != nil {
-			return nil, err
-		}
-		branches, txRoot, wdRoot, err := eth2.Generate(resp.Data.FinalizedHeader.Beacon.Slot, m.Cfg.Eth2Endpoint)
-		if err != nil {
-			return nil, err
-		}
-		exeFinalityBranch = branches
-		block.Data.Message.Body.ExecutionPayload.TransactionsRoot = txRoot
-		block.Data.Message.Body.ExecutionPayload.WithdrawalsRoot = wdRoot
-		execution, err = eth2.ConvertExecution(&block.Data.Message.Body.ExecutionPayload)
-		if err != nil {
-			return nil, err
-		}
+		return nil, "", err
 	}
-	return &eth2.LightClientUpdate{
-		AttestedHeader: eth2.BeaconBlockHeader{
-			Slot:          slot.Uint64(),
-			ProposerIndex: proposerIndex.Uint64(),
-			ParentRoot:    common.HexToHash(resp.Data.AttestedHeader.Beacon.ParentRoot),
-			StateRoot:     common.HexToHash(resp.Data.AttestedHeader.Beacon.StateRoot),
-			BodyRoot:      common.HexToHash(resp.Data.AttestedHeader.Beacon.BodyRoot),
-		},
-		SyncAggregate: eth2.ContractSyncAggregate{
-			SyncCommitteeBits:      util.FromHexString(resp.Data.SyncAggregate.SyncCommitteeBits),
-			SyncCommitteeSignature: util.FromHexString(resp.Data.SyncAggregate.SyncCommitteeSignature),
-		},
-		SignatureSlot:           signatureSlot,
-		NextSyncCommitteeBranch: nextSyncCommitteeBranch,
-		NextSyncCommittee: eth2.ContractSyncCommittee{
-			Pubkeys:         pubKeys,
-			AggregatePubkey: util.FromHexString(resp.Data.NextSyncCommittee.AggregatePubkey),
-		},
-		FinalityBranch: finalityBranch,
-		FinalizedHeader: eth2.BeaconBlockHeader{
-			Slot:          fhSlot.Uint64(),
-			ProposerIndex: fhProposerIndex.Uint64(),
-			ParentRoot:    common.HexToHash(resp.Data.FinalizedHeader.Beacon.ParentRoot),
-			StateRoot:     common.HexToHash(resp.Data.FinalizedHeader.Beacon.StateRoot),
-			BodyRoot:      common.HexToHash(resp.Data.FinalizedHeader.Beacon.BodyRoot),
-		},
-		ExecutionBranch:    exeFinalityBranch,
-		FinalizedExecution: execution,
-	}, nil
+	return update, resp.Version, nil
 }
 
 func (m *Maintainer) updateHeaders(startNumber, endNumber *big.Int) error {
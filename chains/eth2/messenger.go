@@ -9,9 +9,7 @@ import (
 
 	"github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/constant"
-	"github.com/mapprotocol/compass/internal/eth2"
-	"github.com/mapprotocol/compass/internal/tx"
-	"github.com/mapprotocol/compass/mapprotocol"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/pkg/util"
 
 	"github.com/mapprotocol/compass/msg"
@@ -71,7 +69,7 @@ func (m *Messenger) sync() error {
 				m.Metrics.LatestKnownBlock.Set(float64(latestBlock.Int64()))
 			}
 
-			left, right, err := mapprotocol.Get2MapVerifyRange(m.Cfg.Id)
+			left, right, err := m.Map.Get2MapVerifyRange(m.Cfg.Id)
 			if err != nil {
 				m.Log.Warn("Get2MapVerifyRange failed", "err", err)
 			}
@@ -138,6 +136,18 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 		return 0, fmt.Errorf("unable to Filter Logs: %w", err)
 	}
 
+	if len(logs) == 0 {
+		return 0, nil
+	}
+	pb, err := m.ProofBuilder()
+	if err != nil {
+		return 0, err
+	}
+	// when syncToMap we need to assemble a tx proof
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
+	if err != nil {
+		return 0, err
+	}
 	count := 0
 	// read through the log events and handle their deposit event if handler is recognized
 	for _, log := range logs {
@@ -145,26 +155,14 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 		var message msg.Message
 		// getOrderId
 		orderId := log.Data[:32]
-		method := m.GetMethod(log.Topics[0])
-		header, err := m.Conn.Client().EthLatestHeaderByNumber(m.Cfg.Endpoint, latestBlock)
-		if err != nil {
+		proof, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, iproof.ErrInvalidProof) {
+			continue
+		} else if err != nil {
 			return 0, err
 		}
-		// when syncToMap we need to assemble a tx proof
-		txsHash, err := tx.GetTxsHashByBlockNumber(m.Conn.Client(), latestBlock)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-		}
-		receipts, err := tx.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-		}
-		payload, err := eth2.AssembleProof(*eth2.ConvertHeader(header), log, receipts, method, m.Cfg.Id)
-		if err != nil {
-			return 0, fmt.Errorf("unable to Parse Log: %w", err)
-		}
 
-		msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
+		msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash}
 		message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
 
 		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index, "orderId", ethcommon.Bytes2Hex(orderId))
@@ -23,7 +23,11 @@ The writer recieves the message and creates a proposals on-chain. Once a proposa
 package ethereum
 
 import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/mapchain"
 	"github.com/pkg/errors"
 
 	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
@@ -48,8 +52,35 @@ type Chain struct {
 	listen chains.Listener // The listener of this chain
 }
 
+func init() {
+	chains.Register(chains.Ethereum, &chains.Type{
+		Initialize:  initialize,
+		ParseConfig: chain.CheckConfig,
+		InitMap:     initMap,
+		Proof: func(context.Context, *core.ChainConfig, common.Hash) (string, error) {
+			return "", chains.ErrProofNotSupported
+		},
+	})
+}
+
+func initialize(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	c, err := InitializeChain(chainCfg, logger, sysErr, m, role, mc)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// initMap sets the map chain as the map connection of the context, the heights of the light clients on map are
+// queried from the light manager through it
+func initMap(c core.Chain, chainCfg *core.ChainConfig, mc *mapprotocol.MapContext) error {
+	mc.SetMapConn(c.(*Chain).EthClient(), common.HexToAddress(chainCfg.Opts[chain.LightNode]))
+	return nil
+}
+
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (*Chain, error) {
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (*Chain, error) {
 	cfg, err := chain.ParseConfig(chainCfg)
 	if err != nil {
 		return nil, err
@@ -66,6 +97,16 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
+	jn, err := chain.SetupJournal(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
+	dl, err := chain.SetupDeadLetter(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := make(chan int)
 	conn := connection.NewConnection(cfg.Endpoint, cfg.Http, kp, logger, cfg.GasLimit, cfg.MaxGasPrice,
 		cfg.GasMultiplier, cfg.EgsApiKey, cfg.EgsSpeed)
@@ -89,13 +130,12 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "eth get init headerHeight failed")
 		}
 		logger.Info("map2Other Current situation", "chain", cfg.Name, "height", height)
-		mapprotocol.SyncOtherMap[cfg.Id] = height
-		mapprotocol.Map2OtherHeight[cfg.Id] = fn
+		mc.SetMap2OtherHeight(cfg.Id, height, fn)
 	}
 
 	// simplified a little bit
 	var listen chains.Listener
-	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs)
+	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs, mc, chain.OptOfDeadLetter(dl))
 	if role == mapprotocol.RoleOfMessenger {
 		err = conn.EnsureHasBytecode(cfg.McsContract)
 		if err != nil {
@@ -109,14 +149,26 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 				return nil, errors.Wrap(err, "eth init verify range failed")
 			}
 			logger.Info("Map2eth Current verify range", "chain", cfg.Name, "left", left, "right", right, "lightNode", cfg.LightNode)
-			mapprotocol.Map2OtherVerifyRange[cfg.Id] = fn
+			mc.SetMap2OtherVerifyRange(cfg.Id, fn)
 		}
 		listen = NewMessenger(cs)
 		logger.Info("Listen event", "chain", cfg.Name, "event", cfg.Events)
 	} else if role == mapprotocol.RoleOfMaintainer { // Maintainer is used by default
-		listen = NewMaintainer(cs)
+		var validators *mapchain.ValidatorTracker
+		if cfg.Id == cfg.MapChainID {
+			store, err := mapchain.NewStore(cfg.BlockstorePath, cfg.Id, kp.Address(), role)
+			if err != nil {
+				return nil, err
+			}
+			validators, err = mapchain.NewValidatorTracker(conn.Client(), store, mapprotocol.EpochOfMap)
+			if err != nil {
+				return nil, err
+			}
+			logger.Info("Map validator tracker", "tracked", validators.Tracked())
+		}
+		listen = NewMaintainer(cs, validators)
 	}
-	writer := chain.NewWriter(conn, cfg, logger, stop, sysErr)
+	writer := chain.NewWriter(conn, cfg, logger, stop, sysErr, m, jn, mc)
 
 	return &Chain{
 		cfg:    chainCfg,
@@ -133,7 +185,12 @@ func (c *Chain) SetRouter(r *core.Router) {
 }
 
 func (c *Chain) Start() error {
-	err := c.listen.Sync()
+	err := c.writer.Start()
+	if err != nil {
+		return err
+	}
+
+	err = c.listen.Sync()
 	if err != nil {
 		return err
 	}
@@ -161,7 +218,7 @@ func (c *Chain) Stop() {
 	}
 }
 
-// EthClient return EthClient for global map connection
+// EthClient return EthClient for the map connection
 func (c *Chain) EthClient() *ethclient.Client {
 	return c.conn.Client()
 }
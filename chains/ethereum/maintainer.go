@@ -10,6 +10,7 @@ import (
 
 	"github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/constant"
+	"github.com/mapprotocol/compass/internal/mapchain"
 	"github.com/mapprotocol/compass/pkg/util"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -24,12 +25,14 @@ import (
 type Maintainer struct {
 	*chain.CommonSync
 	syncedHeight *big.Int
+	validators   *mapchain.ValidatorTracker // only of the maintainer of map
 }
 
-func NewMaintainer(cs *chain.CommonSync) *Maintainer {
+func NewMaintainer(cs *chain.CommonSync, validators *mapchain.ValidatorTracker) *Maintainer {
 	return &Maintainer{
 		CommonSync:   cs,
 		syncedHeight: new(big.Int),
+		validators:   validators,
 	}
 }
 
@@ -54,8 +57,8 @@ func (m Maintainer) sync() error {
 
 	if m.Cfg.SyncToMap {
 		// check whether needs quick listen
-		syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-		//syncedHeight, err := mapprotocol.Get2MapByLight()
+		syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+		//syncedHeight, err := m.Map.Get2MapByLight()
 		if err != nil {
 			m.Log.Error("Get synced Height failed", "err", err)
 			return err
@@ -83,7 +86,7 @@ func (m Maintainer) sync() error {
 		}
 	} else if m.Cfg.Id == m.Cfg.MapChainID {
 		minHeight := big.NewInt(0)
-		for cId, height := range mapprotocol.SyncOtherMap {
+		for cId, height := range m.Map.SyncedHeights() {
 			if minHeight.Uint64() == 0 || minHeight.Cmp(height) == 1 {
 				m.Log.Info("map to other chain find min sync height ", "chainId", cId,
 					"syncedHeight", minHeight, "currentHeight", height)
@@ -161,8 +164,8 @@ func (m Maintainer) sync() error {
 // syncHeaderToMap listen header from current chain to Map chain
 func (m *Maintainer) syncHeaderToMap(latestBlock *big.Int) error {
 	// It is checked whether the latest height is higher than the current height
-	syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-	//syncedHeight, err := mapprotocol.Get2MapByLight()
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	//syncedHeight, err := m.Map.Get2MapByLight()
 	if err != nil {
 		m.Log.Error("Get synced Height failed", "err", err)
 		return err
@@ -264,6 +267,11 @@ func (m *Maintainer) syncMapHeader(latestBlock *big.Int) error {
 		return err
 	}
 
+	// verified once here for all of the chains, a header failing it is never relayed
+	if err = m.validators.Verify(context.Background(), header); err != nil {
+		return errors.Wrap(err, "verify map header failed")
+	}
+
 	h := mapprotocol.ConvertHeader(header)
 	aggPK, ist, aggPKBytes, err := mapprotocol.GetAggPK(m.Conn.Client(), new(big.Int).Sub(header.Number, big.NewInt(1)), header.Extra)
 	if err != nil {
@@ -285,14 +293,14 @@ func (m *Maintainer) syncMapHeader(latestBlock *big.Int) error {
 	waitCount := len(m.Cfg.SyncChainIDList)
 	for _, cid := range m.Cfg.SyncChainIDList {
 		// Only when the latestblock is greater than the height of the synchronized block, the synchronization is performed
-		if v, ok := mapprotocol.SyncOtherMap[cid]; ok && latestBlock.Cmp(v) <= 0 {
+		if v, ok := m.Map.SyncedHeight(cid); ok && latestBlock.Cmp(v) <= 0 {
 			waitCount--
 			m.Log.Info("map to other current less than synchronized headerHeight", "toChainId", cid, "synced height", v,
 				"current height", latestBlock)
 			continue
 		}
 		// Query the latest height for comparison
-		if fn, ok := mapprotocol.Map2OtherHeight[cid]; ok {
+		if fn, ok := m.Map.Map2OtherHeight(cid); ok {
 			height, err := fn()
 			if err != nil {
 				return errors.Wrap(err, "get headerHeight failed")
@@ -304,7 +312,7 @@ func (m *Maintainer) syncMapHeader(latestBlock *big.Int) error {
 				continue
 			}
 		}
-		if name, ok := mapprotocol.OnlineChaId[cid]; ok && strings.ToLower(name) == "near" {
+		if name, ok := m.Map.ChainName(cid); ok && strings.ToLower(name) == "near" {
 			param := map[string]interface{}{
 				"header": mapprotocol.ConvertNearNeedHeader(header),
 				"agg_pk": map[string]interface{}{
@@ -9,10 +9,9 @@ import (
 
 	"github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/constant"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/pkg/util"
 
-	"github.com/mapprotocol/compass/mapprotocol"
-
 	"github.com/mapprotocol/compass/msg"
 
 	eth "github.com/ethereum/go-ethereum"
@@ -74,7 +73,7 @@ func (m *Messenger) sync() error {
 			}
 
 			if m.Cfg.SyncToMap {
-				left, right, err := mapprotocol.Get2MapVerifyRange(m.Cfg.Id)
+				left, right, err := m.Map.Get2MapVerifyRange(m.Cfg.Id)
 				if err != nil {
 					m.Log.Warn("Get2MapVerifyRange failed", "err", err)
 				}
@@ -139,6 +138,17 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 	}
 
 	m.Log.Debug("event", "latestBlock ", latestBlock, " logs ", len(logs))
+	if len(logs) == 0 || (!m.Cfg.SyncToMap && m.Cfg.Id != m.Cfg.MapChainID) {
+		return 0, nil
+	}
+	pb, err := m.ProofBuilder()
+	if err != nil {
+		return 0, err
+	}
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
+	if err != nil {
+		return 0, err
+	}
 	count := 0
 	// read through the log events and handle their deposit event if handler is recognized
 	for _, log := range logs {
@@ -146,59 +156,24 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 		var message msg.Message
 		// getOrderId
 		orderId := log.Data[:32]
-		method := m.GetMethod(log.Topics[0])
+		// when syncToMap we need to assemble a tx proof, when listen from map we also need to assemble a tx prove in a different way
+		proof, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, iproof.ErrInvalidProof) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
 		if m.Cfg.SyncToMap {
-			// when syncToMap we need to assemble a tx proof
-			txsHash, err := mapprotocol.GetTransactionsHashByBlockNumber(m.Conn.Client(), latestBlock)
-			if err != nil {
-				return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-			}
-			receipts, err := mapprotocol.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-			if err != nil {
-				return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-			}
-			payload, err := utils.ParseEthLogIntoSwapWithProofArgs(log, m.Cfg.McsContract, receipts, method, m.Cfg.Id, m.Cfg.MapChainID)
-			if err != nil {
-				return 0, fmt.Errorf("unable to Parse Log: %w", err)
-			}
-
-			msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
+			msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash}
 			message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
 		} else if m.Cfg.Id == m.Cfg.MapChainID {
-			// when listen from map we also need to assemble a tx prove in a different way
-			header, err := m.Conn.Client().MAPHeaderByNumber(context.Background(), latestBlock)
-			if err != nil {
-				return 0, fmt.Errorf("unable to query header Logs: %w", err)
-			}
-			txsHash, err := mapprotocol.GetMapTransactionsHashByBlockNumber(m.Conn.Client(), latestBlock)
-			if err != nil {
-				return 0, fmt.Errorf("idSame unable to get tx hashes Logs: %w", err)
-			}
-			receipts, err := mapprotocol.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-			if err != nil {
-				return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-			}
-			//
-			remainder := big.NewInt(0).Mod(latestBlock, big.NewInt(mapprotocol.EpochOfMap))
-			if remainder.Cmp(mapprotocol.Big0) == 0 {
-				lr, err := mapprotocol.GetLastReceipt(m.Conn.Client(), latestBlock)
-				if err != nil {
-					return 0, fmt.Errorf("unable to get last receipts in epoch last %w", err)
-				}
-				receipts = append(receipts, lr)
-			}
-
-			toChainID, payload, err := utils.AssembleMapProof(m.Conn.Client(), log, receipts, header, m.Cfg.MapChainID, method)
-			if err != nil {
-				return 0, fmt.Errorf("unable to Parse Log: %w", err)
-			}
-
-			if _, ok := mapprotocol.OnlineChaId[msg.ChainId(toChainID)]; !ok {
+			toChainID := proof.ToChainId
+			if _, ok := m.Map.ChainName(toChainID); !ok {
 				m.Log.Info("Found a log that is not the current task ", "toChainID", toChainID)
 				continue
 			}
 
-			if fn, ok := mapprotocol.Map2OtherVerifyRange[msg.ChainId(toChainID)]; ok {
+			if fn, ok := m.Map.Map2OtherVerifyRange(toChainID); ok {
 				left, right, err := fn()
 				if err != nil {
 					m.Log.Warn("map chain Get2OtherVerifyRange failed", "err", err)
@@ -213,8 +188,8 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 				}
 			}
 
-			msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash, method}
-			message = msg.NewSwapWithMapProof(m.Cfg.MapChainID, msg.ChainId(toChainID), msgPayload, m.MsgCh)
+			msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash, proof.Method}
+			message = msg.NewSwapWithMapProof(m.Cfg.MapChainID, toChainID, msgPayload, m.MsgCh)
 		}
 
 		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index, "orderId", ethcommon.Bytes2Hex(orderId))
@@ -19,23 +19,18 @@ type Listener interface {
 }
 
 const (
-	Map      = "map"
-	Bsc      = "bsc"
-	Matic    = "matic"
-	Klaytn   = "klaytn"
-	Eth2     = "eth2"
-	Platon   = "platon"
-	Near     = "near"
-	Ethereum = "ethereum"
-)
-
-var (
-	NearChainId = map[msg.ChainId]struct{}{
-		1313161556:          {},
-		1313161555:          {},
-		1313161554:          {},
-		5566818579631833089: {},
-	}
+	Map       = "map"
+	Bsc       = "bsc"
+	Matic     = "matic"
+	Klaytn    = "klaytn"
+	Eth2      = "eth2"
+	Platon    = "platon"
+	Near      = "near"
+	Ethereum  = "ethereum"
+	Opstack   = "opstack"
+	Arbitrum  = "arbitrum"
+	Avalanche = "avalanche"
+	Tron      = "tron"
 )
 
 //type Writer interface {
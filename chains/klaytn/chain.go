@@ -2,6 +2,7 @@ package klaytn
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -9,54 +10,58 @@ import (
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/log15"
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	connection "github.com/mapprotocol/compass/connections/ethereum"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/klaytn"
 	"github.com/mapprotocol/compass/core"
 	"github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/klaytn"
-	"github.com/mapprotocol/compass/internal/tx"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
 )
 
-var (
-	kClient = &klaytn.Client{}
-)
-
-func connectKClient(endpoint string) error {
-	kc, err := klaytn.DialHttp(endpoint, true)
-	if err != nil {
-		return err
+// kClient returns the klaytn client of the connection of the chain
+func kClient(conn chain.Connection) (*klaytn.Client, error) {
+	kc, ok := conn.(chain.KConnection)
+	if !ok {
+		return nil, fmt.Errorf("connection %T is not a klaytn connection", conn)
 	}
-	kClient = kc
-	return nil
+	return kc.KClient(), nil
 }
 
-func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (core.Chain, error) {
-	err := connectKClient(chainCfg.Endpoint)
-	if err != nil {
-		return nil, err
-	}
+func init() {
+	chains.Register(chains.Klaytn, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: chain.CheckConfig,
+	})
+}
 
-	return chain.New(chainCfg, logger, sysErr, m, role, connection.NewConnection, chain.OptOfSync2Map(syncHeaderToMap),
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(syncHeaderToMap),
 		chain.OptOfMos(mosHandler))
 }
 
 func syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
-	if err := syncValidatorHeader(m, latestBlock); err != nil {
+	kc, err := kClient(m.Conn)
+	if err != nil {
+		return err
+	}
+
+	if err := syncValidatorHeader(m, kc, latestBlock); err != nil {
 		return err
 	}
 
-	if err := syncHeader(m, latestBlock); err != nil {
+	if err := syncHeader(m, kc, latestBlock); err != nil {
 		return err
 	}
 
 	return nil
 }
 
-func syncValidatorHeader(m *chain.Maintainer, latestBlock *big.Int) error {
-	kHeader, err := kClient.BlockByNumber(context.Background(), latestBlock)
+func syncValidatorHeader(m *chain.Maintainer, kc *klaytn.Client, latestBlock *big.Int) error {
+	kHeader, err := kc.BlockByNumber(context.Background(), latestBlock)
 	if err != nil {
 		return err
 	}
@@ -66,18 +71,18 @@ func syncValidatorHeader(m *chain.Maintainer, latestBlock *big.Int) error {
 	}
 	time.Sleep(time.Second)
 	m.Log.Info("Send Validator Header", "blockHeight", latestBlock, "voteData", kHeader.VoteData)
-	return sendSyncHeader(m, latestBlock, 2)
+	return sendSyncHeader(m, kc, latestBlock, 2)
 }
 
-func syncHeader(m *chain.Maintainer, latestBlock *big.Int) error {
+func syncHeader(m *chain.Maintainer, kc *klaytn.Client, latestBlock *big.Int) error {
 	remainder := big.NewInt(0).Mod(latestBlock, big.NewInt(mapprotocol.EpochOfKlaytn))
 	if remainder.Cmp(mapprotocol.Big0) != 0 {
 		return nil
 	}
 
 	m.Log.Info("Find sync block", "current height", latestBlock)
-	//syncedHeight, err := mapprotocol.Get2MapByLight()
-	syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
+	//syncedHeight, err := m.Map.Get2MapByLight()
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
 	if err != nil {
 		m.Log.Error("Get current synced Height failed", "err", err)
 		return err
@@ -88,11 +93,11 @@ func syncHeader(m *chain.Maintainer, latestBlock *big.Int) error {
 		return nil
 	}
 
-	return sendSyncHeader(m, latestBlock, mapprotocol.HeaderCountOfKlaytn)
+	return sendSyncHeader(m, kc, latestBlock, mapprotocol.HeaderCountOfKlaytn)
 }
 
-func sendSyncHeader(m *chain.Maintainer, latestBlock *big.Int, count int) error {
-	headers, err := assembleHeader(m.Conn.Client(), latestBlock, count)
+func sendSyncHeader(m *chain.Maintainer, kc *klaytn.Client, latestBlock *big.Int, count int) error {
+	headers, err := assembleHeader(m.Conn.Client(), kc, latestBlock, count)
 	if err != nil {
 		return err
 	}
@@ -124,7 +129,7 @@ func sendSyncHeader(m *chain.Maintainer, latestBlock *big.Int, count int) error
 	return nil
 }
 
-func assembleHeader(client *ethclient.Client, latestBlock *big.Int, count int) ([]klaytn.Header, error) {
+func assembleHeader(client *ethclient.Client, kc *klaytn.Client, latestBlock *big.Int, count int) ([]klaytn.Header, error) {
 	headers := make([]klaytn.Header, count)
 	for i := 0; i < count; i++ {
 		headerHeight := new(big.Int).Add(latestBlock, new(big.Int).SetInt64(int64(i)))
@@ -132,7 +137,7 @@ func assembleHeader(client *ethclient.Client, latestBlock *big.Int, count int) (
 		if err != nil {
 			return nil, err
 		}
-		hKheader, err := kClient.BlockByNumber(context.Background(), headerHeight)
+		hKheader, err := kc.BlockByNumber(context.Background(), headerHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -156,35 +161,30 @@ func mosHandler(m *chain.Messenger, latestBlock *big.Int) (int, error) {
 	}
 
 	m.Log.Debug("Event", "latestBlock ", latestBlock, " logs ", len(logs))
+	if len(logs) == 0 {
+		return 0, nil
+	}
+	kc, err := kClient(m.Conn)
+	if err != nil {
+		return 0, err
+	}
+	pb := klaytn.NewBuilder(m.Conn.Client(), kc, m.Cfg.Id)
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
+	if err != nil {
+		return 0, err
+	}
 	count := 0
 	for _, log := range logs {
 		var message msg.Message
 		orderId := log.Data[:32]
-		method := m.GetMethod(log.Topics[0])
-		txsHash, err := klaytn.GetTxsHashByBlockNumber(kClient, latestBlock)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-		}
-		receipts, err := tx.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-		}
-		// get block
-		header, err := m.Conn.Client().HeaderByNumber(context.Background(), latestBlock)
-		if err != nil {
-			return 0, err
-		}
-		kHeader, err := kClient.BlockByNumber(context.Background(), latestBlock)
-		if err != nil {
+		proof, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, iproof.ErrInvalidProof) {
+			continue
+		} else if err != nil {
 			return 0, err
 		}
 
-		payload, err := klaytn.AssembleProof(klaytn.ConvertContractHeader(header, kHeader), log, m.Cfg.Id, receipts, method)
-		if err != nil {
-			return 0, fmt.Errorf("unable to Parse Log: %w", err)
-		}
-
-		msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
+		msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash}
 		message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
 
 		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index,
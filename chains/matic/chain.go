@@ -27,8 +27,24 @@ type Chain struct {
 	listen chains.Listener // The listener of this chain
 }
 
+func init() {
+	chains.Register(chains.Matic, &chains.Type{
+		Initialize:  initialize,
+		ParseConfig: chain.CheckConfig,
+	})
+}
+
+func initialize(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	c, err := InitializeChain(chainCfg, logger, sysErr, m, role, mc)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (*Chain, error) {
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (*Chain, error) {
 	cfg, err := chain.ParseConfig(chainCfg)
 	if err != nil {
 		return nil, err
@@ -45,6 +61,16 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
+	jn, err := chain.SetupJournal(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
+	dl, err := chain.SetupDeadLetter(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := make(chan int)
 	conn := connection.NewConnection(cfg.Endpoint, cfg.Http, kp, logger, cfg.GasLimit, cfg.MaxGasPrice,
 		cfg.GasMultiplier, cfg.EgsApiKey, cfg.EgsSpeed)
@@ -62,7 +88,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 	}
 
 	var listen chains.Listener
-	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs)
+	cs := chain.NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs, mc, chain.OptOfDeadLetter(dl))
 	if role == mapprotocol.RoleOfMaintainer { // 请求获取同步的map高度
 		fn := mapprotocol.Map2EthHeight(cfg.From, cfg.LightNode, conn.Client())
 		height, err := fn()
@@ -70,8 +96,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "matic get init headerHeight failed")
 		}
 		logger.Info("map2 Current situation", "height", height, "lightNode", cfg.LightNode)
-		mapprotocol.SyncOtherMap[cfg.Id] = height
-		mapprotocol.Map2OtherHeight[cfg.Id] = fn
+		mc.SetMap2OtherHeight(cfg.Id, height, fn)
 		listen = NewMaintainer(cs)
 	} else if role == mapprotocol.RoleOfMessenger {
 		err = conn.EnsureHasBytecode(cfg.McsContract)
@@ -85,10 +110,10 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "matic get init verifyHeight failed")
 		}
 		logger.Info("Map2Matic Current verify range", "left", left, "right", right, "lightNode", cfg.LightNode)
-		mapprotocol.Map2OtherVerifyRange[cfg.Id] = fn
+		mc.SetMap2OtherVerifyRange(cfg.Id, fn)
 		listen = NewMessenger(cs)
 	}
-	w := chain.NewWriter(conn, cfg, logger, stop, sysErr)
+	w := chain.NewWriter(conn, cfg, logger, stop, sysErr, m, jn, mc)
 
 	return &Chain{
 		cfg:    chainCfg,
@@ -105,7 +130,12 @@ func (c *Chain) SetRouter(r *core.Router) {
 }
 
 func (c *Chain) Start() error {
-	err := c.listen.Sync()
+	err := c.writer.Start()
+	if err != nil {
+		return err
+	}
+
+	err = c.listen.Sync()
 	if err != nil {
 		return err
 	}
@@ -134,7 +164,7 @@ func (c *Chain) Stop() {
 	}
 }
 
-// EthClient return EthClient for global map connection
+// EthClient return EthClient for the map connection
 func (c *Chain) EthClient() *ethclient.Client {
 	return c.conn.Client()
 }
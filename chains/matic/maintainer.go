@@ -49,8 +49,8 @@ func (m Maintainer) sync() error {
 	m.Log.Info("Polling Blocks...", "block", currentBlock)
 
 	if m.Cfg.SyncToMap {
-		syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-		//syncedHeight, err := mapprotocol.Get2MapByLight()
+		syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+		//syncedHeight, err := m.Map.Get2MapByLight()
 		if err != nil {
 			m.Log.Error("Get synced Height failed", "err", err)
 			return err
@@ -139,8 +139,8 @@ func (m *Maintainer) syncHeaderToMap(latestBlock *big.Int) error {
 		return nil
 	}
 	// synced height check
-	syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-	//syncedHeight, err := mapprotocol.Get2MapByLight()
+	syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	//syncedHeight, err := m.Map.Get2MapByLight()
 	if err != nil {
 		m.Log.Error("Get current synced Height failed", "err", err)
 		return err
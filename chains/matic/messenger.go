@@ -10,12 +10,9 @@ import (
 	"github.com/mapprotocol/compass/pkg/util"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/constant"
-	"github.com/mapprotocol/compass/internal/matic"
-	"github.com/mapprotocol/compass/internal/tx"
-	"github.com/mapprotocol/compass/mapprotocol"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/msg"
 )
 
@@ -72,7 +69,7 @@ func (m *Messenger) sync() error {
 				m.Metrics.LatestKnownBlock.Set(float64(latestBlock.Int64()))
 			}
 
-			left, right, err := mapprotocol.Get2MapVerifyRange(m.Cfg.Id)
+			left, right, err := m.Map.Get2MapVerifyRange(m.Cfg.Id)
 			if err != nil {
 				m.Log.Warn("Get2MapVerifyRange failed", "err", err)
 			}
@@ -137,6 +134,18 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 	}
 
 	m.Log.Debug("event", "latestBlock ", latestBlock, " logs ", len(logs))
+	if !m.Cfg.SyncToMap || len(logs) == 0 {
+		return 0, nil
+	}
+	pb, err := m.ProofBuilder()
+	if err != nil {
+		return 0, err
+	}
+	// when syncToMap we need to assemble a tx proof
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
+	if err != nil {
+		return 0, err
+	}
 	count := 0
 	// read through the log events and handle their deposit event if handler is recognized
 	for _, log := range logs {
@@ -144,48 +153,22 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 		var message msg.Message
 		// getOrderId
 		orderId := log.Data[:32]
-		if m.Cfg.SyncToMap {
-			method := m.GetMethod(log.Topics[0])
-			// when syncToMap we need to assemble a tx proof
-			txsHash, err := tx.GetTxsHashByBlockNumber(m.Conn.Client(), latestBlock)
-			if err != nil {
-				return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-			}
-			receipts, err := tx.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-			if err != nil {
-				return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-			}
-
-			headers := make([]*types.Header, mapprotocol.ConfirmsOfMatic.Int64())
-			for i := 0; i < int(mapprotocol.ConfirmsOfMatic.Int64()); i++ {
-				headerHeight := new(big.Int).Add(latestBlock, new(big.Int).SetInt64(int64(i)))
-				tmp, err := m.Conn.Client().HeaderByNumber(context.Background(), headerHeight)
-				if err != nil {
-					return 0, fmt.Errorf("getHeader failed, err is %v", err)
-				}
-				headers[i] = tmp
-			}
-
-			mHeaders := make([]matic.BlockHeader, 0, len(headers))
-			for _, h := range headers {
-				mHeaders = append(mHeaders, matic.ConvertHeader(h))
-			}
-
-			payload, err := matic.AssembleProof(mHeaders, log, m.Cfg.Id, receipts, method)
-			if err != nil {
-				return 0, fmt.Errorf("unable to Parse Log: %w", err)
-			}
+		proof, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, iproof.ErrInvalidProof) {
+			continue
+		} else if err != nil {
+			return 0, err
+		}
 
-			msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
-			message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
+		msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash}
+		message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
 
-			m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index, "orderId", ethcommon.Bytes2Hex(orderId))
-			err = m.Router.Send(message)
-			if err != nil {
-				m.Log.Error("subscription error: failed to route message", "err", err)
-			}
-			count++
+		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index, "orderId", ethcommon.Bytes2Hex(orderId))
+		err = m.Router.Send(message)
+		if err != nil {
+			m.Log.Error("subscription error: failed to route message", "err", err)
 		}
+		count++
 	}
 
 	return count, nil
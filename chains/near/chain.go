@@ -1,7 +1,9 @@
 package near
 
 import (
+	"context"
 	"math/big"
+	"strings"
 
 	"github.com/mapprotocol/compass/pkg/redis"
 	"github.com/pkg/errors"
@@ -13,6 +15,7 @@ import (
 	"github.com/mapprotocol/compass/chains"
 	connection "github.com/mapprotocol/compass/connections/near"
 	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/journal"
 	"github.com/mapprotocol/compass/keystore"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
@@ -64,14 +67,42 @@ func setupBlockstore(cfg *Config, kp *key.KeyPair, role mapprotocol.Role) (*bloc
 	return bs, nil
 }
 
+func init() {
+	chains.Register(chains.Near, &chains.Type{
+		Initialize: initialize,
+		ParseConfig: func(chainCfg *core.ChainConfig) error {
+			_, err := parseChainConfig(chainCfg)
+			return err
+		},
+		Proof: monitorProof,
+	})
+}
+
+func initialize(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	c, err := InitializeChain(chainCfg, logger, sysErr, m, role, mc)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (*Chain, error) {
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (*Chain, error) {
 	cfg, err := parseChainConfig(chainCfg)
 	if err != nil {
 		return nil, err
 	}
 
-	kp, err := keystore.NearKeyPairFrom(chainCfg.Network, cfg.keystorePath, cfg.from)
+	// the key imported to the keystore is taken before the credentials of near-cli, the access keys of the pool
+	// are imported as well
+	others := make([]string, 0, len(cfg.accessKeys))
+	for _, k := range cfg.accessKeys {
+		if isPublicKey(k) {
+			others = append(others, k)
+		}
+	}
+	kp, err := keystore.NearKeyPair(chainCfg.Network, chainCfg.KeystorePath, cfg.keystorePath, cfg.from, "", others...)
 	if err != nil {
 		return nil, err
 	}
@@ -81,6 +112,11 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 		return nil, err
 	}
 
+	jn, err := journal.NewJournal(cfg.journalPath, cfg.id, kp.PublicKey.ToPublicKey().Hash(), role)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := make(chan int)
 	conn := connection.NewConnection(cfg.endpoint, cfg.http, &kp, logger, cfg.gasLimit, cfg.maxGasPrice,
 		cfg.gasMultiplier, cfg.egsApiKey, cfg.egsSpeed)
@@ -99,9 +135,12 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 
 	// simplified a little bit
 	var listen chains.Listener
-	cs := NewCommonListen(conn, cfg, logger, stop, sysErr, m, bs)
+	cs := NewCommonListen(conn, cfg, logger, stop, sysErr, m, bs, mc)
 	if role == mapprotocol.RoleOfMessenger {
-		redis.Init(cfg.redisUrl)
+		// without redis the blocks are walked by the streamer instead of near-lake-s3
+		if cfg.redisUrl != "" {
+			redis.Init(cfg.redisUrl)
+		}
 		// verify range
 		fn := mapprotocol.Map2NearVerifyRange(cfg.lightNode, conn.Client())
 		left, right, err := fn()
@@ -109,7 +148,7 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "near get init verifyHeight failed")
 		}
 		logger.Info("Map2Near Current verify range", "left", left, "right", right, "lightNode", cfg.lightNode)
-		mapprotocol.Map2OtherVerifyRange[cfg.id] = fn
+		mc.SetMap2OtherVerifyRange(cfg.id, fn)
 		listen = NewMessenger(cs)
 	} else if role == mapprotocol.RoleOfMaintainer {
 		fn := mapprotocol.Map2NearHeight(cfg.lightNode, conn.Client())
@@ -118,11 +157,28 @@ func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr cha
 			return nil, errors.Wrap(err, "near get init headerHeight failed")
 		}
 		logger.Info("Map2Near Current situation", "height", height, "lightNode", cfg.lightNode)
-		mapprotocol.SyncOtherMap[cfg.id] = height
-		mapprotocol.Map2OtherHeight[cfg.id] = fn
+		mc.SetMap2OtherHeight(cfg.id, height, fn)
 		listen = NewMaintainer(cs)
 	}
-	writer := NewWriter(conn, cfg, logger, stop, sysErr, m)
+	kps := []key.KeyPair{kp}
+	for _, k := range cfg.accessKeys {
+		var akp key.KeyPair
+		if isPublicKey(k) {
+			akp, err = keystore.NearKeyPair(chainCfg.Network, chainCfg.KeystorePath, cfg.keystorePath, cfg.from, k)
+		} else {
+			logger.Warn("Access key read from plaintext credentials, import it to the keystore", "file", k)
+			akp, err = keystore.NearKeyPairFromFile(k, cfg.from)
+		}
+		if err != nil {
+			return nil, errors.Wrapf(err, "load access key %s failed", k)
+		}
+		kps = append(kps, akp)
+	}
+	keys, err := newKeyPool(context.Background(), conn.Client(), cfg.from, kps)
+	if err != nil {
+		return nil, err
+	}
+	writer := NewWriter(conn, cfg, logger, stop, sysErr, m, jn, keys)
 
 	return &Chain{
 		cfg:    chainCfg,
@@ -173,7 +229,7 @@ func (c *Chain) Stop() {
 	}
 }
 
-// EthClient return EthClient for global map connection
+// EthClient return EthClient for the map connection
 func (c *Chain) EthClient() *nearclient.Client {
 	return c.conn.Client()
 }
@@ -182,3 +238,8 @@ func (c *Chain) EthClient() *nearclient.Client {
 func (c *Chain) Conn() Connection {
 	return c.conn
 }
+
+// isPublicKey tells the public keys of the keystore from the credentials files in the accessKeys option
+func isPublicKey(k string) bool {
+	return strings.HasPrefix(k, keystore.NearKeyType+":")
+}
@@ -1,7 +1,7 @@
 package near
 
 import (
-	"errors"
+	"context"
 	"math/big"
 	"time"
 
@@ -9,6 +9,10 @@ import (
 	"github.com/ChainSafe/log15"
 	"github.com/mapprotocol/compass/blockstore"
 	"github.com/mapprotocol/compass/chains"
+	"github.com/mapprotocol/compass/internal/near"
+	"github.com/mapprotocol/compass/mapprotocol"
+	nearclient "github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/pkg/errors"
 )
 
 var (
@@ -20,6 +24,7 @@ var (
 type CommonListen struct {
 	cfg                Config
 	conn               Connection
+	mc                 *mapprotocol.MapContext
 	log                log15.Logger
 	router             chains.Router
 	stop               <-chan int
@@ -29,14 +34,16 @@ type CommonListen struct {
 	metrics            *metrics.ChainMetrics
 	blockConfirmations *big.Int
 	blockStore         blockstore.Blockstorer
+	producers          *near.BlockProducerTracker
 }
 
 // NewCommonListen creates and returns a listener
 func NewCommonListen(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error,
-	m *metrics.ChainMetrics, bs blockstore.Blockstorer) *CommonListen {
+	m *metrics.ChainMetrics, bs blockstore.Blockstorer, mc *mapprotocol.MapContext) *CommonListen {
 	return &CommonListen{
 		cfg:                *cfg,
 		conn:               conn,
+		mc:                 mc,
 		log:                log,
 		stop:               stop,
 		sysErr:             sysErr,
@@ -45,9 +52,26 @@ func NewCommonListen(conn Connection, cfg *Config, log log15.Logger, stop <-chan
 		blockConfirmations: cfg.blockConfirmations,
 		msgCh:              make(chan struct{}),
 		blockStore:         bs,
+		producers:          near.NewBlockProducerTracker(conn.Client()),
 	}
 }
 
+// verifyLightBlock checks the light client block against the tracked block producers, bootstrapping them at the
+// height of the light client on map first
+func (c *CommonListen) verifyLightBlock(blk *nearclient.LightClientBlockView) error {
+	if !c.producers.Bootstrapped() {
+		height, err := c.mc.Get2MapHeight(c.cfg.id)
+		if err != nil {
+			return err
+		}
+		if err = c.producers.Bootstrap(context.Background(), height.Uint64()); err != nil {
+			return errors.Wrap(err, "bootstrap block producers failed")
+		}
+		c.log.Info("Block producers bootstrapped", "height", height)
+	}
+	return c.producers.Verify(context.Background(), blk)
+}
+
 func (c *CommonListen) SetRouter(r chains.Router) {
 	c.router = r
 }
@@ -16,7 +16,9 @@ import (
 	gconfig "github.com/mapprotocol/compass/config"
 	"github.com/mapprotocol/compass/connections/ethereum/egs"
 	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/near"
 	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/near-api-go/pkg/types"
 )
 
 const DefaultGasLimit = 6721975
@@ -40,6 +42,10 @@ var (
 	SyncIDList            = "syncIdList"
 	LightNode             = "lightnode"
 	Event                 = "event"
+	AccessKeysOpt         = "accessKeys"
+	GasOpt                = "gas"
+	DepositOpt            = "deposit"
+	GasEstimateOpt        = "gasEstimate"
 )
 
 // Config encapsulates all necessary parameters in ethereum compatible forms
@@ -50,6 +56,7 @@ type Config struct {
 	from               string      // address of key to use
 	keystorePath       string      // Location of keyfiles
 	blockstorePath     string
+	journalPath        string
 	freshStart         bool // Disables loading from blockstore at start
 	mcsContract        string
 	gasLimit           *big.Int
@@ -66,6 +73,10 @@ type Config struct {
 	lightNode          string        // the lightnode to sync header
 	redisUrl           string
 	events             []string
+	accessKeys         []string // public keys of the additional access keys of the account, or their credentials files
+	gas                map[string]types.Gas
+	deposit            map[string]types.Balance
+	gasMargin          int64 // percent over the gas burnt by the recent calls, the estimation is disabled when negative
 	skipError          bool
 	HooksUrl           string
 	WaterLine          string
@@ -80,6 +91,7 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		from:               chainCfg.From,
 		keystorePath:       chainCfg.NearKeystorePath,
 		blockstorePath:     chainCfg.BlockstorePath,
+		journalPath:        chainCfg.JournalPath,
 		freshStart:         chainCfg.FreshStart,
 		endpoint:           chainCfg.Endpoint,
 		mcsContract:        "",
@@ -92,6 +104,7 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		egsApiKey:          "",
 		egsSpeed:           "",
 		redisUrl:           "",
+		gasMargin:          -1,
 		skipError:          chainCfg.SkipError,
 		WaterLine:          "",
 		ChangeInterval:     "",
@@ -239,6 +252,51 @@ func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		delete(chainCfg.Opts, Event)
 	}
 
+	if v, ok := chainCfg.Opts[AccessKeysOpt]; ok && v != "" {
+		config.accessKeys = strings.Split(v, "|")
+		delete(chainCfg.Opts, AccessKeysOpt)
+	}
+
+	if v, ok := chainCfg.Opts[GasOpt]; ok && v != "" {
+		tgas := make(map[string]uint64)
+		if err := json.Unmarshal([]byte(v), &tgas); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", GasOpt, err)
+		}
+		config.gas = make(map[string]types.Gas, len(tgas))
+		for method, g := range tgas {
+			if g == 0 || types.Gas(g) > near.NewFunctionCallGas/Tgas {
+				return nil, fmt.Errorf("%s of %s should be in (0, %d] Tgas", GasOpt, method, near.NewFunctionCallGas/Tgas)
+			}
+			config.gas[method] = types.Gas(g) * Tgas
+		}
+		delete(chainCfg.Opts, GasOpt)
+	}
+
+	if v, ok := chainCfg.Opts[DepositOpt]; ok && v != "" {
+		deposits := make(map[string]string)
+		if err := json.Unmarshal([]byte(v), &deposits); err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", DepositOpt, err)
+		}
+		config.deposit = make(map[string]types.Balance, len(deposits))
+		for method, d := range deposits {
+			b, err := types.BalanceFromString(d)
+			if err != nil {
+				return nil, fmt.Errorf("unable to parse %s of %s: %w", DepositOpt, method, err)
+			}
+			config.deposit[method] = b
+		}
+		delete(chainCfg.Opts, DepositOpt)
+	}
+
+	if v, ok := chainCfg.Opts[GasEstimateOpt]; ok && v != "" {
+		margin, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || margin < 0 {
+			return nil, fmt.Errorf("unable to parse %s", GasEstimateOpt)
+		}
+		config.gasMargin = margin
+		delete(chainCfg.Opts, GasEstimateOpt)
+	}
+
 	if len(chainCfg.Opts) != 0 {
 		return nil, fmt.Errorf("unknown Opts Encountered: %#v", chainCfg.Opts)
 	}
@@ -0,0 +1,113 @@
+package near
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/pkg/errors"
+)
+
+// eventKind is an event of the mcs, logged as `<name>: <json of the event>` along with `<hash><abi encoded event>`
+type eventKind struct {
+	name   string
+	hash   string
+	method string // the method of the mcs on map receiving the event
+}
+
+var eventKinds = []eventKind{
+	{name: mapprotocol.TransferOut, hash: mapprotocol.HashOfTransferOut, method: mapprotocol.MethodOfTransferIn},
+	{name: mapprotocol.DepositOut, hash: mapprotocol.HashOfDepositOut, method: mapprotocol.MethodOfDepositIn},
+	{name: mapprotocol.SwapOut, hash: mapprotocol.HashOfSwapOut, method: mapprotocol.MethodOfSwapIn},
+}
+
+// McsEvent is the event of the mcs decoded from the logs of an outcome
+type McsEvent struct {
+	Kind      string
+	Method    string
+	FromChain msg.ChainId
+	ToChain   msg.ChainId
+	OrderId   common.Hash
+}
+
+// chainID is a chain id in the json of the event, the contract writes it either as a number or as a string
+type chainID msg.ChainId
+
+func (c *chainID) UnmarshalJSON(data []byte) error {
+	id, err := strconv.ParseUint(string(bytes.Trim(data, `"`)), 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid chain id %s", data)
+	}
+	*c = chainID(id)
+	return nil
+}
+
+type eventJSON struct {
+	FromChain *chainID `json:"from_chain"`
+	ToChain   *chainID `json:"to_chain"`
+	OrderId   string   `json:"order_id"`
+}
+
+// DecodeMcsEvent decodes the event of the mcs wherever its logs are in the outcome, the event has to be sent from the
+// chain `from` to another chain
+func DecodeMcsEvent(logs []string, from msg.ChainId) (*McsEvent, error) {
+	var (
+		kind   *eventKind
+		hashed *eventKind
+		body   string
+	)
+	for _, l := range logs {
+		for i := range eventKinds {
+			k := &eventKinds[i]
+			if strings.HasPrefix(l, k.hash) {
+				if hashed != nil && hashed != k {
+					return nil, fmt.Errorf("both %s and %s are logged", hashed.name, k.name)
+				}
+				hashed = k
+			} else if strings.HasPrefix(l, k.name+":") {
+				if kind != nil {
+					return nil, fmt.Errorf("more than one event is logged")
+				}
+				kind, body = k, strings.TrimPrefix(l, k.name+":")
+			}
+		}
+	}
+	if kind == nil {
+		return nil, errors.New("no event is logged")
+	}
+	if hashed != nil && hashed != kind {
+		return nil, fmt.Errorf("%s is logged with the hash of %s", kind.name, hashed.name)
+	}
+
+	ev := eventJSON{}
+	if err := json.Unmarshal([]byte(strings.TrimSpace(body)), &ev); err != nil {
+		return nil, errors.Wrapf(err, "decode %s failed", kind.name)
+	}
+	if ev.FromChain == nil || ev.ToChain == nil {
+		return nil, fmt.Errorf("%s without chain ids", kind.name)
+	}
+	if msg.ChainId(*ev.FromChain) != from {
+		return nil, fmt.Errorf("%s from chain %d, expect %d", kind.name, *ev.FromChain, from)
+	}
+	if *ev.ToChain == 0 || *ev.ToChain == *ev.FromChain {
+		return nil, fmt.Errorf("%s to invalid chain %d", kind.name, *ev.ToChain)
+	}
+	orderId, err := hex.DecodeString(strings.TrimPrefix(ev.OrderId, "0x"))
+	if err != nil || len(orderId) != common.HashLength {
+		return nil, fmt.Errorf("%s with invalid order id %q", kind.name, ev.OrderId)
+	}
+
+	return &McsEvent{
+		Kind:      kind.name,
+		Method:    kind.method,
+		FromChain: msg.ChainId(*ev.FromChain),
+		ToChain:   msg.ChainId(*ev.ToChain),
+		OrderId:   common.BytesToHash(orderId),
+	}, nil
+}
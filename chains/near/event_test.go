@@ -0,0 +1,97 @@
+package near
+
+import (
+	"testing"
+
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+const testOrderId = "0x9f7a3d5c3a2b1e0f00112233445566778899aabbccddeeff0011223344556677"
+
+func TestDecodeMcsEvent(t *testing.T) {
+	var from msg.ChainId = 1313161555
+	tests := []struct {
+		name   string
+		logs   []string
+		method string
+		to     msg.ChainId
+		err    bool
+	}{
+		{
+			name: "transfer out",
+			logs: []string{
+				`transfer out: {"from_chain":"1313161555","to_chain":"212","order_id":"` + testOrderId + `"}`,
+				mapprotocol.HashOfTransferOut + "00ab",
+			},
+			method: mapprotocol.MethodOfTransferIn,
+			to:     212,
+		},
+		{
+			name: "deposit out after other logs",
+			logs: []string{
+				"Transfer 10 from alice.testnet to mos.map007.testnet",
+				mapprotocol.HashOfDepositOut + "00ab",
+				`deposit out: {"from_chain":1313161555,"to_chain":22776,"order_id":"` + testOrderId[2:] + `"}`,
+			},
+			method: mapprotocol.MethodOfDepositIn,
+			to:     22776,
+		},
+		{
+			name:   "swap out without the hash log",
+			logs:   []string{`swap out: {"from_chain":"1313161555","to_chain":"56","order_id":"` + testOrderId + `"}`},
+			method: mapprotocol.MethodOfSwapIn,
+			to:     56,
+		},
+		{
+			name: "hash of another kind",
+			logs: []string{
+				`transfer out: {"from_chain":"1313161555","to_chain":"212","order_id":"` + testOrderId + `"}`,
+				mapprotocol.HashOfSwapOut + "00ab",
+			},
+			err: true,
+		},
+		{
+			name: "from another chain",
+			logs: []string{`transfer out: {"from_chain":"56","to_chain":"212","order_id":"` + testOrderId + `"}`},
+			err:  true,
+		},
+		{
+			name: "to the chain itself",
+			logs: []string{`transfer out: {"from_chain":"1313161555","to_chain":"1313161555","order_id":"` + testOrderId + `"}`},
+			err:  true,
+		},
+		{
+			name: "without to chain",
+			logs: []string{`transfer out: {"from_chain":"1313161555","order_id":"` + testOrderId + `"}`},
+			err:  true,
+		},
+		{
+			name: "short order id",
+			logs: []string{`transfer out: {"from_chain":"1313161555","to_chain":"212","order_id":"0x01"}`},
+			err:  true,
+		},
+		{
+			name: "no event",
+			logs: []string{mapprotocol.HashOfTransferOut + "00ab"},
+			err:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ev, err := DecodeMcsEvent(tt.logs, from)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("decoded %+v", ev)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if ev.Method != tt.method || ev.FromChain != from || ev.ToChain != tt.to || ev.OrderId.Hex() != testOrderId {
+				t.Fatalf("decoded %+v", ev)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package near
+
+import (
+	"encoding/json"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/mapprotocol/compass/internal/near"
+	"github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+)
+
+// Tgas is the unit of the gas in the gas option
+const Tgas types.Gas = 1000000000000
+
+// gasWindow is the number of the recent calls of a method whose burnt gas is kept for the estimation
+const gasWindow = 10
+
+// ErrOfExceededGas is the failure of a call running out of the gas attached
+const ErrOfExceededGas = "Exceeded the prepaid gas"
+
+// callbackMethods are the methods of the mcs calling other contracts with callbacks. The gas attached is prepaid for
+// the receipts they create, and a callback running out of it fails after the call itself has succeeded, so they
+// always attach the gas configured.
+var callbackMethods = map[string]bool{
+	MethodOfTransferIn: true,
+	MethodOfSwapIn:     true,
+}
+
+// gasPolicy decides the gas and the deposit attached to the calls of the methods. When the estimation is enabled
+// the gas of a method is the most gas burnt by its recent calls plus the margin, limited to the gas configured.
+// Near can not dry run a change call, so the first call of a method and the one after running out of gas attach
+// the gas configured. The burnt gas tells the gas needed only for the calls executed in a single receipt, the
+// methods with callbacks and the ones seen creating receipts to other contracts are not estimated.
+type gasPolicy struct {
+	gas       map[string]types.Gas
+	deposit   map[string]types.Balance
+	margin    int64 // percent over the gas burnt, the estimation is disabled when negative
+	lock      sync.Mutex
+	burnt     map[string][]types.Gas
+	callbacks map[string]bool // the methods not estimated
+}
+
+func newGasPolicy(cfg *Config) *gasPolicy {
+	return &gasPolicy{
+		gas:       cfg.gas,
+		deposit:   cfg.deposit,
+		margin:    cfg.gasMargin,
+		burnt:     make(map[string][]types.Gas),
+		callbacks: make(map[string]bool),
+	}
+}
+
+// defaultDeposit returns the deposit of the method when it is not configured
+func defaultDeposit(method string) types.Balance {
+	if method == MethodOfTransferIn || method == MethodOfSwapIn || method == MethodOfVerifyReceiptProof {
+		b, _ := types.BalanceFromString(near.Deposit)
+		return b
+	}
+	return types.Balance{}
+}
+
+// attach returns the gas and the deposit of the call of the method
+func (g *gasPolicy) attach(method string) (types.Gas, types.Balance) {
+	limit, ok := g.gas[method]
+	if !ok {
+		limit = near.NewFunctionCallGas
+	}
+	deposit, ok := g.deposit[method]
+	if !ok {
+		deposit = defaultDeposit(method)
+	}
+	if g.margin < 0 || callbackMethods[method] {
+		return limit, deposit
+	}
+
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if g.callbacks[method] {
+		return limit, deposit
+	}
+	var most types.Gas
+	for _, b := range g.burnt[method] {
+		if b > most {
+			most = b
+		}
+	}
+	if most == 0 {
+		return limit, deposit
+	}
+	if est := most + most*types.Gas(g.margin)/100; est < limit {
+		return est, deposit
+	}
+	return limit, deposit
+}
+
+// observe keeps the gas burnt by the call of the method. A call running out of gas in any of its receipts discards
+// the burnt gas kept, a call creating receipts to other contracts stops the estimation of the method.
+func (g *gasPolicy) observe(method string, burnt types.Gas, res *client.FinalExecutionOutcomeView) {
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	if exceededGas(res) {
+		delete(g.burnt, method)
+		return
+	}
+	if crossContract(res) {
+		g.callbacks[method] = true
+		delete(g.burnt, method)
+		return
+	}
+	if len(res.Status.Failure) != 0 {
+		return
+	}
+	kept := append(g.burnt[method], burnt)
+	if len(kept) > gasWindow {
+		kept = kept[len(kept)-gasWindow:]
+	}
+	g.burnt[method] = kept
+}
+
+// exceededGas reports whether the tx or any of its receipts ran out of the gas attached, the failure of a receipt
+// after the first is not the failure of the tx
+func exceededGas(res *client.FinalExecutionOutcomeView) bool {
+	if strings.Contains(string(res.Status.Failure), ErrOfExceededGas) {
+		return true
+	}
+	for _, o := range res.ReceiptsOutcome {
+		if strings.Contains(string(o.Outcome.Status.Failure), ErrOfExceededGas) {
+			return true
+		}
+	}
+	return false
+}
+
+// crossContract reports whether the call is executed by more than one receipt of the contracts, the refunds to the
+// signer are not counted
+func crossContract(res *client.FinalExecutionOutcomeView) bool {
+	n := 0
+	for _, o := range res.ReceiptsOutcome {
+		if o.Outcome.ExecutorID != res.Transaction.SignerID {
+			n++
+		}
+	}
+	return n > 1
+}
+
+// gasReport is the gas and the deposit spent by a tx
+type gasReport struct {
+	GasAttached     types.Gas
+	GasBurnt        types.Gas // burnt by the receipts, paid from the gas attached
+	TokensBurnt     *big.Int  // burnt by the tx and the receipts
+	Deposit         *big.Int
+	DepositRefunded *big.Int
+}
+
+// newGasReport sums the outcomes of the tx. The deposit is refunded by the system when the call fails, otherwise
+// the contract refunds by transferring to the signer, the transfers of the system refunding the gas left are not
+// counted. The receipts are needed only to find the refunds of the contract
+func newGasReport(gas types.Gas, deposit types.Balance, res *client.FinalExecutionOutcomeView, receipts []client.ReceiptView) *gasReport {
+	r := &gasReport{
+		GasAttached:     gas,
+		TokensBurnt:     balanceToBig(res.TransactionOutcome.Outcome.TokensBurnt),
+		Deposit:         balanceToBig(deposit),
+		DepositRefunded: new(big.Int),
+	}
+	for _, o := range res.ReceiptsOutcome {
+		r.GasBurnt += o.Outcome.GasBurnt
+		r.TokensBurnt.Add(r.TokensBurnt, balanceToBig(o.Outcome.TokensBurnt))
+	}
+	if len(res.Status.Failure) != 0 {
+		r.DepositRefunded.Set(r.Deposit)
+		return r
+	}
+	for _, rc := range receipts {
+		if rc.PredecessorID == "system" || rc.ReceiverID != res.Transaction.SignerID {
+			continue
+		}
+		var receipt struct {
+			Action struct {
+				Actions []struct {
+					Transfer *struct {
+						Deposit types.Balance `json:"deposit"`
+					} `json:"Transfer"`
+				} `json:"actions"`
+			} `json:"Action"`
+		}
+		if err := json.Unmarshal(rc.Receipt, &receipt); err != nil {
+			continue
+		}
+		for _, a := range receipt.Action.Actions {
+			if a.Transfer != nil {
+				r.DepositRefunded.Add(r.DepositRefunded, balanceToBig(a.Transfer.Deposit))
+			}
+		}
+	}
+	return r
+}
+
+func balanceToBig(b types.Balance) *big.Int {
+	ret, _ := new(big.Int).SetString(b.String(), 10)
+	return ret
+}
@@ -0,0 +1,135 @@
+package near
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/mapprotocol/compass/internal/near"
+	"github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+)
+
+func TestGasPolicy(t *testing.T) {
+	deposit, _ := types.BalanceFromString("0.1")
+	cfg := &Config{
+		gas:       map[string]types.Gas{MethodOfTransferIn: 200 * Tgas},
+		deposit:   map[string]types.Balance{MethodOfVerifyReceiptProof: {}, MethodOfTransferIn: deposit},
+		gasMargin: -1,
+	}
+	g := newGasPolicy(cfg)
+	if gas, d := g.attach(MethodOfTransferIn); gas != 200*Tgas || d != deposit {
+		t.Fatalf("transfer in %d %s", gas, d)
+	}
+	if gas, d := g.attach(MethodOfVerifyReceiptProof); gas != near.NewFunctionCallGas || d != (types.Balance{}) {
+		t.Fatalf("verify %d %s", gas, d)
+	}
+	if _, d := g.attach(MethodOfSwapIn); d != defaultDeposit(MethodOfSwapIn) || d == (types.Balance{}) {
+		t.Fatalf("swap in deposit %s", d)
+	}
+	g.observe(MethodOfVerifyReceiptProof, 50*Tgas, testResult(""))
+	if gas, _ := g.attach(MethodOfVerifyReceiptProof); gas != near.NewFunctionCallGas {
+		t.Fatalf("estimated %d with the estimation disabled", gas)
+	}
+
+	cfg.gas[MethodOfVerifyReceiptProof] = 200 * Tgas
+	cfg.gasMargin = 20
+	g = newGasPolicy(cfg)
+	g.observe(MethodOfVerifyReceiptProof, 50*Tgas, testResult(""))
+	g.observe(MethodOfVerifyReceiptProof, 40*Tgas, testResult(""))
+	g.observe(MethodOfVerifyReceiptProof, 90*Tgas, testResult(`{"ActionError":{}}`))
+	if gas, _ := g.attach(MethodOfVerifyReceiptProof); gas != 60*Tgas {
+		t.Fatalf("estimated %d", gas)
+	}
+	g.observe(MethodOfVerifyReceiptProof, 180*Tgas, testResult(""))
+	if gas, _ := g.attach(MethodOfVerifyReceiptProof); gas != 200*Tgas {
+		t.Fatalf("estimated %d over the limit", gas)
+	}
+	g.observe(MethodOfVerifyReceiptProof, 60*Tgas, testResult(`{"ActionError":{"kind":{"FunctionCallError":{"ExecutionError":"Exceeded the prepaid gas."}}}}`))
+	if gas, _ := g.attach(MethodOfVerifyReceiptProof); gas != 200*Tgas {
+		t.Fatalf("estimated %d after running out of gas", gas)
+	}
+
+	// a receipt after the first running out of gas does not fail the tx
+	g.observe(MethodOfVerifyReceiptProof, 50*Tgas, testResult(""))
+	exceeded := testResult("")
+	exceeded.ReceiptsOutcome[0].Outcome.Status.Failure = []byte(`{"ActionError":{"kind":{"FunctionCallError":{"ExecutionError":"Exceeded the prepaid gas."}}}}`)
+	g.observe(MethodOfVerifyReceiptProof, 60*Tgas, exceeded)
+	if gas, _ := g.attach(MethodOfVerifyReceiptProof); gas != 200*Tgas {
+		t.Fatalf("estimated %d after a receipt ran out of gas", gas)
+	}
+
+	// the methods with callbacks are not estimated
+	g.observe(MethodOfTransferIn, 50*Tgas, testResult(""))
+	if gas, _ := g.attach(MethodOfTransferIn); gas != 200*Tgas {
+		t.Fatalf("estimated %d of the method with callbacks", gas)
+	}
+	g.observe(MethodOfUpdateBlockHeader, 50*Tgas, testResult("", "lightnode.map007.testnet"))
+	if gas, _ := g.attach(MethodOfUpdateBlockHeader); gas != near.NewFunctionCallGas {
+		t.Fatalf("estimated %d of the method calling another contract", gas)
+	}
+	g.observe(MethodOfUpdateBlockHeader, 50*Tgas, testResult(""))
+	if gas, _ := g.attach(MethodOfUpdateBlockHeader); gas != near.NewFunctionCallGas {
+		t.Fatalf("estimated %d of the method after it called another contract", gas)
+	}
+}
+
+// testResult is the result of a call of the mcs executed by a receipt, then by the receipts of the contracts called,
+// and refunded to the relayer
+func testResult(failure string, called ...types.AccountID) *client.FinalExecutionOutcomeView {
+	res := &client.FinalExecutionOutcomeView{
+		Status:      client.TransactionStatus{Failure: []byte(failure)},
+		Transaction: client.SignedTransactionView{SignerID: "relayer.testnet"},
+	}
+	for _, executor := range append([]types.AccountID{"mos.map007.testnet"}, called...) {
+		res.ReceiptsOutcome = append(res.ReceiptsOutcome, client.ExecutionOutcomeWithIdView{
+			Outcome: client.ExecutionOutcomeView{ExecutorID: executor},
+		})
+	}
+	res.ReceiptsOutcome = append(res.ReceiptsOutcome, client.ExecutionOutcomeWithIdView{
+		Outcome: client.ExecutionOutcomeView{ExecutorID: "relayer.testnet"},
+	})
+	return res
+}
+
+func TestGasReport(t *testing.T) {
+	deposit, _ := types.BalanceFromString("0.3")
+	refund, _ := types.BalanceFromString("0.05")
+	res := &client.FinalExecutionOutcomeView{
+		Transaction: client.SignedTransactionView{SignerID: "relayer.testnet"},
+		TransactionOutcome: client.ExecutionOutcomeWithIdView{Outcome: client.ExecutionOutcomeView{
+			GasBurnt: 2 * Tgas, TokensBurnt: types.NEARToYocto(1).Div64(10000),
+		}},
+		ReceiptsOutcome: []client.ExecutionOutcomeWithIdView{
+			{Outcome: client.ExecutionOutcomeView{GasBurnt: 30 * Tgas, TokensBurnt: types.NEARToYocto(1).Div64(1000)}},
+			{Outcome: client.ExecutionOutcomeView{GasBurnt: 5 * Tgas}},
+		},
+	}
+	transfer := func(amount types.Balance) json.RawMessage {
+		raw, _ := json.Marshal(map[string]interface{}{"Action": map[string]interface{}{
+			"actions": []interface{}{map[string]interface{}{"Transfer": map[string]interface{}{"deposit": amount}}},
+		}})
+		return raw
+	}
+	receipts := []client.ReceiptView{
+		{PredecessorID: "mos.map007.testnet", ReceiverID: "relayer.testnet", Receipt: transfer(refund)},
+		// the gas refunded by the system
+		{PredecessorID: "system", ReceiverID: "relayer.testnet", Receipt: transfer(refund)},
+	}
+
+	r := newGasReport(100*Tgas, deposit, res, receipts)
+	if r.GasBurnt != 35*Tgas || r.GasAttached != 100*Tgas {
+		t.Fatalf("gas %+v", r)
+	}
+	if r.TokensBurnt.Cmp(new(big.Int).Mul(big.NewInt(11), new(big.Int).Exp(big.NewInt(10), big.NewInt(20), nil))) != 0 {
+		t.Fatalf("tokens burnt %s", r.TokensBurnt)
+	}
+	if r.DepositRefunded.Cmp(balanceToBig(refund)) != 0 || r.Deposit.Cmp(balanceToBig(deposit)) != 0 {
+		t.Fatalf("deposit %s refunded %s", r.Deposit, r.DepositRefunded)
+	}
+
+	res.Status.Failure = json.RawMessage(`{"ActionError":{}}`)
+	if r = newGasReport(100*Tgas, deposit, res, nil); r.DepositRefunded.Cmp(r.Deposit) != 0 {
+		t.Fatalf("failed call refunded %s", r.DepositRefunded)
+	}
+}
@@ -0,0 +1,79 @@
+package near
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/msg"
+)
+
+// newEntry builds the journal entry of the message, tx fields are filled in sendTx
+func newEntry(m msg.Message) *journal.Entry {
+	e := &journal.Entry{
+		Source:      m.Source,
+		Destination: m.Destination,
+		Type:        m.Type,
+	}
+	if m.Type == msg.SwapWithMapProof {
+		if orderId, ok := m.Payload[1].([]byte); ok {
+			e.OrderId = "0x" + common.Bytes2Hex(orderId)
+		}
+		if len(m.Payload) > 3 {
+			e.SrcHash = fmt.Sprintf("%v", m.Payload[3])
+		}
+	}
+	return e
+}
+
+// record writes the entry with the status to journal, failing to write journal does not block the tx
+func (w *writer) record(e *journal.Entry, status journal.Status, err error) {
+	if e == nil {
+		return
+	}
+	e.Status = status
+	e.Time = time.Now()
+	e.Error = ""
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if errr := w.journal.Append(e); errr != nil {
+		w.log.Warn("Failed to write journal", "tx", e.TxHash, "status", status, "err", errr)
+	}
+}
+
+// reconcile checks the unfinished journal entries left by the last run, near txs are sent synchronously,
+// so only the ones interrupted before the result was known are left. Swap entries are resolved by the order id,
+// the others are marked as dropped since the message will be replayed from blockstore
+func (w *writer) reconcile() error {
+	entries, err := w.journal.Unfinished()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	w.log.Info("Reconciling unfinished journal entries", "count", len(entries))
+
+	for _, e := range entries {
+		if e.OrderId == "" || e.Method == MethodOfVerifyReceiptProof {
+			w.record(e, journal.StatusDropped, errors.New("interrupted before the tx result was known"))
+			w.log.Info("Journal entry reconciled", "id", e.Id, "method", e.Method, "status", e.Status)
+			continue
+		}
+		exist, err := w.checkOrderId(w.cfg.mcsContract, common.FromHex(e.OrderId))
+		if err != nil {
+			w.log.Warn("Journal entry reconcile failed", "id", e.Id, "orderId", e.OrderId, "err", err)
+			continue
+		}
+		if exist {
+			w.record(e, journal.StatusSuccess, nil)
+		} else {
+			w.record(e, journal.StatusDropped, errors.New("order id is not used"))
+		}
+		w.log.Info("Journal entry reconciled", "id", e.Id, "orderId", e.OrderId, "status", e.Status)
+	}
+	return nil
+}
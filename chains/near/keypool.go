@@ -0,0 +1,156 @@
+package near
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	nearclient "github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/client/block"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+)
+
+// accessKeyViewer is the query of the access keys the pool uses, implemented by the near client
+type accessKeyViewer interface {
+	AccessKeyView(ctx context.Context, accountID types.AccountID, publicKey key.Base58PublicKey,
+		block block.BlockCharacteristic) (nearclient.AccessKeyView, error)
+}
+
+// accessKey is a key of the relayer account held by one tx at a time, the nonce of the last tx it signed is
+// tracked locally so the txs of different keys are sent without waiting for each other
+type accessKey struct {
+	kp    key.KeyPair
+	perm  nearclient.AccessKeyPermission
+	nonce types.Nonce
+	stale bool // the nonce is queried again before the next tx
+}
+
+// allow reports whether the key can sign the function call, function call keys can only call the methods of their
+// receiver without deposit
+func (k *accessKey) allow(receiver types.AccountID, method string, deposit bool) bool {
+	if k.perm.FullAccess {
+		return true
+	}
+	if deposit || k.perm.FunctionCall.ReceiverID != receiver {
+		return false
+	}
+	if len(k.perm.FunctionCall.MethodNames) == 0 {
+		return true
+	}
+	for _, m := range k.perm.FunctionCall.MethodNames {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+// keyPool hands out the access keys of the account, a call waits only when all the keys allowed to sign it are busy
+type keyPool struct {
+	client  accessKeyViewer
+	account types.AccountID
+	lock    sync.Mutex
+	cond    *sync.Cond
+	keys    []*accessKey // function call keys first, to keep the full access keys for the calls only they can sign
+	idle    map[*accessKey]bool
+}
+
+// newKeyPool queries the permission and the nonce of the keys
+func newKeyPool(ctx context.Context, client accessKeyViewer, account types.AccountID, kps []key.KeyPair) (*keyPool, error) {
+	p := &keyPool{
+		client:  client,
+		account: account,
+		idle:    make(map[*accessKey]bool, len(kps)),
+	}
+	p.cond = sync.NewCond(&p.lock)
+	for _, kp := range kps {
+		view, err := client.AccessKeyView(ctx, account, kp.PublicKey, block.FinalityFinal())
+		if err != nil {
+			return nil, fmt.Errorf("get access key %s of %s failed: %w", kp.PublicKey.String(), account, err)
+		}
+		k := &accessKey{kp: kp, perm: view.Permission, nonce: view.Nonce}
+		p.keys = append(p.keys, k)
+		p.idle[k] = true
+	}
+	sort.SliceStable(p.keys, func(i, j int) bool {
+		return !p.keys[i].perm.FullAccess && p.keys[j].perm.FullAccess
+	})
+	return p, nil
+}
+
+// acquire takes an idle key allowed to sign the call, it blocks until one is released
+func (p *keyPool) acquire(receiver types.AccountID, method string, deposit bool) (*accessKey, error) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	for {
+		allowed := false
+		for _, k := range p.keys {
+			if !k.allow(receiver, method, deposit) {
+				continue
+			}
+			allowed = true
+			if p.idle[k] {
+				p.idle[k] = false
+				return k, nil
+			}
+		}
+		if !allowed {
+			return nil, fmt.Errorf("no access key of %s is allowed to call %s of %s (deposit %v)", p.account, method, receiver, deposit)
+		}
+		p.cond.Wait()
+	}
+}
+
+// nextNonce returns the nonce of the next tx of the key, the nonce is queried again after a failed tx since the tx
+// may or may not have been included. The key is held by the caller, the lock is not held over the query.
+func (p *keyPool) nextNonce(ctx context.Context, k *accessKey) (types.Nonce, error) {
+	p.lock.Lock()
+	stale := k.stale
+	p.lock.Unlock()
+	if stale {
+		view, err := p.client.AccessKeyView(ctx, p.account, k.kp.PublicKey, block.FinalityFinal())
+		if err != nil {
+			return 0, fmt.Errorf("get nonce of access key %s failed: %w", k.kp.PublicKey.String(), err)
+		}
+		p.lock.Lock()
+		// near accepts any nonce greater than the last one, the local one is kept when the node is behind
+		if view.Nonce > k.nonce {
+			k.nonce = view.Nonce
+		}
+		k.stale = false
+		p.lock.Unlock()
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return k.nonce + 1, nil
+}
+
+// signers returns the number of the keys allowed to sign the call, which is the number of its txs sent in parallel
+func (p *keyPool) signers(receiver types.AccountID, method string, deposit bool) int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	n := 0
+	for _, k := range p.keys {
+		if k.allow(receiver, method, deposit) {
+			n++
+		}
+	}
+	return n
+}
+
+// release returns the key to the pool with the nonce of the tx it signed, failed tells the tx is not known to be
+// included
+func (p *keyPool) release(k *accessKey, nonce types.Nonce, failed bool) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if nonce > k.nonce {
+		k.nonce = nonce
+	}
+	if failed {
+		k.stale = true
+	}
+	p.idle[k] = true
+	p.cond.Broadcast()
+}
@@ -0,0 +1,111 @@
+package near
+
+import (
+	"context"
+	"crypto/rand"
+	"testing"
+	"time"
+
+	nearclient "github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/client/block"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+)
+
+// accessKeys stands in for the access keys of the account on chain
+type accessKeys map[string]nearclient.AccessKey
+
+func (a accessKeys) AccessKeyView(_ context.Context, _ types.AccountID, publicKey key.Base58PublicKey,
+	_ block.BlockCharacteristic) (nearclient.AccessKeyView, error) {
+	return nearclient.AccessKeyView{AccessKey: a[publicKey.String()]}, nil
+}
+
+func newTestKey(t *testing.T, keys accessKeys, nonce types.Nonce, perm nearclient.AccessKeyPermission) key.KeyPair {
+	kp, err := key.GenerateKeyPair(key.KeyTypeED25519, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys[kp.PublicKey.String()] = nearclient.AccessKey{Nonce: nonce, Permission: perm}
+	return kp
+}
+
+func TestKeyPool(t *testing.T) {
+	const lightNode, mcs = "lightnode.map007.testnet", "mos.map007.testnet"
+	keys := accessKeys{}
+	full := newTestKey(t, keys, 10, nearclient.AccessKeyPermission{FullAccess: true})
+	header := newTestKey(t, keys, 20, nearclient.AccessKeyPermission{FunctionCall: nearclient.FunctionCallPermission{
+		ReceiverID: lightNode, MethodNames: []string{MethodOfUpdateBlockHeader},
+	}})
+	verify := newTestKey(t, keys, 30, nearclient.AccessKeyPermission{FunctionCall: nearclient.FunctionCallPermission{
+		ReceiverID: mcs,
+	}})
+	p, err := newKeyPool(context.Background(), keys, "relayer.testnet", []key.KeyPair{full, header, verify})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// only the full access key signs the calls with deposit in parallel
+	if n := p.signers(mcs, MethodOfTransferIn, true); n != 1 {
+		t.Fatalf("%d signers of the deposit call", n)
+	}
+	if n := p.signers(mcs, MethodOfVerifyReceiptProof, false); n != 2 {
+		t.Fatalf("%d signers of the call without deposit", n)
+	}
+
+	// the function call key of the receiver is taken before the full access key
+	h, err := p.acquire(lightNode, MethodOfUpdateBlockHeader, false)
+	if err != nil || h.kp.PublicKey != header.PublicKey {
+		t.Fatalf("header key %v, %v", h, err)
+	}
+	v, err := p.acquire(mcs, MethodOfVerifyReceiptProof, false)
+	if err != nil || v.kp.PublicKey != verify.PublicKey {
+		t.Fatalf("verify key %v, %v", v, err)
+	}
+	// a call with deposit needs the full access key
+	f, err := p.acquire(mcs, MethodOfTransferIn, true)
+	if err != nil || f.kp.PublicKey != full.PublicKey {
+		t.Fatalf("deposit key %v, %v", f, err)
+	}
+	// no key is allowed, the call fails instead of waiting
+	fc, err := newKeyPool(context.Background(), keys, "relayer.testnet", []key.KeyPair{header, verify})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = fc.acquire(mcs, MethodOfTransferIn, true); err == nil {
+		t.Fatal("deposit call is signed by a function call key")
+	}
+
+	// all the keys allowed are busy
+	taken := make(chan *accessKey)
+	go func() {
+		k, _ := p.acquire(lightNode, MethodOfUpdateBlockHeader, false)
+		taken <- k
+	}()
+	select {
+	case <-taken:
+		t.Fatal("busy key is taken")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	if nonce, _ := p.nextNonce(context.Background(), h); nonce != 21 {
+		t.Fatalf("nonce %d", nonce)
+	}
+	p.release(h, 21, false)
+	if k := <-taken; k != h {
+		t.Fatalf("released key is not taken")
+	}
+	if nonce, _ := p.nextNonce(context.Background(), h); nonce != 22 {
+		t.Fatalf("nonce %d after release", nonce)
+	}
+
+	// the nonce is queried after a failed tx, the local one is kept when the node is behind
+	p.release(v, 31, true)
+	if nonce, _ := p.nextNonce(context.Background(), v); nonce != 32 {
+		t.Fatalf("nonce %d after failed tx", nonce)
+	}
+	v.stale = true
+	keys[verify.PublicKey.String()] = nearclient.AccessKey{Nonce: 40}
+	if nonce, _ := p.nextNonce(context.Background(), v); nonce != 41 {
+		t.Fatalf("nonce %d after the key is used elsewhere", nonce)
+	}
+}
@@ -11,7 +11,6 @@ import (
 	"github.com/mapprotocol/compass/pkg/util"
 
 	"github.com/mapprotocol/compass/internal/near"
-	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/near-api-go/pkg/client/block"
 )
@@ -86,7 +85,7 @@ func (m Maintainer) sync() error {
 
 // syncHeaderToMapChain listen header from current chain to Map chain
 func (m *Maintainer) syncHeaderToMapChain(latestBlock *big.Int) error {
-	height, err := mapprotocol.Get2MapHeight(m.cfg.id)
+	height, err := m.mc.Get2MapHeight(m.cfg.id)
 	if err != nil {
 		return err
 	}
@@ -122,6 +121,11 @@ func (m *Maintainer) syncHeaderToMapChain(latestBlock *big.Int) error {
 
 		number = lightBlock.InnerLite.Height
 
+		if err = m.verifyLightBlock(&lightBlock); err != nil {
+			m.log.Error("failed to verify next light client block", "err", err, "number", number)
+			return err
+		}
+
 		message := msg.NewSyncToMap(m.cfg.id, m.cfg.mapChainID, []interface{}{id, near.Borshify(lightBlock)}, m.msgCh)
 		err = m.router.Send(message)
 		if err != nil {
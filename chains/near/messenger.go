@@ -8,11 +8,9 @@ import (
 	"strings"
 	"time"
 
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/mapprotocol/compass/internal/constant"
 	"github.com/mapprotocol/compass/pkg/util"
 
-	rds "github.com/go-redis/redis/v8"
 	"github.com/mapprotocol/compass/internal/near"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
@@ -25,12 +23,20 @@ import (
 
 type Messenger struct {
 	*CommonListen
+	streamer *NearStreamer // walks the blocks when no redis is configured
+	queue    *redis.Queue  // takes the messages of near-lake-s3 from redis
 }
 
 func NewMessenger(cs *CommonListen) *Messenger {
-	return &Messenger{
+	m := &Messenger{
 		CommonListen: cs,
 	}
+	if cs.cfg.redisUrl == "" {
+		m.streamer = NewNearStreamer(cs.conn.Client(), cs.cfg.mcsContract, cs.cfg.events)
+	} else {
+		m.queue = redis.NewQueue(redis.GetClient(), redis.ListKey)
+	}
+	return m
 }
 
 func (m *Messenger) Sync() error {
@@ -101,7 +107,9 @@ func (m *Messenger) sync() error {
 
 			// Goto next block and reset retry counter
 			currentBlock.Add(currentBlock, big.NewInt(1))
-			time.Sleep(RetryInterval)
+			if m.streamer == nil || big.NewInt(0).Sub(latestBlock, currentBlock).Cmp(m.blockConfirmations) <= 0 {
+				time.Sleep(RetryInterval)
+			}
 		}
 	}
 }
@@ -111,22 +119,24 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 	if !m.cfg.syncToMap {
 		return 0, nil
 	}
-	// querying for logs
+	if m.streamer != nil {
+		return m.streamEventsForBlock(latestBlock)
+	}
+	// the message stays in the processing list of the queue until it is acked
 	ctx := context.Background()
-	cmd := redis.GetClient().RPop(ctx, redis.ListKey)
-	result, err := cmd.Result()
-	if err != nil && !errors.Is(err, rds.Nil) {
-		return 0, errors.Wrap(err, "rPop failed")
+	result, err := m.queue.Next(ctx)
+	if err != nil {
+		return 0, errors.Wrap(err, "take message failed")
 	}
-
-	if err != nil && errors.Is(err, rds.Nil) {
+	if result == "" {
 		return 0, nil
 	}
 
 	data := mapprotocol.StreamerMessage{}
 	err = json.Unmarshal([]byte(result), &data)
 	if err != nil {
-		return 0, errors.Wrap(err, "json marshal failed")
+		m.log.Error("Drop the message failed to decode", "err", err, "message", result)
+		return 0, m.queue.Ack(ctx, result)
 	}
 	target := make([]mapprotocol.IndexerExecutionOutcomeWithReceipt, 0)
 	for _, shard := range data.Shards {
@@ -153,38 +163,74 @@ func (m *Messenger) getEventsForBlock(latestBlock *big.Int) (int, error) {
 	}
 
 	if len(target) == 0 {
+		return 0, m.queue.Ack(ctx, result)
+	}
+
+	if !m.inVerifyRange(new(big.Int).SetUint64(data.Block.Header.Height)) {
+		return 0, m.queue.Ack(ctx, result)
+	}
+
+	ret, err := m.makeMessage(target)
+	// the message is acked only when all the messages routed are handled, it is taken again when failed
+	_ = m.waitUntilMsgHandled(ret)
+	if err != nil {
+		m.log.Error("make message failed, the message will be retried", "err", err)
+		time.Sleep(constant.TxRetryInterval)
 		return 0, nil
 	}
 
-	// check verify range
-	left, right, err := mapprotocol.Get2MapVerifyRange(m.cfg.id)
+	return 0, m.queue.Ack(ctx, result)
+}
+
+// streamEventsForBlock makes the messages of the mcs outcomes of the txs in the block, the block is retried when
+// a message fails to be made
+func (m *Messenger) streamEventsForBlock(height *big.Int) (int, error) {
+	target, err := m.streamer.Outcomes(context.Background(), height.Uint64())
+	if errors.Is(err, ErrUnknownBlock) {
+		m.log.Debug("No block at the height, skip", "height", height)
+		return 0, nil
+	}
 	if err != nil {
-		m.log.Warn("Get2MapVerifyRange failed", "err", err)
+		return 0, err
 	}
-	if left != nil && left.Uint64() != 0 && left.Cmp(new(big.Int).SetUint64(data.Block.Header.Height)) == 1 {
-		m.log.Info("min verify range greater than currentBlock, skip", "currentBlock", data.Block.Header.Height,
-			"minVerify", left, "log", data)
+	if len(target) == 0 {
 		return 0, nil
 	}
-	if right != nil && right.Uint64() != 0 && right.Cmp(new(big.Int).SetUint64(data.Block.Header.Height)) == -1 {
-		m.log.Info("currentBlock less than max verify range", "currentBlock", data.Block.Header.Height, "maxVerify", right, "log", data)
-		time.Sleep(time.Minute)
+	for _, tg := range target {
+		m.log.Info("Event found", "block", height, "receipt", tg.ExecutionOutcome.ID, "log", tg.ExecutionOutcome.Outcome.Logs)
+	}
+
+	if !m.inVerifyRange(height) {
+		return 0, nil
 	}
 
 	ret, err := m.makeMessage(target)
 	if err != nil {
-		m.log.Error("make message failed", "err", err)
-		cmd := redis.GetClient().RPush(context.Background(), redis.ListKey, result)
-		_, err = cmd.Result()
-		if err != nil {
-			m.log.Error("make message failed, retry insert failed", "err", err)
-		}
-		time.Sleep(constant.TxRetryInterval)
+		// the messages routed are handled before the block is retried
+		_ = m.waitUntilMsgHandled(ret)
+		return 0, errors.Wrap(err, "make message failed")
 	}
-
 	return ret, nil
 }
 
+// inVerifyRange checks the block against the range map verifies, the blocks below it are skipped and the ones above
+// it wait for the headers of map to catch up
+func (m *Messenger) inVerifyRange(height *big.Int) bool {
+	left, right, err := m.mc.Get2MapVerifyRange(m.cfg.id)
+	if err != nil {
+		m.log.Warn("Get2MapVerifyRange failed", "err", err)
+	}
+	if left != nil && left.Uint64() != 0 && left.Cmp(height) == 1 {
+		m.log.Info("min verify range greater than currentBlock, skip", "currentBlock", height, "minVerify", left)
+		return false
+	}
+	if right != nil && right.Uint64() != 0 && right.Cmp(height) == -1 {
+		m.log.Info("currentBlock less than max verify range", "currentBlock", height, "maxVerify", right)
+		time.Sleep(time.Minute)
+	}
+	return true
+}
+
 func (m *Messenger) match(log string) bool {
 	for _, e := range m.cfg.events {
 		if strings.HasPrefix(log, e) {
@@ -199,9 +245,14 @@ func (m *Messenger) makeMessage(target []mapprotocol.IndexerExecutionOutcomeWith
 	ret := 0
 	for _, tg := range target {
 		m.log.Debug("makeMessage receive one message", "tg", tg)
+		ev, err := DecodeMcsEvent(tg.ExecutionOutcome.Outcome.Logs, m.cfg.id)
+		if err != nil {
+			m.log.Error("Drop the event failed to decode", "receipt", tg.ExecutionOutcome.ID, "err", err,
+				"logs", tg.ExecutionOutcome.Outcome.Logs)
+			continue
+		}
 		time.Sleep(time.Second * 3)
 		var (
-			err        error
 			retryCount = 0
 			blk        client.LightClientBlockView
 			proof      client.RpcLightClientExecutionProofResponse
@@ -209,7 +260,7 @@ func (m *Messenger) makeMessage(target []mapprotocol.IndexerExecutionOutcomeWith
 		for {
 			retryCount++
 			if retryCount == RetryLimit {
-				return 0, errors.New("make message, retries exceeded")
+				return ret, errors.New("make message, retries exceeded")
 			}
 			blk, err = m.conn.Client().NextLightClientBlock(context.Background(), tg.ExecutionOutcome.BlockHash)
 			if err != nil {
@@ -238,44 +289,29 @@ func (m *Messenger) makeMessage(target []mapprotocol.IndexerExecutionOutcomeWith
 			break
 		}
 
+		if err = m.verifyLightBlock(&blk); err != nil {
+			return ret, errors.Wrap(err, "verify light client block failed")
+		}
+
 		blkBytes := near.Borshify(blk)
 		proofBytes, err := near.BorshifyOutcomeProof(proof)
 		if err != nil {
-			return 0, errors.Wrap(err, "borshifyOutcomeProof failed")
+			return ret, errors.Wrap(err, "borshifyOutcomeProof failed")
 		}
 
 		all, err := mapprotocol.Near.Methods[mapprotocol.MethodOfGetBytes].Inputs.Pack(blkBytes, proofBytes)
 		if err != nil {
-			return 0, errors.Wrap(err, "getBytes pack failed")
+			return ret, errors.Wrap(err, "getBytes pack failed")
 		}
 
-		// get fromChainId and toChainId
-		logs := strings.SplitN(tg.ExecutionOutcome.Outcome.Logs[0], ":", 2)
-		out := near.TransferOut{}
-		err = json.Unmarshal([]byte(logs[1]), &out)
-		if err != nil {
-			return 0, errors.Wrap(err, "logs format failed")
-		}
-
-		method := mapprotocol.MethodOfTransferIn
-		if strings.HasPrefix(tg.ExecutionOutcome.Outcome.Logs[1], mapprotocol.NearOfDepositIn) {
-			method = mapprotocol.MethodOfDepositIn
-		} else if strings.HasPrefix(tg.ExecutionOutcome.Outcome.Logs[1], mapprotocol.NearOfSwapIn) {
-			method = mapprotocol.MethodOfSwapIn
-		}
-		input, err := mapprotocol.Mcs.Pack(method, new(big.Int).SetUint64(uint64(m.cfg.id)), all)
+		input, err := mapprotocol.Mcs.Pack(ev.Method, new(big.Int).SetUint64(uint64(m.cfg.id)), all)
 		//input, err := mapprotocol.LightManger.Pack(mapprotocol.MethodVerifyProofData, new(big.Int).SetUint64(uint64(m.cfg.id)), all)
 		if err != nil {
-			return 0, errors.Wrap(err, "transferIn pack failed")
+			return ret, errors.Wrap(err, "transferIn pack failed")
 		}
 		//fmt.Println("near msc pack hex ------------ ", "0x"+common.Bytes2Hex(input))
 
-		ids := common.HexToHash(out.OrderId)
-		orderId := make([]byte, 0, len(ids))
-		for _, id := range ids {
-			orderId = append(orderId, id)
-		}
-		msgPayload := []interface{}{input, orderId, 0, tg.ExecutionOutcome.Outcome.ReceiptIDs}
+		msgPayload := []interface{}{input, ev.OrderId.Bytes(), 0, tg.ExecutionOutcome.Outcome.ReceiptIDs}
 		message := msg.NewSwapWithProof(m.cfg.id, m.cfg.mapChainID, msgPayload, m.msgCh)
 		err = m.router.Send(message)
 		ret++
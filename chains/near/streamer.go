@@ -0,0 +1,167 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mapprotocol/compass/mapprotocol"
+	nearclient "github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/client/block"
+	"github.com/mapprotocol/near-api-go/pkg/jsonrpc"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+	"github.com/mapprotocol/near-api-go/pkg/types/hash"
+)
+
+// ErrUnknownBlock is returned when no block is produced at the height, near skips the heights of missed blocks
+var ErrUnknownBlock = errors.New("unknown block")
+
+// NearStreamer walks the blocks over json-rpc and returns the receipt outcomes of the mcs with the logs of the
+// events, it takes the place of near-lake-s3 and redis. Only the receipts the chunks pass to the mcs are followed
+// to their outcomes, by the light client proof of the receipt against the final head, so the block has to be old
+// enough for the receipts to have been executed. A tx the mcs signs to itself is executed in its own chunk without
+// a receipt passed on, it is followed by EXPERIMENTAL_tx_status.
+type NearStreamer struct {
+	client *nearclient.Client
+	mcs    types.AccountID
+	events []string
+}
+
+func NewNearStreamer(client *nearclient.Client, mcs types.AccountID, events []string) *NearStreamer {
+	return &NearStreamer{client: client, mcs: mcs, events: events}
+}
+
+// Outcomes returns the mcs outcomes with matching logs of the receipts in the block, ErrUnknownBlock is returned
+// for the skipped heights
+func (s *NearStreamer) Outcomes(ctx context.Context, height uint64) ([]mapprotocol.IndexerExecutionOutcomeWithReceipt, error) {
+	blk, err := s.client.BlockDetails(ctx, block.BlockID(height))
+	if err != nil {
+		var rpcErr *jsonrpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.Cause.Name == "UNKNOWN_BLOCK" {
+			return nil, ErrUnknownBlock
+		}
+		return nil, fmt.Errorf("get block %d failed: %w", height, err)
+	}
+
+	var (
+		ret  = make([]mapprotocol.IndexerExecutionOutcomeWithReceipt, 0)
+		head hash.CryptoHash // the final head the receipts are proved against, taken once it is needed
+	)
+	for _, header := range blk.Chunks {
+		// the header of the last chunk is repeated when the shard has no new chunk in the block
+		if uint64(header.HeightIncluded) != height {
+			continue
+		}
+		chunk, err := s.client.ChunkDetails(ctx, header.ChunkHash)
+		if err != nil {
+			return nil, fmt.Errorf("get chunk %s failed: %w", header.ChunkHash, err)
+		}
+		for _, tx := range chunk.Transactions {
+			if tx.SignerID != s.mcs || tx.ReceiverID != s.mcs {
+				continue
+			}
+			o, ok, err := s.localOutcome(ctx, &tx)
+			if err != nil {
+				return nil, fmt.Errorf("tx %s: %w", tx.Hash, err)
+			}
+			if ok {
+				ret = append(ret, o)
+			}
+		}
+		for _, r := range chunk.Receipts {
+			if r.ReceiverID != s.mcs || r.PredecessorID == systemAccount || !isActionReceipt(&r) {
+				continue
+			}
+			if head == (hash.CryptoHash{}) {
+				final, err := s.client.BlockDetails(ctx, block.FinalityFinal())
+				if err != nil {
+					return nil, fmt.Errorf("get final block failed: %w", err)
+				}
+				head = final.Header.Hash
+			}
+			proof, err := s.client.LightClientProof(ctx, nearclient.Receipt{
+				ReceiptID:       r.ReceiptID,
+				ReceiverID:      r.ReceiverID,
+				LightClientHead: head,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("get outcome of receipt %s failed: %w", r.ReceiptID, err)
+			}
+			if s.match(proof.OutcomeProof.Outcome.Logs) {
+				ret = append(ret, outcomeOf(&proof.OutcomeProof, r.PredecessorID))
+			}
+		}
+	}
+	return ret, nil
+}
+
+// localOutcome returns the outcome of the receipt the tx of the mcs to itself is converted to, an error is returned
+// when it is not executed yet
+func (s *NearStreamer) localOutcome(ctx context.Context, tx *nearclient.SignedTransactionView) (mapprotocol.IndexerExecutionOutcomeWithReceipt, bool, error) {
+	status, err := s.client.TransactionStatusWithReceipts(ctx, tx.Hash, tx.SignerID)
+	if err != nil {
+		return mapprotocol.IndexerExecutionOutcomeWithReceipt{}, false, fmt.Errorf("get status failed: %w", err)
+	}
+	if len(status.TransactionOutcome.Outcome.ReceiptIDs) == 0 {
+		return mapprotocol.IndexerExecutionOutcomeWithReceipt{}, false, nil
+	}
+	id := status.TransactionOutcome.Outcome.ReceiptIDs[0]
+	for _, o := range status.ReceiptsOutcome {
+		if o.ID != id {
+			continue
+		}
+		if o.Outcome.ExecutorID != s.mcs || !s.match(o.Outcome.Logs) {
+			return mapprotocol.IndexerExecutionOutcomeWithReceipt{}, false, nil
+		}
+		return outcomeOf(&o, tx.SignerID), true, nil
+	}
+	return mapprotocol.IndexerExecutionOutcomeWithReceipt{}, false, fmt.Errorf("receipt %s is not executed", id)
+}
+
+// systemAccount is the predecessor of the refunds, which never log the events
+const systemAccount = "system"
+
+// isActionReceipt tells the action receipts from the data receipts, which have no outcome
+func isActionReceipt(r *nearclient.ReceiptView) bool {
+	var kind map[string]json.RawMessage
+	if err := json.Unmarshal(r.Receipt, &kind); err != nil {
+		return false
+	}
+	_, ok := kind["Action"]
+	return ok
+}
+
+func outcomeOf(o *nearclient.ExecutionOutcomeWithIdView, predecessor types.AccountID) mapprotocol.IndexerExecutionOutcomeWithReceipt {
+	return mapprotocol.IndexerExecutionOutcomeWithReceipt{
+		ExecutionOutcome: mapprotocol.ExecutionOutcomeWithIdView{
+			BlockHash: o.BlockHash,
+			ID:        o.ID,
+			Outcome: mapprotocol.ExecutionOutcomeView{
+				ExecutorID:  o.Outcome.ExecutorID,
+				GasBurnt:    o.Outcome.GasBurnt,
+				Logs:        o.Outcome.Logs,
+				ReceiptIDs:  o.Outcome.ReceiptIDs,
+				Status:      o.Outcome.Status,
+				TokensBurnt: o.Outcome.TokensBurnt.String(),
+			},
+		},
+		Receipt: mapprotocol.ReceiptView{
+			PredecessorId: predecessor,
+			ReceiverID:    o.Outcome.ExecutorID,
+			ReceiptID:     o.ID,
+		},
+	}
+}
+
+func (s *NearStreamer) match(logs []string) bool {
+	for _, l := range logs {
+		for _, e := range s.events {
+			if strings.HasPrefix(l, e) {
+				return true
+			}
+		}
+	}
+	return false
+}
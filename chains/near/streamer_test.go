@@ -0,0 +1,178 @@
+package near
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	nearclient "github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/types/hash"
+)
+
+const streamerMcs = "mos.map007.testnet"
+
+func testHash(s string) string {
+	return hash.NewCryptoHash([]byte(s)).String()
+}
+
+// nearRpc stands in for the json-rpc of a node with the block 100 and the final block 105, height 101 is skipped.
+// The chunk of the block passes receipts to the mcs and to a token, its txs are a call of the mcs and a tx of the
+// mcs to itself.
+func nearRpc(t *testing.T, statuses, outcomes map[string]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     string          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+			return
+		}
+		result, rpcErr := "", ""
+		switch req.Method {
+		case "block":
+			var p struct {
+				BlockId  uint64 `json:"block_id"`
+				Finality string `json:"finality"`
+			}
+			_ = json.Unmarshal(req.Params, &p)
+			if p.Finality == "final" {
+				result = fmt.Sprintf(`{"author":"node0","header":{"height":105,"hash":"%s"},"chunks":[]}`, testHash("block105"))
+				break
+			}
+			if p.BlockId != 100 {
+				rpcErr = `{"name":"HANDLER_ERROR","cause":{"name":"UNKNOWN_BLOCK","info":{}},"code":-32000,"message":"Server error"}`
+				break
+			}
+			result = fmt.Sprintf(`{"author":"node0","header":{"height":100},"chunks":[`+
+				`{"chunk_hash":"%s","height_included":100,"shard_id":0},{"chunk_hash":"%s","height_included":99,"shard_id":1}]}`,
+				testHash("chunk0"), testHash("chunk1"))
+		case "chunk":
+			var p []string
+			_ = json.Unmarshal(req.Params, &p)
+			if p[0] != testHash("chunk0") {
+				t.Errorf("chunk %s not included in the block is fetched", p[0])
+			}
+			result = fmt.Sprintf(`{"author":"node0","header":{"chunk_hash":"%s"},"receipts":[`+
+				`{"predecessor_id":"usdc.testnet","receiver_id":"%s","receipt_id":"%s","receipt":{"Action":{}}},`+
+				`{"predecessor_id":"usdc.testnet","receiver_id":"%s","receipt_id":"%s","receipt":{"Data":{}}},`+
+				`{"predecessor_id":"system","receiver_id":"%s","receipt_id":"%s","receipt":{"Action":{}}},`+
+				`{"predecessor_id":"%s","receiver_id":"usdc.testnet","receipt_id":"%s","receipt":{"Action":{}}}],`+
+				`"transactions":[`+
+				`{"signer_id":"alice.testnet","receiver_id":"%s","hash":"%s"},`+
+				`{"signer_id":"%s","receiver_id":"%s","hash":"%s"}]}`,
+				testHash("chunk0"), streamerMcs, testHash("r0"), streamerMcs, testHash("data"), streamerMcs, testHash("refund"),
+				streamerMcs, testHash("r1"), streamerMcs, testHash("tx0"), streamerMcs, streamerMcs, testHash("tx1"))
+		case "EXPERIMENTAL_light_client_proof":
+			var p struct {
+				Type            string `json:"type"`
+				ReceiptId       string `json:"receipt_id"`
+				ReceiverId      string `json:"receiver_id"`
+				LightClientHead string `json:"light_client_head"`
+			}
+			_ = json.Unmarshal(req.Params, &p)
+			if p.Type != "receipt" || p.ReceiverId != streamerMcs || p.LightClientHead != testHash("block105") {
+				t.Errorf("unexpected proof request %+v", p)
+			}
+			o, ok := outcomes[p.ReceiptId]
+			if !ok {
+				rpcErr = `{"name":"HANDLER_ERROR","cause":{"name":"UNKNOWN_TRANSACTION_OR_RECEIPT","info":{}},"code":-32000,"message":"Server error"}`
+				break
+			}
+			result = fmt.Sprintf(`{"outcome_proof":%s}`, o)
+		case "EXPERIMENTAL_tx_status":
+			var p []string
+			_ = json.Unmarshal(req.Params, &p)
+			s, ok := statuses[p[0]]
+			if !ok {
+				t.Errorf("status of tx %s is fetched", p[0])
+			}
+			result = s
+		default:
+			t.Errorf("unexpected method %s", req.Method)
+		}
+		if rpcErr != "" {
+			_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":"%s","error":%s}`, req.ID, rpcErr)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"jsonrpc":"2.0","id":"%s","result":%s}`, req.ID, result)
+	}))
+}
+
+func txStatus(tx string, outcomes ...string) string {
+	receipts := ""
+	for i, o := range outcomes {
+		if i > 0 {
+			receipts += ","
+		}
+		receipts += o
+	}
+	return fmt.Sprintf(`{"status":{"SuccessValue":""},"transaction":{"signer_id":"%s","hash":"%s"},`+
+		`"transaction_outcome":{"block_hash":"%s","id":"%s","outcome":{"logs":[],"receipt_ids":["%s"],"executor_id":"%s"}},`+
+		`"receipts_outcome":[%s],"receipts":[]}`, streamerMcs, testHash(tx), testHash("block100"), testHash(tx),
+		testHash(tx+"-r0"), streamerMcs, receipts)
+}
+
+func outcome(id, executor string, logs string, receiptIds ...string) string {
+	ids := ""
+	for i, r := range receiptIds {
+		if i > 0 {
+			ids += ","
+		}
+		ids += fmt.Sprintf(`"%s"`, testHash(r))
+	}
+	return fmt.Sprintf(`{"block_hash":"%s","id":"%s","outcome":{"logs":%s,"receipt_ids":[%s],"executor_id":"%s","status":{"SuccessValue":""}}}`,
+		testHash("block100"), testHash(id), logs, ids, executor)
+}
+
+func TestNearStreamer(t *testing.T) {
+	transferOut := `["transfer out: {\"order_id\":\"0x01\"}","TransferOut"]`
+	statuses := map[string]string{
+		// the mcs calls itself, its callback to the token is passed on by a receipt
+		testHash("tx1"): txStatus("tx1", outcome("tx1-r0", streamerMcs, transferOut, "r1")),
+	}
+	outcomes := map[string]string{
+		// the token calls the mcs, the mcs logs the event
+		testHash("r0"): outcome("r0", streamerMcs, transferOut),
+	}
+	srv := nearRpc(t, statuses, outcomes)
+	defer srv.Close()
+	client, err := nearclient.NewClient(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := NewNearStreamer(&client, streamerMcs, []string{"transfer out:"})
+
+	got, err := s.Outcomes(context.Background(), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("%d outcomes", len(got))
+	}
+	o := got[0]
+	if o.ExecutionOutcome.ID.String() != testHash("tx1-r0") || o.Receipt.PredecessorId != streamerMcs ||
+		o.Receipt.ReceiverID != streamerMcs || len(o.ExecutionOutcome.Outcome.Logs) != 2 {
+		t.Fatalf("outcome of the tx %+v", o)
+	}
+	o = got[1]
+	if o.ExecutionOutcome.ID.String() != testHash("r0") || o.Receipt.PredecessorId != "usdc.testnet" ||
+		o.Receipt.ReceiverID != streamerMcs || o.ExecutionOutcome.BlockHash.String() != testHash("block100") {
+		t.Fatalf("outcome of the receipt %+v", o)
+	}
+
+	if _, err = s.Outcomes(context.Background(), 101); !errors.Is(err, ErrUnknownBlock) {
+		t.Fatalf("skipped height: %v", err)
+	}
+
+	// the receipt passed to the mcs is not executed yet
+	delete(outcomes, testHash("r0"))
+	if _, err = s.Outcomes(context.Background(), 100); err == nil {
+		t.Fatal("block with receipts not executed is streamed")
+	}
+}
@@ -7,7 +7,9 @@ import (
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/log15"
 	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/journal"
 	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/near-api-go/pkg/types"
 )
 
 var _ core.Writer = &writer{}
@@ -19,10 +21,14 @@ type writer struct {
 	stop    <-chan int
 	sysErr  chan<- error // Reports fatal error to core
 	metrics *metrics.ChainMetrics
+	journal journal.Journaler
+	keys    *keyPool // access keys of the account signing the txs
+	gas     *gasPolicy
 }
 
 // NewWriter creates and returns writer
-func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error, m *metrics.ChainMetrics) *writer {
+func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error, m *metrics.ChainMetrics,
+	jn journal.Journaler, keys *keyPool) *writer {
 	return &writer{
 		cfg:     *cfg,
 		conn:    conn,
@@ -30,11 +36,29 @@ func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int,
 		stop:    stop,
 		sysErr:  sysErr,
 		metrics: m,
+		journal: jn,
+		keys:    keys,
+		gas:     newGasPolicy(cfg),
 	}
 }
 
 func (w *writer) start() error {
 	w.log.Debug("Starting ethereum writer...")
+	// function call keys can not attach deposit, the calls attaching it run in parallel only over full access keys
+	for _, c := range []struct{ receiver, method string }{
+		{w.cfg.lightNode, MethodOfUpdateBlockHeader},
+		{w.cfg.mcsContract, MethodOfVerifyReceiptProof},
+		{w.cfg.mcsContract, MethodOfTransferIn},
+		{w.cfg.mcsContract, MethodOfSwapIn},
+	} {
+		_, deposit := w.gas.attach(c.method)
+		w.log.Info("Near access keys of the method", "method", c.method, "deposit", deposit.String(),
+			"parallel", w.keys.signers(c.receiver, c.method, deposit != types.Balance{}))
+	}
+	err := w.reconcile()
+	if err != nil {
+		w.log.Warn("Failed to reconcile journal", "err", err)
+	}
 	return nil
 }
 
@@ -16,7 +16,7 @@ import (
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/near-api-go/pkg/client/block"
 
-	"github.com/mapprotocol/compass/internal/near"
+	"github.com/mapprotocol/compass/journal"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/near-api-go/pkg/client"
 	"github.com/mapprotocol/near-api-go/pkg/types"
@@ -59,7 +59,7 @@ func (w *writer) exeSyncMapMsg(m msg.Message) bool {
 				return false
 			}
 
-			txHash, err := w.sendTx(w.cfg.lightNode, MethodOfUpdateBlockHeader, m.Payload[0].([]byte))
+			txHash, err := w.sendTx(w.cfg.lightNode, MethodOfUpdateBlockHeader, m.Payload[0].([]byte), newEntry(m))
 			w.conn.UnlockOpts()
 			if err == nil {
 				// message successfully handled
@@ -114,7 +114,7 @@ func (w *writer) exeSwapMsg(m msg.Message) bool {
 			w.log.Error("Verify Execution failed, Will retry", "srcHash", inputHash, "err", err)
 			return false
 		}
-		txHash, err := w.sendTx(w.cfg.mcsContract, MethodOfVerifyReceiptProof, verify)
+		txHash, err := w.sendTx(w.cfg.mcsContract, MethodOfVerifyReceiptProof, verify, newEntry(m))
 		if err == nil {
 			w.log.Info("Verify Success", "mcsTx", txHash.String(), "srcHash", inputHash)
 			time.Sleep(time.Second)
@@ -148,7 +148,7 @@ func (w *writer) exeSwapMsg(m msg.Message) bool {
 				method = MethodOfSwapIn
 			}
 			w.log.Info("Send transaction", "addr", w.cfg.mcsContract, "srcHash", inputHash, "method", method)
-			txHash, err := w.sendTx(w.cfg.mcsContract, method, data)
+			txHash, err := w.sendTx(w.cfg.mcsContract, method, data, newEntry(m))
 			if err == nil {
 				w.log.Info("Submitted cross tx execution", "mcsTx", txHash.String(), "srcHash", inputHash)
 				m.DoneCh <- struct{}{}
@@ -188,34 +188,77 @@ func (w *writer) exeSwapMsg(m msg.Message) bool {
 	}
 }
 
-// sendTx send tx to an address with value and input data
-func (w *writer) sendTx(toAddress string, method string, input []byte) (hash.CryptoHash, error) {
+// sendTx send tx to an address with value and input data, the tx is recorded to journal before and after it is sent
+func (w *writer) sendTx(toAddress string, method string, input []byte, e *journal.Entry) (hash.CryptoHash, error) {
 	w.log.Info("sendTx", "toAddress", toAddress)
-	ctx := client.ContextWithKeyPair(context.Background(), *w.conn.Keypair())
-	b := types.Balance{}
-	if method == MethodOfTransferIn || method == MethodOfSwapIn || method == MethodOfVerifyReceiptProof {
-		b, _ = types.BalanceFromString(near.Deposit)
+	ctx := context.Background()
+	gas, b := w.gas.attach(method)
+	k, err := w.keys.acquire(toAddress, method, b != types.Balance{})
+	if err != nil {
+		return hash.CryptoHash{}, err
+	}
+	nonce, err := w.keys.nextNonce(ctx, k)
+	if err != nil {
+		w.keys.release(k, 0, true)
+		return hash.CryptoHash{}, err
+	}
+	if e != nil {
+		e.Id = strconv.FormatInt(time.Now().UnixNano(), 10)
+		e.To = toAddress
+		e.Method = method
+		e.GasLimit = uint64(gas)
+		e.Nonce = nonce
+		w.record(e, journal.StatusPending, nil)
 	}
+	w.log.Debug("sendTx with access key", "key", k.kp.PublicKey.String(), "nonce", nonce)
 	res, err := w.conn.Client().TransactionSendAwait(
 		ctx,
 		w.cfg.from,
 		toAddress,
 		[]action.Action{
-			action.NewFunctionCall(method, input, near.NewFunctionCallGas, b),
+			action.NewFunctionCall(method, input, gas, b),
 		},
 		client.WithLatestBlock(),
-		client.WithKeyPair(*w.conn.Keypair()),
+		client.WithKeyPair(k.kp),
+		client.WithKeyNonce(nonce),
 	)
+	w.keys.release(k, nonce, err != nil)
 	if err != nil {
+		// the tx may have been broadcast, leave it to reconcile
+		w.record(e, journal.StatusSubmitted, err)
 		return hash.CryptoHash{}, fmt.Errorf("failed to do txn: %w", err)
 	}
 	w.log.Debug("sendTx success", "res", res)
+	if e != nil {
+		e.TxHash = res.Transaction.Hash.String()
+		e.Nonce = uint64(res.Transaction.Nonce)
+	}
+	w.reportGas(method, gas, b, &res)
 	if len(res.Status.Failure) != 0 {
-		return hash.CryptoHash{}, fmt.Errorf("%s", string(res.Status.Failure))
+		err = fmt.Errorf("%s", string(res.Status.Failure))
+		w.record(e, journal.StatusFailed, err)
+		return hash.CryptoHash{}, err
 	}
+	w.record(e, journal.StatusSuccess, nil)
 	return res.Transaction.Hash, nil
 }
 
+// reportGas logs the gas and the deposit spent by the tx, and keeps the gas burnt for the estimation
+func (w *writer) reportGas(method string, gas types.Gas, deposit types.Balance, res *client.FinalExecutionOutcomeView) {
+	var receipts []client.ReceiptView
+	if deposit != (types.Balance{}) && len(res.Status.Failure) == 0 {
+		status, err := w.conn.Client().TransactionStatusWithReceipts(context.Background(), res.Transaction.Hash, w.cfg.from)
+		if err != nil {
+			w.log.Warn("Get receipts of tx failed, the deposit refunded is not reported", "tx", res.Transaction.Hash, "err", err)
+		}
+		receipts = status.Receipts
+	}
+	r := newGasReport(gas, deposit, res, receipts)
+	w.gas.observe(method, r.GasBurnt, res)
+	w.log.Info("Near tx gas report", "tx", res.Transaction.Hash, "method", method, "gasAttached", r.GasAttached,
+		"gasBurnt", r.GasBurnt, "tokensBurnt", r.TokensBurnt, "deposit", r.Deposit, "depositRefunded", r.DepositRefunded)
+}
+
 func (w *writer) checkOrderId(toAddress string, input []byte) (bool, error) {
 	var fixedOrderId [32]byte
 	for idx, v := range input {
@@ -0,0 +1,129 @@
+package opstack
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/ethereum"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/opstack"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func init() {
+	chains.Register(chains.Opstack, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: parseConfig,
+	})
+}
+
+func parseConfig(chainCfg *core.ChainConfig) error {
+	if _, err := opstack.ParseOpts(chainCfg.Opts); err != nil {
+		return err
+	}
+	return chain.CheckConfig(chainCfg)
+}
+
+// InitializeChain creates an op stack chain. The l2 blocks have no headers to verify on map, the maintainer syncs
+// the output roots proposed on l1 instead, and the messenger proves the receipts against them.
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	opts, err := opstack.ParseOpts(chainCfg.Opts)
+	if err != nil {
+		return nil, err
+	}
+	ctx := context.Background()
+	source, err := opts.NewOutputSource(ctx)
+	if err != nil {
+		return nil, err
+	}
+	client, err := opstack.Dial(ctx, chainCfg.Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	s := &syncer{client: client, source: source}
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, connection.NewConnection, chain.OptOfSync2Map(s.syncHeaderToMap),
+		chain.OptOfMos(chain.MosOfProof))
+}
+
+type syncer struct {
+	client *opstack.Client
+	source opstack.OutputSource
+
+	lock sync.Mutex
+	next *opstack.Output // the first output after the height synced to map
+}
+
+// syncHeaderToMap syncs the output proposed at the block, the blocks without an output are passed. It waits, not
+// failing, while the output after the synced height is not proposed on l1.
+func (s *syncer) syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if s.next == nil || s.next.L2BlockNumber < latestBlock.Uint64() {
+		syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+		if err != nil {
+			m.Log.Error("Get current synced Height failed", "err", err)
+			return err
+		}
+		if latestBlock.Cmp(syncedHeight) <= 0 {
+			m.Log.Info("CurrentBlock less than synchronized headerHeight", "synced height", syncedHeight,
+				"current height", latestBlock)
+			return nil
+		}
+		next, err := s.source.OutputAfter(context.Background(), latestBlock.Uint64())
+		if err != nil {
+			if errors.Is(err, ethereum.NotFound) {
+				m.Log.Debug("Output not proposed yet", "block", latestBlock)
+			}
+			return err
+		}
+		s.next = next
+	}
+	if s.next.L2BlockNumber != latestBlock.Uint64() {
+		return nil
+	}
+
+	m.Log.Info("find sync output", "index", s.next.Index, "current height", latestBlock)
+	rootProof, _, err := opstack.NewOutputRootProof(context.Background(), s.client, s.next.L2BlockNumber)
+	if err != nil {
+		return err
+	}
+	if root := rootProof.Root(); root != s.next.Root {
+		return fmt.Errorf("%w: output %d of block %d is %s on l1, the node has %s", opstack.ErrInvalidOutput,
+			s.next.Index, s.next.L2BlockNumber, s.next.Root, root)
+	}
+	input, err := mapprotocol.Opstack.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Pack(opstack.OutputData{
+		OutputIndex:     s.next.Index,
+		L2BlockNumber:   latestBlock,
+		OutputRootProof: *rootProof,
+	})
+	if err != nil {
+		m.Log.Error("Failed to abi pack", "err", err)
+		return err
+	}
+
+	id := big.NewInt(0).SetUint64(uint64(m.Cfg.Id))
+	msgpayload := []interface{}{id, input}
+	message := msg.NewSyncToMap(m.Cfg.Id, m.Cfg.MapChainID, msgpayload, m.MsgCh)
+
+	err = m.Router.Send(message)
+	if err != nil {
+		m.Log.Error("Subscription error: failed to route message", "err", err)
+		return err
+	}
+
+	err = m.WaitUntilMsgHandled(1)
+	if err != nil {
+		return err
+	}
+	return nil
+}
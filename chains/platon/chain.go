@@ -2,25 +2,34 @@ package platon
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 
 	"github.com/mapprotocol/compass/internal/platon"
 
 	ethcommon "github.com/ethereum/go-ethereum/common"
-	"github.com/mapprotocol/compass/internal/tx"
 
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/log15"
+	"github.com/mapprotocol/compass/chains"
 	"github.com/mapprotocol/compass/core"
 	"github.com/mapprotocol/compass/internal/chain"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 )
 
+func init() {
+	chains.Register(chains.Platon, &chains.Type{
+		Initialize:  InitializeChain,
+		ParseConfig: chain.CheckConfig,
+	})
+}
+
 func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role) (core.Chain, error) {
-	return chain.New(chainCfg, logger, sysErr, m, role, platon.NewConn, chain.OptOfSync2Map(syncHeaderToMap), chain.OptOfMos(mos))
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	return chain.New(chainCfg, logger, sysErr, m, role, mc, platon.NewConn, chain.OptOfSync2Map(syncHeaderToMap), chain.OptOfMos(mos))
 }
 
 func syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
@@ -28,8 +37,8 @@ func syncHeaderToMap(m *chain.Maintainer, latestBlock *big.Int) error {
 	if remainder.Cmp(mapprotocol.Big0) != 0 {
 		return nil
 	}
-	//syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-	syncedHeight, err := mapprotocol.Get2MapByLight()
+	//syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+	syncedHeight, err := m.Map.Get2MapByLight()
 	if err != nil {
 		m.Log.Error("Get current synced Height failed", "err", err)
 		return err
@@ -98,7 +107,11 @@ func mos(m *chain.Messenger, latestBlock *big.Int) (int, error) {
 	if len(logs) == 0 {
 		return 0, nil
 	}
-	headerParam, err := platon.GetHeaderParam(m.Conn.Client(), latestBlock)
+	pb, err := m.ProofBuilder()
+	if err != nil {
+		return 0, err
+	}
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
 	if err != nil {
 		return 0, err
 	}
@@ -109,22 +122,14 @@ func mos(m *chain.Messenger, latestBlock *big.Int) (int, error) {
 		var message msg.Message
 		// getOrderId
 		orderId := log.Data[:32]
-		method := m.GetMethod(log.Topics[0])
-		txsHash, err := tx.GetTxsHashByBlockNumber(m.Conn.Client(), latestBlock)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-		}
-		receipts, err := tx.GetReceiptsByTxsHash(m.Conn.Client(), txsHash)
-		if err != nil {
-			return 0, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-		}
-
-		payload, err := platon.AssembleProof(headerParam, log, receipts, method, m.Cfg.Id)
-		if err != nil {
-			return 0, fmt.Errorf("unable to Parse Log: %w", err)
+		proof, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, iproof.ErrInvalidProof) {
+			continue
+		} else if err != nil {
+			return 0, err
 		}
 
-		msgPayload := []interface{}{payload, orderId, latestBlock.Uint64(), log.TxHash}
+		msgPayload := []interface{}{proof.Data, orderId, latestBlock.Uint64(), log.TxHash}
 		message = msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
 
 		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index,
@@ -0,0 +1,93 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package chains
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+// ErrProofNotSupported is returned by the Proof of the chain types whose events are not proved by the monitor
+var ErrProofNotSupported = errors.New("proof not supported")
+
+// Initializer creates the chain of the config with its connection, listener and writer, the chain reaches map and
+// the other chains of the relayer through the map context
+type Initializer func(cfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error)
+
+// Type is what a chain package provides for its chain type, the proof builder of the type is registered
+// in internal/proof under the same name
+type Type struct {
+	Initialize Initializer
+	// ParseConfig checks the options of the chain, it is called for all chains before any of them is initialized
+	ParseConfig func(cfg *core.ChainConfig) error
+	// InitMap is called with the chain when it is the map chain to set it to the map context, nil when the type can
+	// not be the map chain
+	InitMap func(c core.Chain, cfg *core.ChainConfig, mc *mapprotocol.MapContext) error
+	// Proof returns the hex proof of the tx for the monitor, nil when the proof builder of the type is used
+	Proof func(ctx context.Context, cfg *core.ChainConfig, tx common.Hash) (string, error)
+}
+
+var (
+	lock       sync.RWMutex
+	registered = make(map[string]*Type)
+)
+
+// Register makes the chain type available by its name, it is called in the init of the chain package
+func Register(name string, t *Type) {
+	lock.Lock()
+	defer lock.Unlock()
+	if t == nil || t.Initialize == nil {
+		panic("chain type registered without initializer for " + name)
+	}
+	if _, ok := registered[name]; ok {
+		panic("chain type registered twice for " + name)
+	}
+	registered[name] = t
+}
+
+// Lookup returns the registered chain type of the name
+func Lookup(name string) (*Type, error) {
+	lock.RLock()
+	defer lock.RUnlock()
+	t, ok := registered[name]
+	if !ok {
+		return nil, fmt.Errorf("unrecognized chain type (%s)", name)
+	}
+	return t, nil
+}
+
+// Types returns the names of the registered chain types
+func Types() []string {
+	lock.RLock()
+	defer lock.RUnlock()
+	ret := make([]string, 0, len(registered))
+	for name := range registered {
+		ret = append(ret, name)
+	}
+	sort.Strings(ret)
+	return ret
+}
+
+// CheckConfig parses the config of the chain type on a copy of it, as the parsers consume the options they know
+func (t *Type) CheckConfig(cfg *core.ChainConfig) error {
+	if t.ParseConfig == nil {
+		return nil
+	}
+	c := *cfg
+	c.Opts = make(map[string]string, len(cfg.Opts))
+	for k, v := range cfg.Opts {
+		c.Opts[k] = v
+	}
+	return t.ParseConfig(&c)
+}
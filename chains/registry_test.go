@@ -0,0 +1,59 @@
+package chains
+
+import (
+	"errors"
+	"testing"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+func testInitialize(*core.ChainConfig, log15.Logger, chan<- error, *metrics.ChainMetrics, mapprotocol.Role,
+	*mapprotocol.MapContext) (core.Chain, error) {
+	return nil, nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register("test", &Type{
+		Initialize: testInitialize,
+		ParseConfig: func(cfg *core.ChainConfig) error {
+			if cfg.Opts["mcs"] == "" {
+				return errors.New("must provide opts.mcs")
+			}
+			delete(cfg.Opts, "mcs")
+			return nil
+		},
+	})
+
+	ct, err := Lookup("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := &core.ChainConfig{Opts: map[string]string{"mcs": "0x01"}}
+	if err = ct.CheckConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	// the options are left for the initializer
+	if cfg.Opts["mcs"] != "0x01" {
+		t.Fatal("config is consumed by the check")
+	}
+	if err = ct.CheckConfig(&core.ChainConfig{Opts: map[string]string{}}); err == nil {
+		t.Fatal("expected error for invalid config")
+	}
+
+	if _, err = Lookup("unknown"); err == nil {
+		t.Fatal("expected error for unknown chain type")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	Register("twice", &Type{Initialize: testInitialize})
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	Register("twice", &Type{Initialize: testInitialize})
+}
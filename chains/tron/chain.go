@@ -0,0 +1,198 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/tron"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/tron"
+	"github.com/mapprotocol/compass/keystore"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/pkg/errors"
+)
+
+type Chain struct {
+	cfg    *core.ChainConfig      // The config of the chain
+	conn   *connection.Connection // The chains connection
+	writer *writer                // The writer of the chain
+	stop   chan<- int
+	listen chains.Listener // The listener of this chain
+}
+
+func init() {
+	chains.Register(chains.Tron, &chains.Type{
+		Initialize: initialize,
+		ParseConfig: func(chainCfg *core.ChainConfig) error {
+			_, err := parseChainConfig(chainCfg)
+			return err
+		},
+		// the events of tron can not be proven, see errNoEventProof
+		Proof: func(context.Context, *core.ChainConfig, common.Hash) (string, error) {
+			return "", chains.ErrProofNotSupported
+		},
+	})
+}
+
+func initialize(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (core.Chain, error) {
+	c, err := InitializeChain(chainCfg, logger, sysErr, m, role, mc)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func InitializeChain(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
+	role mapprotocol.Role, mc *mapprotocol.MapContext) (*Chain, error) {
+	cfg, err := parseChainConfig(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+	if role == mapprotocol.RoleOfMessenger && cfg.SyncToMap {
+		return nil, errNoEventProof
+	}
+
+	kpI, err := keystore.KeypairFromAddress(cfg.From, keystore.EthChain, cfg.KeystorePath, chainCfg.Insecure)
+	if err != nil {
+		return nil, err
+	}
+	kp, _ := kpI.(*secp256k1.Keypair)
+
+	bs, err := chain.SetupBlockStore(cfg.Config, kp, role)
+	if err != nil {
+		return nil, err
+	}
+	jn, err := chain.SetupJournal(cfg.Config, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
+	stop := make(chan int)
+	conn := connection.NewConnection(cfg.Endpoint, kp, logger, cfg.FeeLimit)
+	err = conn.Connect()
+	if err != nil {
+		return nil, err
+	}
+
+	if chainCfg.LatestBlock {
+		curr, err := conn.LatestBlock()
+		if err != nil {
+			return nil, err
+		}
+		cfg.StartBlock = curr
+	}
+
+	var listen chains.Listener
+	cs := newCommonListen(conn, cfg, logger, stop, sysErr, m, bs, mc)
+	if role == mapprotocol.RoleOfMessenger {
+		err = conn.EnsureHasBytecode(cfg.McsContract)
+		if err != nil {
+			return nil, err
+		}
+		fn := Map2TronVerifyRange(cfg.From, cfg.LightNode, conn.Client())
+		left, right, err := fn()
+		if err != nil {
+			return nil, errors.Wrap(err, "tron get init verifyHeight failed")
+		}
+		logger.Info("Map2Tron Current verify range", "left", left, "right", right, "lightNode", tron.EncodeAddress(cfg.LightNode))
+		mc.SetMap2OtherVerifyRange(cfg.Id, fn)
+		listen = newMessenger(cs)
+	} else if role == mapprotocol.RoleOfMaintainer {
+		fn := Map2TronHeight(cfg.From, cfg.LightNode, conn.Client())
+		height, err := fn()
+		if err != nil {
+			return nil, errors.Wrap(err, "tron get init headerHeight failed")
+		}
+		logger.Info("Map2Tron Current situation", "height", height, "lightNode", tron.EncodeAddress(cfg.LightNode))
+		mc.SetMap2OtherHeight(cfg.Id, height, fn)
+		listen = newMaintainer(cs)
+	}
+	w := newWriter(conn, cfg, logger, stop, sysErr, m, jn, mc)
+
+	return &Chain{
+		cfg:    chainCfg,
+		conn:   conn,
+		writer: w,
+		stop:   stop,
+		listen: listen,
+	}, nil
+}
+
+// Map2TronHeight returns the height of the map light client on tron
+func Map2TronHeight(fromUser string, lightNode common.Address, client *tron.Client) mapprotocol.GetHeight {
+	return func() (*big.Int, error) {
+		input, err := mapprotocol.PackInput(mapprotocol.Height, mapprotocol.MethodOfHeaderHeight)
+		if err != nil {
+			return nil, fmt.Errorf("pack lightNode headerHeight Input failed, err is %v", err.Error())
+		}
+		output, err := client.TriggerConstantContract(context.Background(), common.HexToAddress(fromUser), lightNode, input)
+		if err != nil {
+			return nil, fmt.Errorf("headerHeight triggerConstantContract failed, err is %v", err.Error())
+		}
+		return mapprotocol.UnpackHeaderHeightOutput(output)
+	}
+}
+
+// Map2TronVerifyRange returns the range of the map blocks the light client on tron can verify
+func Map2TronVerifyRange(fromUser string, lightNode common.Address, client *tron.Client) mapprotocol.GetVerifyRange {
+	return func() (*big.Int, *big.Int, error) {
+		input, err := mapprotocol.PackInput(mapprotocol.Verify, mapprotocol.MethodVerifiableHeaderRange)
+		if err != nil {
+			return nil, nil, errors.Wrap(err, "pack lightNode verifiableHeaderRange Input failed")
+		}
+		output, err := client.TriggerConstantContract(context.Background(), common.HexToAddress(fromUser), lightNode, input)
+		if err != nil {
+			return nil, nil, fmt.Errorf("verifiableHeaderRange triggerConstantContract failed, err is %v", err.Error())
+		}
+		return mapprotocol.UnpackVerifyRangeOutput(output)
+	}
+}
+
+func (c *Chain) SetRouter(r *core.Router) {
+	r.Listen(c.cfg.Id, c.writer)
+	c.listen.SetRouter(r)
+}
+
+func (c *Chain) Start() error {
+	err := c.listen.Sync()
+	if err != nil {
+		return err
+	}
+
+	err = c.writer.start()
+	if err != nil {
+		return err
+	}
+
+	c.writer.log.Debug("Successfully started chain")
+	return nil
+}
+
+func (c *Chain) Id() msg.ChainId {
+	return c.cfg.Id
+}
+
+func (c *Chain) Name() string {
+	return c.cfg.Name
+}
+
+func (c *Chain) LatestBlock() metrics.LatestBlock {
+	return c.listen.GetLatestBlock()
+}
+
+// Stop signals to any running routines to exit
+func (c *Chain) Stop() {
+	close(c.stop)
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
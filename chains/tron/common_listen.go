@@ -0,0 +1,123 @@
+package tron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/mapprotocol/compass/blockstore"
+	"github.com/mapprotocol/compass/chains"
+	connection "github.com/mapprotocol/compass/connections/tron"
+	"github.com/mapprotocol/compass/internal/constant"
+	"github.com/mapprotocol/compass/internal/tron"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/pkg/util"
+)
+
+type commonListen struct {
+	cfg         Config
+	conn        *connection.Connection
+	mc          *mapprotocol.MapContext
+	log         log15.Logger
+	router      chains.Router
+	stop        <-chan int
+	msgCh       chan struct{}
+	sysErr      chan<- error // Reports fatal error to core
+	latestBlock metrics.LatestBlock
+	metrics     *metrics.ChainMetrics
+	blockStore  blockstore.Blockstorer
+	client      *tron.Client
+}
+
+func newCommonListen(conn *connection.Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error,
+	m *metrics.ChainMetrics, bs blockstore.Blockstorer, mc *mapprotocol.MapContext) *commonListen {
+	return &commonListen{
+		cfg:         *cfg,
+		conn:        conn,
+		mc:          mc,
+		log:         log,
+		stop:        stop,
+		sysErr:      sysErr,
+		latestBlock: metrics.LatestBlock{LastUpdated: time.Now()},
+		metrics:     m,
+		msgCh:       make(chan struct{}),
+		blockStore:  bs,
+		client:      conn.Client(),
+	}
+}
+
+func (c *commonListen) SetRouter(r chains.Router) {
+	c.router = r
+}
+
+func (c *commonListen) GetLatestBlock() metrics.LatestBlock {
+	return c.latestBlock
+}
+
+// waitUntilMsgHandled this function will block untill message is handled
+func (c *commonListen) waitUntilMsgHandled(counter int) error {
+	c.log.Debug("waitUntilMsgHandled", "counter", counter)
+	for counter > 0 {
+		<-c.msgCh
+		counter -= 1
+	}
+	return nil
+}
+
+// poll calls handle with the blocks from currentBlock once they are delay blocks behind the latest solidified
+// block, a block failing is retried until it is handled
+func (c *commonListen) poll(role string, currentBlock *big.Int, delay int64, handle func(*big.Int) error) error {
+	c.log.Info("Polling Blocks...", "block", currentBlock)
+	for {
+		select {
+		case <-c.stop:
+			return errors.New("polling terminated")
+		default:
+			latestBlock, err := c.conn.LatestBlock()
+			if err != nil {
+				c.log.Error("Unable to get latest block", "block", currentBlock, "err", err)
+				time.Sleep(constant.BlockRetryInterval)
+				continue
+			}
+			if c.metrics != nil {
+				c.metrics.LatestKnownBlock.Set(float64(latestBlock.Int64()))
+			}
+
+			if new(big.Int).Sub(latestBlock, currentBlock).Cmp(big.NewInt(delay)) == -1 {
+				c.log.Debug("Block not ready, will retry", "current", currentBlock, "latest", latestBlock)
+				time.Sleep(constant.BlockRetryInterval)
+				continue
+			}
+
+			err = handle(currentBlock)
+			if err != nil {
+				c.log.Error("Failed to handle block", "block", currentBlock, "err", err)
+				time.Sleep(constant.BlockRetryInterval)
+				util.Alarm(context.Background(), fmt.Sprintf("%s %s failed, err is %s", c.cfg.Name, role, err.Error()))
+				continue
+			}
+
+			// Write to block store. Not a critical operation, no need to retry
+			err = c.blockStore.StoreBlock(currentBlock)
+			if err != nil {
+				c.log.Error("Failed to write latest block to blockstore", "block", currentBlock, "err", err)
+			}
+			if c.metrics != nil {
+				c.metrics.BlocksProcessed.Inc()
+				c.metrics.LatestProcessedBlock.Set(float64(latestBlock.Int64()))
+			}
+
+			c.latestBlock.Height = new(big.Int).Set(latestBlock)
+			c.latestBlock.LastUpdated = time.Now()
+
+			currentBlock = new(big.Int).Add(currentBlock, big.NewInt(1))
+			if latestBlock.Int64()-currentBlock.Int64() <= delay {
+				time.Sleep(constant.MessengerInterval)
+			}
+		}
+	}
+}
@@ -0,0 +1,54 @@
+package tron
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/tron"
+)
+
+const (
+	FeeLimitOpt     = "feeLimit"
+	DefaultFeeLimit = 1000000000 // 1000 TRX in sun
+)
+
+// Config is the config of the evm chains with the fee limit of the txs. The addresses of the config can be the
+// base58 addresses of tron, they are converted to the hex addresses before the evm config is parsed.
+type Config struct {
+	*chain.Config
+	FeeLimit int64
+}
+
+func parseChainConfig(chainCfg *core.ChainConfig) (*Config, error) {
+	from, err := tron.DecodeAddress(chainCfg.From)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from: %w", err)
+	}
+	chainCfg.From = from.Hex()
+	for _, opt := range []string{chain.McsOpt, chain.LightNode} {
+		if v, ok := chainCfg.Opts[opt]; ok && v != "" {
+			addr, err := tron.DecodeAddress(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid opts.%s: %w", opt, err)
+			}
+			chainCfg.Opts[opt] = addr.Hex()
+		}
+	}
+
+	feeLimit := int64(DefaultFeeLimit)
+	if v, ok := chainCfg.Opts[FeeLimitOpt]; ok && v != "" {
+		feeLimit, err = strconv.ParseInt(v, 10, 64)
+		if err != nil || feeLimit <= 0 {
+			return nil, fmt.Errorf("unable to parse %s", FeeLimitOpt)
+		}
+	}
+	delete(chainCfg.Opts, FeeLimitOpt)
+
+	cfg, err := chain.ParseConfig(chainCfg)
+	if err != nil {
+		return nil, err
+	}
+	return &Config{Config: cfg, FeeLimit: feeLimit}, nil
+}
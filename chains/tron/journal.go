@@ -0,0 +1,97 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/pkg/errors"
+)
+
+// txExpiration is how long a tx built by the node is valid, it can not be packed after
+const txExpiration = time.Minute
+
+// newEntry builds the journal entry of the message, tx fields are filled in sendTx
+func newEntry(m msg.Message) *journal.Entry {
+	e := &journal.Entry{
+		Source:      m.Source,
+		Destination: m.Destination,
+		Type:        m.Type,
+	}
+	if m.Type == msg.SwapWithMapProof {
+		if orderId, ok := m.Payload[1].([]byte); ok {
+			e.OrderId = "0x" + common.Bytes2Hex(orderId)
+		}
+		if len(m.Payload) > 3 {
+			e.SrcHash = fmt.Sprintf("%v", m.Payload[3])
+		}
+	}
+	return e
+}
+
+// record writes the entry with the status to journal, failing to write journal does not block the tx
+func (w *writer) record(e *journal.Entry, status journal.Status, err error) {
+	if e == nil {
+		return
+	}
+	e.Status = status
+	e.Time = time.Now()
+	e.Error = ""
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if errr := w.journal.Append(e); errr != nil {
+		w.log.Warn("Failed to write journal", "tx", e.TxHash, "status", status, "err", errr)
+	}
+}
+
+// finish records the final state of a submitted tx according to the result of txStatus,
+// a tx whose info is not found yet is left as submitted
+func (w *writer) finish(e *journal.Entry, err error) {
+	if err == nil {
+		w.record(e, journal.StatusSuccess, nil)
+	} else if errors.Is(err, errTxNotSuccess) {
+		w.record(e, journal.StatusFailed, err)
+	}
+}
+
+// reconcile checks the unfinished journal entries left by the last run against chain state. Tron has no nonce,
+// a tx whose info is not found after it expired will never be packed and is dropped
+func (w *writer) reconcile() error {
+	entries, err := w.journal.Unfinished()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	w.log.Info("Reconciling unfinished journal entries", "count", len(entries))
+
+	for _, e := range entries {
+		info, err := w.conn.Client().TransactionInfo(context.Background(), common.HexToHash(e.TxHash))
+		if err == nil {
+			if info.Success() {
+				w.record(e, journal.StatusSuccess, nil)
+			} else {
+				w.record(e, journal.StatusFailed, fmt.Errorf("result is (%s %s)", info.Result, info.Receipt.Result))
+			}
+			w.log.Info("Journal entry reconciled", "tx", e.TxHash, "srcHash", e.SrcHash, "status", e.Status)
+			continue
+		}
+		if err != ethereum.NotFound {
+			w.log.Warn("Journal entry reconcile failed", "tx", e.TxHash, "err", err)
+			continue
+		}
+		if time.Since(e.Time) < txExpiration {
+			w.log.Warn("Journal entry still pending", "tx", e.TxHash, "srcHash", e.SrcHash)
+			continue
+		}
+		w.record(e, journal.StatusDropped, errors.New("tx not found and expired"))
+		w.log.Info("Journal entry reconciled", "tx", e.TxHash, "srcHash", e.SrcHash, "status", e.Status)
+	}
+	return nil
+}
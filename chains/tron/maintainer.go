@@ -0,0 +1,127 @@
+package tron
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/mapprotocol/compass/internal/tron"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+// maintainer syncs the headers of tron to the light client on map, HeaderCountOfTron headers with their witness
+// signatures are synced from the first block after every maintenance, which is the first one produced by the
+// witnesses elected in the maintenance
+type maintainer struct {
+	*commonListen
+	maintenance *tron.Maintenance
+	headers     map[uint64]*tron.Header // the headers before the first one of the latest block handled
+}
+
+func newMaintainer(cs *commonListen) *maintainer {
+	return &maintainer{commonListen: cs, headers: make(map[uint64]*tron.Header)}
+}
+
+func (m *maintainer) Sync() error {
+	m.log.Debug("Starting listener...")
+	go func() {
+		if !m.cfg.SyncToMap {
+			m.log.Info("Tron maintainer does not sync to map, exit")
+			return
+		}
+		err := m.sync()
+		if err != nil {
+			m.log.Error("Polling blocks failed", "err", err)
+		}
+	}()
+
+	return nil
+}
+
+func (m *maintainer) sync() error {
+	currentBlock := m.cfg.StartBlock
+	syncedHeight, err := m.mc.Get2MapHeight(m.cfg.Id)
+	if err != nil {
+		m.log.Error("Get synced Height failed", "err", err)
+		return err
+	}
+	m.log.Info("Check Sync Status...", "synced", syncedHeight)
+	if syncedHeight != nil && syncedHeight.Cmp(currentBlock) != 0 {
+		currentBlock = new(big.Int).Add(syncedHeight, big.NewInt(1))
+		m.log.Info("SyncedHeight is higher or lower than currentHeight, so let currentHeight = syncedHeight",
+			"syncedHeight", syncedHeight, "currentBlock", currentBlock)
+	}
+	return m.poll("sync header", currentBlock, 0, m.syncHeaderToMap)
+}
+
+// syncHeaderToMap syncs the headers ending at the block when the first of them is the first block after a
+// maintenance
+func (m *maintainer) syncHeaderToMap(latestBlock *big.Int) error {
+	start := latestBlock.Uint64() - (mapprotocol.HeaderCountOfTron - 1)
+	ok, err := m.firstAfterMaintenance(start)
+	if err != nil || !ok {
+		return err
+	}
+	// synced height check
+	syncedHeight, err := m.mc.Get2MapHeight(m.cfg.Id)
+	if err != nil {
+		m.log.Error("Get current synced Height failed", "err", err)
+		return err
+	}
+	if latestBlock.Cmp(syncedHeight) <= 0 {
+		m.log.Info("CurrentBlock less than synchronized headerHeight", "synced height", syncedHeight,
+			"current height", latestBlock)
+		return nil
+	}
+	m.log.Info("find sync block", "current height", latestBlock, "start", start)
+	headers, err := m.client.Headers(context.Background(), start, mapprotocol.HeaderCountOfTron)
+	if err != nil {
+		return err
+	}
+	input, err := tron.PackHeaders(headers)
+	if err != nil {
+		m.log.Error("Failed to abi pack", "err", err)
+		return err
+	}
+
+	id := big.NewInt(0).SetUint64(uint64(m.cfg.Id))
+	message := msg.NewSyncToMap(m.cfg.Id, m.cfg.MapChainID, []interface{}{id, input}, m.msgCh)
+	err = m.router.Send(message)
+	if err != nil {
+		m.log.Error("Subscription error: failed to route message", "err", err)
+		return err
+	}
+	return m.waitUntilMsgHandled(1)
+}
+
+// firstAfterMaintenance is whether the block is the one after the block the maintenance is done at. The schedule is
+// read again at every maintenance, the interval may be changed by a proposal.
+func (m *maintainer) firstAfterMaintenance(number uint64) (bool, error) {
+	ctx := context.Background()
+	if m.maintenance == nil {
+		maintenance, err := m.client.Maintenance(ctx)
+		if err != nil {
+			return false, err
+		}
+		m.maintenance = maintenance
+	}
+	var pair [2]*tron.Header
+	for i := range pair {
+		n := number - 2 + uint64(i)
+		h, ok := m.headers[n]
+		if !ok {
+			var err error
+			if h, err = m.client.HeaderByNumber(ctx, n); err != nil {
+				return false, err
+			}
+		}
+		pair[i] = h
+	}
+	m.headers = map[uint64]*tron.Header{number - 1: pair[1]}
+	if !m.maintenance.Maintained(pair[1], pair[0]) {
+		return false, nil
+	}
+	m.log.Info("Maintenance done", "block", pair[1].Number, "time", pair[1].Timestamp)
+	m.maintenance = nil
+	return true, nil
+}
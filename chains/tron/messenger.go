@@ -0,0 +1,24 @@
+package tron
+
+import (
+	"errors"
+)
+
+// errNoEventProof is returned for a messenger syncing to map, the headers of tron commit to the transactions but
+// not to their results and logs, so the events of the mcs can not be proven to the light client on map
+var errNoEventProof = errors.New("tron events can not be proven to map, set syncToMap to false for the messenger")
+
+// messenger relays no events of tron to map, see errNoEventProof, the messages of map are written to tron by the
+// writer of the chain
+type messenger struct {
+	*commonListen
+}
+
+func newMessenger(cs *commonListen) *messenger {
+	return &messenger{commonListen: cs}
+}
+
+func (m *messenger) Sync() error {
+	m.log.Info("Tron messenger relays no events to map")
+	return nil
+}
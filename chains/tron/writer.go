@@ -0,0 +1,217 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	connection "github.com/mapprotocol/compass/connections/tron"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/internal/constant"
+	"github.com/mapprotocol/compass/internal/tron"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/util"
+	"github.com/pkg/errors"
+)
+
+var _ core.Writer = &writer{}
+
+var errTxNotSuccess = errors.New("status not success")
+
+type writer struct {
+	cfg     Config
+	conn    *connection.Connection
+	mc      *mapprotocol.MapContext
+	log     log15.Logger
+	stop    <-chan int
+	sysErr  chan<- error // Reports fatal error to core
+	metrics *metrics.ChainMetrics
+	journal journal.Journaler
+}
+
+func newWriter(conn *connection.Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error,
+	m *metrics.ChainMetrics, jn journal.Journaler, mc *mapprotocol.MapContext) *writer {
+	return &writer{
+		cfg:     *cfg,
+		conn:    conn,
+		mc:      mc,
+		log:     log,
+		stop:    stop,
+		sysErr:  sysErr,
+		metrics: m,
+		journal: jn,
+	}
+}
+
+func (w *writer) start() error {
+	w.log.Debug("Starting tron writer...")
+	err := w.reconcile()
+	if err != nil {
+		w.log.Warn("Failed to reconcile journal", "err", err)
+	}
+	return nil
+}
+
+// ResolveMessage handles any given message based on type
+// A bool is returned to indicate failure/success, this should be ignored except for within tests.
+func (w *writer) ResolveMessage(m msg.Message) bool {
+	w.log.Info("Tron Attempting to resolve message", "type", m.Type, "src", m.Source, "dst", m.Destination)
+
+	switch m.Type {
+	case msg.SyncFromMap:
+		return w.callContractWithMsg(w.cfg.LightNode, m, false)
+	case msg.SwapWithMapProof:
+		return w.callContractWithMsg(w.cfg.McsContract, m, true)
+	default:
+		w.log.Error("Unknown message type received", "type", m.Type)
+		return false
+	}
+}
+
+// callContractWithMsg calls the contract with the calldata of the message until the tx succeeds, the order id of
+// the swap is checked before each attempt
+func (w *writer) callContractWithMsg(addr common.Address, m msg.Message, checkOrderId bool) bool {
+	var errorCount int64
+	var inputHash interface{}
+	if len(m.Payload) > 3 {
+		inputHash = m.Payload[3]
+	}
+	for {
+		select {
+		case <-w.stop:
+			return false
+		default:
+			if checkOrderId {
+				orderId := m.Payload[1].([]byte)
+				exist, err := w.checkOrderId(addr, orderId)
+				if err != nil {
+					w.log.Error("check orderId exist failed ", "err", err, "orderId", common.Bytes2Hex(orderId))
+				}
+				if exist {
+					w.log.Info("Mcs orderId has been processed, Skip this request", "orderId", common.Bytes2Hex(orderId))
+					m.DoneCh <- struct{}{}
+					return true
+				}
+			}
+
+			w.log.Info("Send transaction", "addr", tron.EncodeAddress(addr), "srcHash", inputHash)
+			entry := newEntry(m)
+			txHash, err := w.sendTx(addr, m.Payload[0].([]byte), entry)
+			if err == nil {
+				w.log.Info("Submitted tron tx execution", "tx", txHash, "src", m.Source, "dst", m.Destination, "srcHash", inputHash)
+				err = w.txStatus(txHash)
+				w.finish(entry, err)
+				if err == nil {
+					m.DoneCh <- struct{}{}
+					return true
+				}
+				w.log.Warn("TxHash Status is not successful, will retry", "err", err)
+			} else if w.cfg.SkipError && checkOrderId {
+				w.log.Warn("Execution failed, ignore this error, Continue to the next ", "srcHash", inputHash, "err", err)
+				m.DoneCh <- struct{}{}
+				return true
+			} else {
+				for e := range constant.IgnoreError {
+					if strings.Index(err.Error(), e) != -1 {
+						w.log.Info("Ignore This Error, Continue to the next", "id", m.Destination, "err", err)
+						m.DoneCh <- struct{}{}
+						return true
+					}
+				}
+				w.log.Warn("Execution failed, will retry", "srcHash", inputHash, "err", err)
+			}
+			errorCount++
+			if errorCount >= 10 {
+				util.Alarm(context.Background(), fmt.Sprintf("%s2%s failed, srcHash=%v err is %s",
+					w.mc.Name(m.Source), w.mc.Name(m.Destination), inputHash, err.Error()))
+				errorCount = 0
+			}
+			time.Sleep(constant.TxRetryInterval)
+		}
+	}
+}
+
+// sendTx has the node build the call of the contract, checks and signs it, then broadcasts it. The tx is recorded
+// to journal before and after it is broadcast
+func (w *writer) sendTx(to common.Address, input []byte, e *journal.Entry) (common.Hash, error) {
+	from := w.conn.Keypair().CommonAddress()
+	tx, err := w.conn.Client().TriggerSmartContract(context.Background(), from, to, input, w.conn.FeeLimit())
+	if err != nil {
+		return common.Hash{}, err
+	}
+	err = tx.Sign(w.conn.Keypair().PrivateKey())
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	if e != nil {
+		e.Id = tx.Hash().Hex()
+		e.TxHash = tx.Hash().Hex()
+		e.To = to.Hex()
+		e.GasLimit = uint64(w.conn.FeeLimit())
+		w.record(e, journal.StatusPending, nil)
+	}
+	err = w.conn.Client().BroadcastTransaction(context.Background(), tx)
+	if err != nil {
+		w.log.Error("BroadcastTransaction failed", "error:", err.Error())
+		w.record(e, journal.StatusFailed, err)
+		return common.Hash{}, err
+	}
+	w.record(e, journal.StatusSubmitted, nil)
+	return tx.Hash(), nil
+}
+
+// txStatus waits for the info of the tx, the tx expires in a minute if it is not packed
+func (w *writer) txStatus(txHash common.Hash) error {
+	var count int64
+	for {
+		time.Sleep(constant.BlockRetryInterval)
+		info, err := w.conn.Client().TransactionInfo(context.Background(), txHash)
+		if err == ethereum.NotFound {
+			w.log.Info("Tx is temporary not found, please wait...", "tx", txHash)
+			count++
+			if count == 40 {
+				return err
+			}
+			continue
+		} else if err != nil {
+			return err
+		}
+		if info.Success() {
+			w.log.Info("Tx receipt status is success", "hash", txHash)
+			return nil
+		}
+		return errors.Wrapf(errTxNotSuccess, "txHash(%s), result is (%s %s), message is (%s)", txHash,
+			info.Result, info.Receipt.Result, string(info.ResMessage))
+	}
+}
+
+func (w *writer) checkOrderId(mcs common.Address, input []byte) (bool, error) {
+	var fixedOrderId [32]byte
+	copy(fixedOrderId[:], input)
+	data, err := mapprotocol.PackInput(mapprotocol.Mcs, mapprotocol.MethodOfOrderList, fixedOrderId)
+	if err != nil {
+		return false, err
+	}
+	output, err := w.conn.Client().TriggerConstantContract(context.Background(), w.conn.Keypair().CommonAddress(), mcs, data)
+	if err != nil {
+		return false, errors.Wrap(err, "triggerConstantContract failed")
+	}
+	resp, err := mapprotocol.Mcs.Methods[mapprotocol.MethodOfOrderList].Outputs.Unpack(output)
+	if err != nil {
+		return false, errors.Wrap(err, "output Unpack failed")
+	}
+	var exist bool
+	err = mapprotocol.Mcs.Methods[mapprotocol.MethodOfOrderList].Outputs.Copy(&exist, resp)
+	if err != nil {
+		return false, errors.Wrap(err, "checkOrderId output copy failed")
+	}
+	return exist, nil
+}
@@ -17,6 +17,7 @@ import (
 	gokeystore "github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/mapprotocol/compass/config"
 	"github.com/mapprotocol/compass/keystore"
+	nearkey "github.com/mapprotocol/near-api-go/pkg/types/key"
 	"github.com/urfave/cli/v2"
 )
 
@@ -94,6 +95,13 @@ func handleImportCmd(ctx *cli.Context, dHandler *dataHandler) error {
 		} else {
 			return fmt.Errorf("Must provide a key to import.")
 		}
+	} else if account := ctx.String(config.NearImportFlag.Name); account != "" {
+		// check if --password is set
+		var password []byte = nil
+		if pwdflag := ctx.String(config.PasswordFlag.Name); pwdflag != "" {
+			password = []byte(pwdflag)
+		}
+		_, err = importNearKey(account, ctx.String(config.SubkeyNetworkFlag.Name), ctx.Args().First(), dHandler.datadir, password)
 	} else if privkeyflag := ctx.String(config.PrivateKeyFlag.Name); privkeyflag != "" {
 		// check if --password is set
 		var password []byte = nil
@@ -205,6 +213,56 @@ func importPrivKey(ctx *cli.Context, keytype, datadir, key string, password []by
 
 }
 
+// importNearKey encrypts the key of the near account from the credentials of near-cli into our keystore format,
+// the credentials are read from ~/.near-credentials/<network>/<account>.json unless the file is given
+func importNearKey(account, network, credsFile, datadir string, password []byte) (string, error) {
+	keystorepath, err := keystoreDir(datadir)
+	if err != nil {
+		return "", fmt.Errorf("could not get keystore directory: %w", err)
+	}
+
+	var kp nearkey.KeyPair
+	if credsFile != "" {
+		kp, err = keystore.NearKeyPairFromFile(credsFile, account)
+	} else if network != "" {
+		kp, err = keystore.NearKeyPairFrom(network, "", account)
+	} else {
+		return "", fmt.Errorf("Must provide the network or the credentials file of the near account.")
+	}
+	if err != nil {
+		return "", fmt.Errorf("could not read near credentials: %w", err)
+	}
+
+	fp, err := filepath.Abs(keystore.NearKeyFile(keystorepath, account, kp.PublicKey.String()))
+	if err != nil {
+		return "", fmt.Errorf("invalid filepath: %w", err)
+	}
+
+	file, err := os.OpenFile(filepath.Clean(fp), os.O_EXCL|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return "", err
+	}
+
+	defer func() {
+		err = file.Close()
+		if err != nil {
+			log.Error("import near key: could not close keystore file")
+		}
+	}()
+
+	if password == nil {
+		password = keystore.GetPassword("Enter password to encrypt keystore file:")
+	}
+
+	err = keystore.EncryptNearKeyPair(file, account, kp, password)
+	if err != nil {
+		return "", fmt.Errorf("could not write key to file: %w", err)
+	}
+
+	log.Info("Near key imported", "account", account, "publicKey", kp.PublicKey.String(), "file", fp)
+	return fp, nil
+}
+
 //importEthKey takes an ethereum keystore and converts it to our keystore format
 func importEthKey(filename, datadir string, password, newPassword []byte) (string, error) {
 	keystorepath, err := keystoreDir(datadir)
@@ -279,7 +337,11 @@ func importKey(filename, datadir string) (string, error) {
 		return "", fmt.Errorf("could not read file contents: %w", err)
 	}
 
-	keystorefile, err := filepath.Abs(keystorepath + "/" + ksjson.Address[2:] + ".key")
+	name := keystorepath + "/" + ksjson.Address[2:] + ".key"
+	if ksjson.Type == keystore.NearKeyType {
+		name = keystore.NearKeyFile(keystorepath, ksjson.Address, ksjson.PublicKey)
+	}
+	keystorefile, err := filepath.Abs(name)
 	if err != nil {
 		return "", fmt.Errorf("could not create keystore file path: %w", err)
 	}
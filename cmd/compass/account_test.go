@@ -4,6 +4,7 @@
 package main
 
 import (
+	"crypto/rand"
 	"encoding/json"
 	"flag"
 	"fmt"
@@ -17,6 +18,7 @@ import (
 
 	"github.com/ChainSafe/chainbridge-utils/crypto"
 	"github.com/mapprotocol/compass/keystore"
+	nearkey "github.com/mapprotocol/near-api-go/pkg/types/key"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v2"
 )
@@ -313,6 +315,48 @@ func TestImportEthKey(t *testing.T) {
 	}
 }
 
+func TestImportNearKey(t *testing.T) {
+	kp, err := nearkey.GenerateKeyPair(nearkey.KeyTypeED25519, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := "relayer.testnet"
+	creds, err := json.Marshal(map[string]string{
+		"account_id":  account,
+		"public_key":  kp.PublicKey.String(),
+		"private_key": kp.PrivateEncoded(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	credsFile := "../../near-test.json"
+	ioutil.WriteFile(credsFile, creds, 0600)
+
+	defer os.RemoveAll(credsFile)
+	defer os.RemoveAll(testKeystoreDir)
+
+	if _, err = importNearKey("other.testnet", "", credsFile, testKeystoreDir, testPassword); err == nil {
+		t.Fatal("credentials of another account are imported")
+	}
+	keyfile, err := importNearKey(account, "", credsFile, testKeystoreDir, testPassword)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if filepath.Base(keyfile) != account+"."+strings.TrimPrefix(kp.PublicKey.String(), "ed25519:")+".key" {
+		t.Fatalf("keyfile %s", keyfile)
+	}
+
+	os.Setenv(keystore.EnvPassword, string(testPassword))
+	defer os.Unsetenv(keystore.EnvPassword)
+	loaded, err := keystore.NearKeyPair("testnet", testKeystoreDir, "", account, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.PrivateEncoded() != kp.PrivateEncoded() {
+		t.Fatal("decrypted key is not the one imported")
+	}
+}
+
 func TestImportKey_withPk(t *testing.T) {
 	keyfile, err := importPrivKey(cli.NewContext(app, nil, nil), "", testKeystoreDir, "000000000000000000000000000000000000000000000000000000416c696365", testPassword)
 	if err != nil {
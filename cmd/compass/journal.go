@@ -0,0 +1,68 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/mapprotocol/compass/config"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/urfave/cli/v2"
+)
+
+// handleJournalCmd prints the journal entries matching the filter flags
+func handleJournalCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	entries, err := journal.LoadDir(ctx.String(config.JournalPathFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to load journal: %w", err)
+	}
+
+	var (
+		chainId = msg.ChainId(ctx.Uint64(config.JournalChainFlag.Name))
+		status  = ctx.String(config.JournalStatusFlag.Name)
+		srcHash = ctx.String(config.JournalSrcHashFlag.Name)
+		orderId = ctx.String(config.JournalOrderIdFlag.Name)
+		tx      = ctx.String(config.JournalTxFlag.Name)
+	)
+	ret := make([]*journal.Entry, 0)
+	for _, e := range entries {
+		if chainId != 0 && e.Source != chainId && e.Destination != chainId {
+			continue
+		}
+		if status == "unfinished" && e.Status.Finished() {
+			continue
+		}
+		if status != "" && status != "unfinished" && string(e.Status) != status {
+			continue
+		}
+		if srcHash != "" && !strings.EqualFold(e.SrcHash, srcHash) {
+			continue
+		}
+		if orderId != "" && !strings.EqualFold(e.OrderId, orderId) {
+			continue
+		}
+		if tx != "" && !strings.EqualFold(e.TxHash, tx) {
+			continue
+		}
+		ret = append(ret, e)
+	}
+
+	fmt.Printf("=== Found %d entries ===\n", len(ret))
+	for _, e := range ret {
+		data, err := json.Marshal(e)
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	}
+	return nil
+}
@@ -14,29 +14,31 @@ import (
 	"os"
 	"strconv"
 
-	"github.com/mapprotocol/compass/chains/platon"
-
-	"github.com/mapprotocol/compass/chains/bsc"
-
 	"github.com/ChainSafe/chainbridge-utils/metrics/health"
 	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	log "github.com/ChainSafe/log15"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/mapprotocol/compass/chains"
-	"github.com/mapprotocol/compass/chains/eth2"
-	"github.com/mapprotocol/compass/chains/ethereum"
-	"github.com/mapprotocol/compass/chains/klaytn"
-	"github.com/mapprotocol/compass/chains/matic"
-	"github.com/mapprotocol/compass/chains/near"
 	"github.com/mapprotocol/compass/config"
 	"github.com/mapprotocol/compass/core"
-	chain2 "github.com/mapprotocol/compass/internal/chain"
 	"github.com/mapprotocol/compass/internal/monitor"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 	"github.com/urfave/cli/v2"
+
+	// register the chain types
+	_ "github.com/mapprotocol/compass/chains/arbitrum"
+	_ "github.com/mapprotocol/compass/chains/avalanche"
+	_ "github.com/mapprotocol/compass/chains/bsc"
+	_ "github.com/mapprotocol/compass/chains/eth2"
+	_ "github.com/mapprotocol/compass/chains/ethereum"
+	_ "github.com/mapprotocol/compass/chains/klaytn"
+	_ "github.com/mapprotocol/compass/chains/matic"
+	_ "github.com/mapprotocol/compass/chains/near"
+	_ "github.com/mapprotocol/compass/chains/opstack"
+	_ "github.com/mapprotocol/compass/chains/platon"
+	_ "github.com/mapprotocol/compass/chains/tron"
 )
 
 var app = cli.NewApp()
@@ -46,6 +48,8 @@ var cliFlags = []cli.Flag{
 	config.VerbosityFlag,
 	config.KeystorePathFlag,
 	config.BlockstorePathFlag,
+	config.JournalPathFlag,
+	config.DeadLetterPathFlag,
 	config.FreshStartFlag,
 	config.LatestBlockFlag,
 	config.MetricsFlag,
@@ -67,6 +71,7 @@ var devFlags = []cli.Flag{
 
 var importFlags = []cli.Flag{
 	config.EthereumImportFlag,
+	config.NearImportFlag,
 	config.PrivateKeyFlag,
 	config.Sr25519Flag,
 	config.Secp256k1Flag,
@@ -85,6 +90,24 @@ var bindFlags = []cli.Flag{
 	config.Worker,
 }
 
+var journalFlags = []cli.Flag{
+	config.JournalPathFlag,
+	config.JournalChainFlag,
+	config.JournalStatusFlag,
+	config.JournalSrcHashFlag,
+	config.JournalOrderIdFlag,
+	config.JournalTxFlag,
+}
+
+var proofBuildFlags = []cli.Flag{
+	config.ConfigFileFlag,
+	config.VerbosityFlag,
+	config.ProofChainFlag,
+	config.ProofTxFlag,
+	config.ProofLogIndexFlag,
+	config.ProofOutFlag,
+}
+
 var monitorFlags = []cli.Flag{
 	config.ConfigFileFlag,
 	config.ExposePortFlag,
@@ -97,6 +120,7 @@ var accountCommand = cli.Command{
 		"\tTo generate a new account (key type generated is determined on the flag passed in): compass accounts generate\n" +
 		"\tTo import a keystore file: compass accounts import path/to/file\n" +
 		"\tTo import a geth keystore file: compass accounts import --ethereum path/to/file\n" +
+		"\tTo import a near key: compass accounts import --near account --network testnet\n" +
 		"\tTo import a private key file: compass accounts import --privateKey private_key\n" +
 		"\tTo list keys: compass accounts list",
 	Subcommands: []*cli.Command{
@@ -116,6 +140,8 @@ var accountCommand = cli.Command{
 			Description: "The import subcommand is used to import a keystore for the bridge.\n" +
 				"\tA path to the keystore must be provided\n" +
 				"\tUse --ethereum to import an ethereum keystore from external sources such as geth\n" +
+				"\tUse --near with --network to import the key of a near account from ~/.near-credentials,\n" +
+				"\tor from the credentials file given\n" +
 				"\tUse --privateKey to create a keystore from a provided private key.",
 		},
 		{
@@ -175,6 +201,43 @@ var monitorCommand = cli.Command{
 	Flags:       append(app.Flags, monitorFlags...),
 }
 
+var journalCommand = cli.Command{
+	Name:  "journal",
+	Usage: "query the tx journal",
+	Description: "The journal command is used to query the txs sent by writers.\n" +
+		"\tTo list unfinished txs: compass journal --status unfinished\n" +
+		"\tTo find the destination tx of an order: compass journal --orderId 0x0...",
+	Action: handleJournalCmd,
+	Flags:  journalFlags,
+}
+
+var proofCommand = cli.Command{
+	Name:  "proof",
+	Usage: "build and verify receipt proofs offline",
+	Description: "The proof command is used to inspect the proof of an event without running the messenger.\n" +
+		"\tTo build the proof of a tx: compass proof build --config config.json --chain 56 --tx 0x0... --out proof.json\n" +
+		"\tTo verify a proof file: compass proof verify proof.json",
+	Subcommands: []*cli.Command{
+		{
+			Action: handleProofBuildCmd,
+			Name:   "build",
+			Usage:  "build the proof of the mcs event of a tx",
+			Flags:  proofBuildFlags,
+			Description: "The build subcommand assembles the proof of an event with the chain in the config.\n" +
+				"\tIt writes the receipt proof, its header and receipt, and the mcs calldata as json.\n" +
+				"\tUse --logIndex to choose the log when the tx emits more than one event.",
+		},
+		{
+			Action:      handleProofVerifyCmd,
+			Name:        "verify",
+			Usage:       "verify a proof file",
+			Flags:       []cli.Flag{config.VerbosityFlag},
+			ArgsUsage:   "<proof.json>",
+			Description: "The verify subcommand checks the proof file against the receipts root of its header.\n",
+		},
+	},
+}
+
 var (
 	Version = "1.0.0"
 )
@@ -193,6 +256,8 @@ func init() {
 		&maintainerCommand,
 		&messengerCommand,
 		&monitorCommand,
+		&journalCommand,
+		&proofCommand,
 	}
 
 	app.Flags = append(app.Flags, cliFlags...)
@@ -271,15 +336,31 @@ func run(ctx *cli.Context, role mapprotocol.Role) error {
 	allChains = append(allChains, cfg.MapChain)
 	allChains = append(allChains, cfg.Chains...)
 
+	// the chains of the relayer share the map context, the map chain is set to it as the first chain initialized
+	mc := mapprotocol.NewMapContext()
+	chainConfigs := make([]*core.ChainConfig, 0, len(allChains))
+	chainTypes := make([]*chains.Type, 0, len(allChains))
 	for idx, chain := range allChains {
 		chainId, err := strconv.Atoi(chain.Id)
 		if err != nil {
 			return err
 		}
+		t, err := chains.Lookup(chain.Type)
+		if err != nil {
+			return fmt.Errorf("chain %s: %w, known types are %v", chain.Name, err, chains.Types())
+		}
+		if idx == 0 && t.InitMap == nil {
+			return fmt.Errorf("chain type (%s) can not be the map chain", chain.Type)
+		}
 		// write Map chain id to opts
 		chain.Opts[config.MapChainID] = cfg.MapChain.Id
+		chainType := chain.Type
+		if idx == 0 {
+			chainType = chains.Map
+		}
 		chainConfig := &core.ChainConfig{
 			Name:             chain.Name,
+			Type:             chainType,
 			Id:               msg.ChainId(chainId),
 			Endpoint:         chain.Endpoint,
 			From:             chain.From,
@@ -288,56 +369,40 @@ func run(ctx *cli.Context, role mapprotocol.Role) error {
 			NearKeystorePath: chain.KeystorePath,
 			Insecure:         insecure,
 			BlockstorePath:   ctx.String(config.BlockstorePathFlag.Name),
+			JournalPath:      ctx.String(config.JournalPathFlag.Name),
+			DeadLetterPath:   ctx.String(config.DeadLetterPathFlag.Name),
 			FreshStart:       ctx.Bool(config.FreshStartFlag.Name),
 			LatestBlock:      ctx.Bool(config.LatestBlockFlag.Name),
 			Opts:             chain.Opts,
 			SkipError:        ctx.Bool(config.SkipErrorFlag.Name),
 		}
-		var (
-			newChain core.Chain
-			m        *metrics.ChainMetrics
-		)
+		// the configs of all chains are checked before any of them connects
+		if err = t.CheckConfig(chainConfig); err != nil {
+			return fmt.Errorf("chain %s: %w", chain.Name, err)
+		}
+		chainConfigs = append(chainConfigs, chainConfig)
+		chainTypes = append(chainTypes, t)
+	}
 
+	for idx, chainConfig := range chainConfigs {
+		var m *metrics.ChainMetrics
 		logger := log.Root().New("chain", chainConfig.Name)
 		if ctx.Bool(config.MetricsFlag.Name) {
-			m = metrics.NewChainMetrics(chain.Name)
+			m = metrics.NewChainMetrics(chainConfig.Name)
 		}
 		logger.Info("This task set skip error", "skip", ctx.Bool(config.SkipErrorFlag.Name))
 
-		switch chain.Type {
-		case chains.Ethereum:
-			newChain, err = ethereum.InitializeChain(chainConfig, logger, sysErr, m, role)
-			if err != nil {
-				return err
-			}
-			if idx == 0 {
-				mapprotocol.GlobalMapConn = newChain.(*ethereum.Chain).EthClient()
-				mapprotocol.Init2MapHeightByLight(common.HexToAddress(chainConfig.Opts[chain2.LightNode]))
-				mapprotocol.Init2GetEth22MapNumber(common.HexToAddress(chainConfig.Opts[chain2.LightNode]))
-				mapprotocol.InitOtherChain2MapHeight(common.HexToAddress(chainConfig.Opts[chain2.LightNode]))
-				mapprotocol.InitOtherChain2MapVerifyRange(common.HexToAddress(chainConfig.Opts[chain2.LightNode]))
-			}
-		case chains.Near:
-			newChain, err = near.InitializeChain(chainConfig, logger, sysErr, m, role)
-		case chains.Bsc:
-			newChain, err = bsc.InitializeChain(chainConfig, logger, sysErr, m, role)
-		case chains.Matic:
-			newChain, err = matic.InitializeChain(chainConfig, logger, sysErr, m, role)
-		case chains.Klaytn:
-			newChain, err = klaytn.InitializeChain(chainConfig, logger, sysErr, m, role)
-		case chains.Eth2:
-			newChain, err = eth2.InitializeChain(chainConfig, logger, sysErr, m, role)
-		case chains.Platon:
-			newChain, err = platon.InitializeChain(chainConfig, logger, sysErr, m, role)
-		default:
-			return errors.New("unrecognized Chain Type")
-		}
+		newChain, err := chainTypes[idx].Initialize(chainConfig, logger, sysErr, m, role, mc)
 		if err != nil {
 			return err
 		}
+		if idx == 0 {
+			if err = chainTypes[idx].InitMap(newChain, chainConfig, mc); err != nil {
+				return err
+			}
+		}
 
-		mapprotocol.OnlineChaId[chainConfig.Id] = chainConfig.Name
-		mapprotocol.OnlineChainCfg[chainConfig.Id] = chainConfig
+		mc.AddChain(chainConfig)
 		c.AddChain(newChain)
 	}
 
@@ -369,7 +434,7 @@ func run(ctx *cli.Context, role mapprotocol.Role) error {
 	if role == mapprotocol.RoleOfMonitor {
 		port := ctx.Int(config.ExposePortFlag.Name)
 		mux := http.NewServeMux()
-		mux.HandleFunc("/get/proof", monitor.Handler)
+		mux.HandleFunc("/get/proof", monitor.Handler(mc))
 
 		handler := cors.Default().Handler(mux)
 		err := http.ListenAndServe(fmt.Sprintf(":%d", port), handler)
@@ -0,0 +1,193 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"strconv"
+
+	log "github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/mapprotocol/compass/chains"
+	"github.com/mapprotocol/compass/config"
+	"github.com/mapprotocol/compass/core"
+	chain2 "github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/urfave/cli/v2"
+)
+
+// handleProofBuildCmd assembles the proof of an event like the messenger does, and writes it out decoded
+func handleProofBuildCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.GetConfig(ctx)
+	if err != nil {
+		return err
+	}
+	mapId, err := strconv.ParseUint(cfg.MapChain.Id, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	chainId := msg.ChainId(ctx.Uint64(config.ProofChainFlag.Name))
+	var (
+		raw       *config.RawChainConfig
+		chainType string
+	)
+	allChains := append([]config.RawChainConfig{cfg.MapChain}, cfg.Chains...)
+	mc := mapprotocol.NewMapContext()
+	for idx := range allChains {
+		id, err := strconv.ParseUint(allChains[idx].Id, 10, 64)
+		if err != nil {
+			return err
+		}
+		// the mcs packs the proof of map for the destination by its name
+		mc.AddChain(&core.ChainConfig{Name: allChains[idx].Name, Id: msg.ChainId(id)})
+		if msg.ChainId(id) != chainId {
+			continue
+		}
+		raw = &allChains[idx]
+		chainType = raw.Type
+		if idx == 0 {
+			chainType = chains.Map
+		}
+	}
+	if raw == nil {
+		return fmt.Errorf("chain %d is not in the config", chainId)
+	}
+	txHash := ctx.String(config.ProofTxFlag.Name)
+	if txHash == "" {
+		return errors.New("tx hash is required")
+	}
+
+	rpcClient, err := rpc.DialContext(ctx.Context, raw.Endpoint)
+	if err != nil {
+		return err
+	}
+	client := ethclient.NewClient(rpcClient)
+	defer client.Close()
+
+	pb, err := proof.New(chainType, &proof.Config{
+		Id:         chainId,
+		MapChainID: msg.ChainId(mapId),
+		Endpoint:   raw.Endpoint,
+		Mcs:        common.HexToAddress(raw.Opts[chain2.McsOpt]),
+		Client:     client,
+		Opts:       raw.Opts,
+		Map:        mc,
+	})
+	if err != nil {
+		return err
+	}
+
+	receipt, err := client.TransactionReceipt(ctx.Context, common.HexToHash(txHash))
+	if err != nil {
+		return fmt.Errorf("failed to get receipt of %s: %w", txHash, err)
+	}
+	l, err := findLog(receipt, ctx.Int(config.ProofLogIndexFlag.Name))
+	if err != nil {
+		return err
+	}
+	receipts, err := pb.Receipts(ctx.Context, receipt.BlockNumber)
+	if err != nil {
+		return err
+	}
+	p, err := pb.Build(ctx.Context, l, receipts)
+	if err != nil {
+		return err
+	}
+	header, err := rawHeader(ctx.Context, rpcClient, receipt.BlockNumber)
+	if err != nil {
+		return err
+	}
+
+	f := proof.NewFile(chainId, chainType, header, receipt, l, p)
+	if err = f.Verify(); err != nil {
+		// the proof is still written out, finding out why it is invalid is what it is built for
+		log.Error("Proof verification failed", "tx", txHash, "err", err)
+	} else {
+		log.Info("Proof verified", "tx", txHash, "receiptsRoot", f.ReceiptsRoot, "nodes", len(f.Nodes))
+	}
+
+	data, err := json.MarshalIndent(f, "", "  ")
+	if err != nil {
+		return err
+	}
+	out := ctx.String(config.ProofOutFlag.Name)
+	if out == "" {
+		fmt.Println(string(data))
+		return nil
+	}
+	return ioutil.WriteFile(out, append(data, '\n'), 0644)
+}
+
+// handleProofVerifyCmd checks a proof written by the build subcommand again, without the chain
+func handleProofVerifyCmd(ctx *cli.Context) error {
+	err := startLogger(ctx)
+	if err != nil {
+		return err
+	}
+
+	path := ctx.Args().First()
+	if path == "" {
+		return errors.New("path of the proof file is required")
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	f := &proof.File{}
+	if err = json.Unmarshal(data, f); err != nil {
+		return fmt.Errorf("failed to decode %s: %w", path, err)
+	}
+	if err = f.Verify(); err != nil {
+		return err
+	}
+	fmt.Printf("Proof of tx %s on chain %d is valid against receipts root %s\n", f.TxHash.Hex(), f.ChainId, f.ReceiptsRoot.Hex())
+	return nil
+}
+
+// findLog returns the log at the index of the block, or the first mcs event of the receipt when index is negative
+func findLog(receipt *types.Receipt, index int) (*types.Log, error) {
+	for _, l := range receipt.Logs {
+		if index >= 0 && l.Index == uint(index) {
+			return l, nil
+		}
+		if index < 0 && len(l.Topics) != 0 {
+			if _, ok := mapprotocol.Event[l.Topics[0]]; ok {
+				return l, nil
+			}
+		}
+	}
+	if index >= 0 {
+		return nil, fmt.Errorf("tx %s has no log %d", receipt.TxHash.Hex(), index)
+	}
+	return nil, fmt.Errorf("tx %s has no mcs event", receipt.TxHash.Hex())
+}
+
+func rawHeader(ctx context.Context, c *rpc.Client, number *big.Int) (json.RawMessage, error) {
+	var header json.RawMessage
+	err := c.CallContext(ctx, &header, "eth_getBlockByNumber", hexutil.EncodeBig(number), false)
+	if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 || string(header) == "null" {
+		return nil, fmt.Errorf("block %s not found", number)
+	}
+	return header, nil
+}
@@ -60,7 +60,8 @@ func handleRegisterCmd(ctx *cli.Context) error {
 
 	logger := log.Root().New("register", accountAddr)
 	sysErr := make(chan error)
-	mapChain, err := ethereum.InitializeChain(chainConfig, logger, sysErr, m, mapprotocol.RoleOfMaintainer)
+	mapChain, err := ethereum.InitializeChain(chainConfig, logger, sysErr, m, mapprotocol.RoleOfMaintainer,
+		mapprotocol.NewMapContext())
 	if err != nil {
 		return err
 	}
@@ -115,7 +116,8 @@ func handleBindCmd(ctx *cli.Context) error {
 
 	logger := log.Root().New("bind", relayerAddr)
 	sysErr := make(chan error)
-	mapChain, err := ethereum.InitializeChain(chainConfig, logger, sysErr, m, mapprotocol.RoleOfMaintainer)
+	mapChain, err := ethereum.InitializeChain(chainConfig, logger, sysErr, m, mapprotocol.RoleOfMaintainer,
+		mapprotocol.NewMapContext())
 	if err != nil {
 		return err
 	}
@@ -37,6 +37,18 @@ var (
 		Value: "", // Empty will use home dir
 	}
 
+	JournalPathFlag = &cli.StringFlag{
+		Name:  "journal",
+		Usage: "Specify path for tx journal",
+		Value: "", // Empty will use home dir
+	}
+
+	DeadLetterPathFlag = &cli.StringFlag{
+		Name:  "deadletter",
+		Usage: "Specify path for dead-letter of events which fail local proof verification",
+		Value: "", // Empty will use home dir
+	}
+
 	FreshStartFlag = &cli.BoolFlag{
 		Name:  "fresh",
 		Usage: "Disables loading from blockstore at start. Opts will still be used if specified.",
@@ -92,13 +104,17 @@ var (
 		Name:  "ethereum",
 		Usage: "Import an existing ethereum keystore, such as from geth.",
 	}
+	NearImportFlag = &cli.StringFlag{
+		Name:  "near",
+		Usage: "Import the key of a near account from the credentials of near-cli, the network is given by --network.",
+	}
 	PrivateKeyFlag = &cli.StringFlag{
 		Name:  "privateKey",
 		Usage: "Import a hex representation of a private key into a keystore.",
 	}
 	SubkeyNetworkFlag = &cli.StringFlag{
 		Name:        "network",
-		Usage:       "Specify the network to use for the address encoding (substrate/polkadot/centrifuge)",
+		Usage:       "Specify the network to use for the address encoding (substrate/polkadot/centrifuge), or the network of the near-cli credentials with --near (mainnet/testnet)",
 		DefaultText: "substrate",
 	}
 )
@@ -136,6 +152,51 @@ var (
 	}
 )
 
+// Journal query flags
+var (
+	JournalChainFlag = &cli.Uint64Flag{
+		Name:  "chain",
+		Usage: "Filter by source or destination chain id",
+	}
+	JournalStatusFlag = &cli.StringFlag{
+		Name:  "status",
+		Usage: "Filter by status (pending/submitted/success/failed/dropped), use 'unfinished' for the ones not final",
+	}
+	JournalSrcHashFlag = &cli.StringFlag{
+		Name:  "srcHash",
+		Usage: "Filter by source tx hash",
+	}
+	JournalOrderIdFlag = &cli.StringFlag{
+		Name:  "orderId",
+		Usage: "Filter by order id",
+	}
+	JournalTxFlag = &cli.StringFlag{
+		Name:  "tx",
+		Usage: "Filter by destination tx hash",
+	}
+)
+
+// Proof flags
+var (
+	ProofChainFlag = &cli.Uint64Flag{
+		Name:  "chain",
+		Usage: "Id of the chain the tx is on",
+	}
+	ProofTxFlag = &cli.StringFlag{
+		Name:  "tx",
+		Usage: "Hash of the tx which emits the event",
+	}
+	ProofLogIndexFlag = &cli.IntFlag{
+		Name:  "logIndex",
+		Usage: "Index in the block of the log to prove, default is the first mcs event of the tx",
+		Value: -1,
+	}
+	ProofOutFlag = &cli.StringFlag{
+		Name:  "out",
+		Usage: "File to write the proof to, default is stdout",
+	}
+)
+
 var (
 	ExposePortFlag = &cli.IntFlag{
 		Name:  "exposePort",
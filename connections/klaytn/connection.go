@@ -0,0 +1,47 @@
+package klaytn
+
+import (
+	"math/big"
+
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ChainSafe/log15"
+	"github.com/mapprotocol/compass/connections/ethereum"
+	"github.com/mapprotocol/compass/internal/chain"
+	"github.com/mapprotocol/compass/internal/klaytn"
+)
+
+// Connection is the connection of a klaytn chain, the klaytn client querying the klaytn specific fields of the
+// headers lives with the chain so several klaytn chains can be configured together
+type Connection struct {
+	endpoint string
+	chain.Connection
+	kConn *klaytn.Client
+}
+
+// NewConnection returns an uninitialized connection, must call Connection.Connect() before using.
+func NewConnection(endpoint string, http bool, kp *secp256k1.Keypair, log log15.Logger, gasLimit, gasPrice *big.Int,
+	gasMultiplier float64, gsnApiKey, gsnSpeed string) chain.Connection {
+	conn := ethereum.NewConnection(endpoint, http, kp, log, gasLimit, gasPrice, gasMultiplier, gsnApiKey, gsnSpeed)
+	return &Connection{
+		Connection: conn,
+		endpoint:   endpoint,
+	}
+}
+
+func (c *Connection) KClient() *klaytn.Client {
+	return c.kConn
+}
+
+// Connect starts the ethereum WS connection and the klaytn client
+func (c *Connection) Connect() error {
+	if err := c.Connection.Connect(); err != nil {
+		return err
+	}
+
+	client, err := klaytn.DialHttp(c.endpoint, true)
+	if err != nil {
+		return err
+	}
+	c.kConn = client
+	return nil
+}
@@ -0,0 +1,118 @@
+package tron
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ChainSafe/log15"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/internal/tron"
+)
+
+var BlockRetryInterval = time.Second * 5
+
+// Connection talks to a tron full node over its http api. The blocks are read at the solidified block, the txs are
+// built by the node and signed with the secp256k1 key of the keystore.
+type Connection struct {
+	endpoint string
+	kp       *secp256k1.Keypair
+	feeLimit int64
+	conn     *tron.Client
+	log      log15.Logger
+	stop     chan int // All routines should exit when this channel is closed
+}
+
+// NewConnection returns an uninitialized connection, must call Connection.Connect() before using.
+func NewConnection(endpoint string, kp *secp256k1.Keypair, log log15.Logger, feeLimit int64) *Connection {
+	return &Connection{
+		endpoint: endpoint,
+		kp:       kp,
+		feeLimit: feeLimit,
+		log:      log,
+		stop:     make(chan int),
+	}
+}
+
+// Connect checks the node answers with its latest block
+func (c *Connection) Connect() error {
+	c.log.Info("Connecting to tron chain...", "url", c.endpoint)
+	client := tron.NewClient(c.endpoint)
+	h, err := client.NowBlock(context.Background())
+	if err != nil {
+		return err
+	}
+	c.log.Info("Connecting success tron chain...", "block", h.Number, "from", tron.EncodeAddress(c.kp.CommonAddress()))
+	c.conn = client
+	return nil
+}
+
+func (c *Connection) Keypair() *secp256k1.Keypair {
+	return c.kp
+}
+
+func (c *Connection) Client() *tron.Client {
+	return c.conn
+}
+
+// FeeLimit is the max sun a tx burns for its energy
+func (c *Connection) FeeLimit() int64 {
+	return c.feeLimit
+}
+
+// LatestBlock returns the latest solidified block, the blocks after it can still be switched
+func (c *Connection) LatestBlock() (*big.Int, error) {
+	h, err := c.conn.SolidBlock(context.Background())
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetUint64(h.Number), nil
+}
+
+// EnsureHasBytecode asserts if contract code exists at the specified address
+func (c *Connection) EnsureHasBytecode(addr common.Address) error {
+	ok, err := c.conn.HasContract(context.Background(), addr)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("no bytecode found at %s", tron.EncodeAddress(addr))
+	}
+	return nil
+}
+
+// WaitForBlock will poll for the block number until the current block is equal or greater.
+// If delay is provided it will wait until currBlock - delay = targetBlock
+func (c *Connection) WaitForBlock(targetBlock *big.Int, delay *big.Int) error {
+	for {
+		select {
+		case <-c.stop:
+			return errors.New("connection terminated")
+		default:
+			currBlock, err := c.LatestBlock()
+			if err != nil {
+				return err
+			}
+
+			if delay != nil {
+				currBlock.Sub(currBlock, delay)
+			}
+
+			// Equal or greater than target
+			if currBlock.Cmp(targetBlock) >= 0 {
+				return nil
+			}
+			c.log.Trace("Block not ready, waiting", "target", targetBlock, "current", currBlock, "delay", delay)
+			time.Sleep(BlockRetryInterval)
+			continue
+		}
+	}
+}
+
+// Close stops any running routines
+func (c *Connection) Close() {
+	close(c.stop)
+}
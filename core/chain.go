@@ -19,6 +19,7 @@ type Chain interface {
 
 type ChainConfig struct {
 	Name             string            // Human-readable chain name
+	Type             string            // Chain type, map for the map chain
 	Id               msg.ChainId       // ChainID
 	Endpoint         string            // url for rpc endpoint
 	Network          string            //
@@ -27,6 +28,8 @@ type ChainConfig struct {
 	NearKeystorePath string            // Location of key files
 	Insecure         bool              // Indicated whether the test keyring should be used
 	BlockstorePath   string            // Location of blockstore
+	JournalPath      string            // Location of tx journal
+	DeadLetterPath   string            // Location of dead-letter
 	FreshStart       bool              // If true, blockstore is ignored at start.
 	LatestBlock      bool              // If true, overrides blockstore or latest block in config and starts from current block
 	Opts             map[string]string // Per chain options
@@ -0,0 +1,95 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package deadletter
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+const (
+	PathPostfix = ".compass/deadletter"
+	FileExt     = ".deadletter"
+)
+
+// Letter is an event which is not routed because it can not be handled by retrying,
+// it keeps everything needed to look into it by hand.
+type Letter struct {
+	Source      msg.ChainId       `json:"source"`
+	Destination msg.ChainId       `json:"destination,omitempty"`
+	BlockNumber uint64            `json:"blockNumber"`
+	TxHash      string            `json:"txHash"`
+	LogIndex    uint              `json:"logIndex"`
+	OrderId     string            `json:"orderId,omitempty"`
+	Method      string            `json:"method,omitempty"`
+	Reason      string            `json:"reason"`
+	Diagnostics map[string]string `json:"diagnostics,omitempty"`
+	Time        time.Time         `json:"time"`
+}
+
+type DeadLetterer interface {
+	Put(*Letter) error
+}
+
+var _ DeadLetterer = &EmptyDeadLetter{}
+var _ DeadLetterer = &DeadLetter{}
+
+// Dummy dead-letter for testing only
+type EmptyDeadLetter struct{}
+
+func (d *EmptyDeadLetter) Put(_ *Letter) error { return nil }
+
+// DeadLetter implements DeadLetterer with a json-lines file per chain/relayer/role.
+type DeadLetter struct {
+	path     string // Path excluding filename
+	fullPath string
+	lock     sync.Mutex
+}
+
+func NewDeadLetter(path string, chain msg.ChainId, relayer string, role mapprotocol.Role) (*DeadLetter, error) {
+	if path == "" {
+		def, err := getDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = def
+	}
+
+	return &DeadLetter{
+		path:     path,
+		fullPath: filepath.Join(path, getFileName(chain, relayer, role)),
+	}, nil
+}
+
+// Put writes the letter to the end of the dead-letter file.
+func (d *DeadLetter) Put(l *Letter) error {
+	d.lock.Lock()
+	defer d.lock.Unlock()
+
+	if l.Time.IsZero() {
+		l.Time = time.Now()
+	}
+	return journal.AppendLine(d.path, d.fullPath, l)
+}
+
+func getFileName(chain msg.ChainId, relayer string, role mapprotocol.Role) string {
+	return fmt.Sprintf("%s-%d-%s%s", relayer, chain, role, FileExt)
+}
+
+// getDefaultPath returns the home directory joined with PathPostfix
+func getDefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, PathPostfix), nil
+}
@@ -0,0 +1,60 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package deadletter
+
+import (
+	"bufio"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func TestPut(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "deadletter")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	d, err := NewDeadLetter(dir, msg.ChainId(56), "0x01", mapprotocol.RoleOfMessenger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	letters := []*Letter{
+		{Source: 56, BlockNumber: 1, TxHash: "0xaa", Reason: "invalid receipt proof", Diagnostics: map[string]string{"keyIndex": "0x0800"}},
+		{Source: 56, BlockNumber: 2, TxHash: "0xbb", Reason: "invalid receipt proof"},
+	}
+	for _, l := range letters {
+		if err = d.Put(l); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	f, err := os.Open(filepath.Join(dir, getFileName(56, "0x01", mapprotocol.RoleOfMessenger)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	got := make([]*Letter, 0)
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		l := &Letter{}
+		if err = json.Unmarshal(scanner.Bytes(), l); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, l)
+	}
+	if len(got) != len(letters) {
+		t.Fatalf("Expected: %d got: %d", len(letters), len(got))
+	}
+	if got[0].TxHash != "0xaa" || got[0].Diagnostics["keyIndex"] != "0x0800" || got[0].Time.IsZero() {
+		t.Fatalf("unexpected letter %+v", got[0])
+	}
+}
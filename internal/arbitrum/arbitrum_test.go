@@ -0,0 +1,311 @@
+package arbitrum
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/internal/proof/prooftest"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+)
+
+var rollupAddr = common.HexToAddress("0x5ef0d09d1e6204141b4d37530808ed19f60fba35")
+
+// the arbitrum tx types in the receipt trie
+const (
+	arbitrumDepositTxType  = 0x64
+	arbitrumInternalTxType = 0x6a
+)
+
+func testHeader(number int64, receiptsRoot common.Hash) *Header {
+	var mix common.Hash
+	binary.BigEndian.PutUint64(mix[:8], 41213)
+	binary.BigEndian.PutUint64(mix[8:16], 20000000+uint64(number)/4)
+	binary.BigEndian.PutUint64(mix[16:24], 32)
+	return &Header{
+		ParentHash:  crypto.Keccak256Hash(big.NewInt(number - 1).Bytes()),
+		UncleHash:   types.EmptyUncleHash,
+		Coinbase:    common.HexToAddress("0xa4b000000000000000000073657175656e636572"),
+		Root:        crypto.Keccak256Hash([]byte("state")),
+		TxHash:      crypto.Keccak256Hash(big.NewInt(number).Bytes()),
+		ReceiptHash: receiptsRoot,
+		Difficulty:  big.NewInt(1),
+		Number:      big.NewInt(number),
+		GasLimit:    1125899906842624,
+		GasUsed:     152000,
+		Time:        uint64(1700000000 + number/4),
+		Extra:       crypto.Keccak256([]byte("send root")),
+		MixDigest:   mix,
+		Nonce:       types.EncodeNonce(1651236),
+		BaseFee:     big.NewInt(10000000),
+	}
+}
+
+func testReceipts() []*types.Receipt {
+	return prooftest.Receipts(
+		&types.Receipt{Type: arbitrumInternalTxType, Status: 1},
+		&types.Receipt{Type: arbitrumDepositTxType, Status: 1},
+		&types.Receipt{Type: types.DynamicFeeTxType, Status: 1, CumulativeGasUsed: 120000, Logs: []*types.Log{prooftest.TransferOut()}},
+		&types.Receipt{Type: types.LegacyTxType, Status: 0, CumulativeGasUsed: 152000},
+	)
+}
+
+func TestHeaderJSON(t *testing.T) {
+	h := testHeader(150000000, types.EmptyRootHash)
+	var dec Header
+	enc := prooftest.CheckJSON(t, h, &dec)
+	if dec.SendRoot() != common.BytesToHash(h.Extra) || dec.SendCount() != 41213 || dec.L1BlockNumber() != 20000000+150000000/4 ||
+		dec.ArbOSVersion() != 32 {
+		t.Fatalf("nitro fields %s %d %d %d", dec.SendRoot(), dec.SendCount(), dec.L1BlockNumber(), dec.ArbOSVersion())
+	}
+
+	// the send root the node returns is not the one of the header
+	tampered := strings.Replace(enc, `"sendRoot":"`+dec.SendRoot().Hex(), `"sendRoot":"`+common.Hash{}.Hex(), 1)
+	if err := json.Unmarshal([]byte(tampered), &dec); err == nil {
+		t.Fatal("header with another send root decoded")
+	}
+	prooftest.CheckDropped(t, enc, "baseFeePerGas", "baseFee", &dec)
+}
+
+func TestAssembleProof(t *testing.T) {
+	rs := testReceipts()
+	header := testHeader(150000000, receipttrie.DeriveRoot(receipttrie.Receipts(rs)))
+	prooftest.CheckAssemble(t, func(log types.Log) (*iproof.Proof, error) {
+		return AssembleProof(header, log, 42161, rs, "transferIn")
+	}, *rs[2].Logs[0], len(rs))
+}
+
+type fixedSource struct {
+	confirmed *Confirmed
+}
+
+func (s *fixedSource) LatestConfirmed(context.Context) (*Confirmed, error) {
+	return s.confirmed, nil
+}
+
+// nodeServer answers eth_getBlockByNumber and eth_getBlockByHash with the headers, the last one of a height is the
+// one of the chain of the node
+func nodeServer(t *testing.T, headers ...*Header) *Client {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var msg struct {
+			Id     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": msg.Id, "result": nil}
+		for _, h := range headers {
+			switch msg.Method {
+			case "eth_getBlockByNumber":
+				var n hexutil.Big
+				_ = json.Unmarshal(msg.Params[0], &n)
+				if h.Number.Cmp(n.ToInt()) == 0 {
+					resp["result"] = h
+				}
+			case "eth_getBlockByHash":
+				var hash common.Hash
+				_ = json.Unmarshal(msg.Params[0], &hash)
+				if h.Hash() == hash {
+					resp["result"] = h
+				}
+			}
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewClient(c)
+}
+
+func TestBuildConfirmed(t *testing.T) {
+	rs := testReceipts()
+	header := testHeader(150000000, receipttrie.DeriveRoot(receipttrie.Receipts(rs)))
+	confirmedHeader := testHeader(150000100, types.EmptyRootHash)
+	log := *rs[2].Logs[0]
+	log.BlockNumber, log.BlockHash = header.Number.Uint64(), header.Hash()
+
+	// the block is after the latest confirmed block
+	b := NewBuilder(nodeServer(t, header, confirmedHeader), &fixedSource{&Confirmed{BlockHash: header.ParentHash}}, 42161)
+	if _, err := b.Build(context.Background(), &log, rs); err == nil {
+		t.Fatal("proof of a block the node does not know the confirmation of")
+	}
+	earlier := testHeader(149999900, types.EmptyRootHash)
+	b = NewBuilder(nodeServer(t, header, earlier), &fixedSource{&Confirmed{BlockHash: earlier.Hash()}}, 42161)
+	if _, err := b.Build(context.Background(), &log, rs); !errors.Is(err, iproof.ErrNotReady) {
+		t.Fatalf("got %v, want ErrNotReady", err)
+	}
+
+	// the confirmed block is not the one of the node at its height
+	other := *confirmedHeader
+	other.Extra = common.Hash{}.Bytes()
+	b = NewBuilder(nodeServer(t, header, &other, confirmedHeader), &fixedSource{&Confirmed{BlockHash: other.Hash()}}, 42161)
+	if _, err := b.Build(context.Background(), &log, rs); !errors.Is(err, ErrInvalidConfirmed) {
+		t.Fatalf("got %v, want ErrInvalidConfirmed", err)
+	}
+
+	b = NewBuilder(nodeServer(t, header, confirmedHeader), &fixedSource{&Confirmed{BlockHash: confirmedHeader.Hash()}}, 42161)
+	p, err := b.Build(context.Background(), &log, rs)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	if b.confirmed != confirmedHeader.Number.Uint64() {
+		t.Fatalf("confirmed %d", b.confirmed)
+	}
+}
+
+// rollupServer answers the calls of the rollup on l1 with the handlers, and eth_getLogs with the logs
+func rollupServer(t *testing.T, a *abi.ABI, handle map[string]func(args []interface{}) []interface{}, logs []types.Log) *rpc.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		var msg struct {
+			Id     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(req.Body).Decode(&msg); err != nil {
+			rw.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": msg.Id}
+		switch msg.Method {
+		case "eth_call":
+			var call struct {
+				Data hexutil.Bytes `json:"data"`
+			}
+			_ = json.Unmarshal(msg.Params[0], &call)
+			method, err := a.MethodById(call.Data[:4])
+			if err != nil {
+				t.Fatal(err)
+			}
+			args, err := method.Inputs.Unpack(call.Data[4:])
+			if err != nil {
+				t.Fatal(err)
+			}
+			out, err := method.Outputs.Pack(handle[method.Name](args)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp["result"] = hexutil.Bytes(out)
+		case "eth_getLogs":
+			resp["result"] = logs
+		default:
+			resp["error"] = map[string]interface{}{"code": -32601, "message": "no method " + msg.Method}
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	c, err := rpc.DialHTTP(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+func confirmedLog(t *testing.T, a *abi.ABI, event string, id common.Hash, header *Header) types.Log {
+	data, err := a.Events[event].Inputs.NonIndexed().Pack(header.Hash(), header.SendRoot())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return types.Log{Address: rollupAddr, Topics: []common.Hash{a.Events[event].ID, id}, Data: data,
+		BlockHash: common.HexToHash("0x01"), TxHash: common.HexToHash("0x02")}
+}
+
+func TestNodeSource(t *testing.T) {
+	header := testHeader(150000000, types.EmptyRootHash)
+	confirmData := ConfirmData(header.Hash(), header.SendRoot())
+	c := rollupServer(t, &rollupAbi, map[string]func([]interface{}) []interface{}{
+		"latestConfirmed": func([]interface{}) []interface{} { return []interface{}{uint64(5)} },
+		"getNode": func(args []interface{}) []interface{} {
+			if args[0].(uint64) != 5 {
+				t.Fatalf("node %d", args[0])
+			}
+			return []interface{}{node{ConfirmData: confirmData, CreatedAtBlock: 19000000}}
+		},
+	}, []types.Log{confirmedLog(t, &rollupAbi, "NodeConfirmed", common.BigToHash(big.NewInt(5)), header)})
+
+	confirmed, err := NewNodeSource(c, rollupAddr).LatestConfirmed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.NodeNum != 5 || confirmed.BlockHash != header.Hash() || confirmed.SendRoot != header.SendRoot() {
+		t.Fatalf("confirmed %+v", confirmed)
+	}
+	if _, err = NewConfirmedData(confirmed, header); err != nil {
+		t.Fatal(err)
+	}
+	other := *header
+	other.Extra = common.Hash{}.Bytes()
+	if _, err = NewConfirmedData(confirmed, &other); !errors.Is(err, ErrInvalidConfirmed) {
+		t.Fatalf("got %v, want ErrInvalidConfirmed", err)
+	}
+
+	// the event is not what the node confirms
+	c = rollupServer(t, &rollupAbi, map[string]func([]interface{}) []interface{}{
+		"latestConfirmed": func([]interface{}) []interface{} { return []interface{}{uint64(5)} },
+		"getNode": func([]interface{}) []interface{} {
+			return []interface{}{node{ConfirmData: common.HexToHash("0x01"), CreatedAtBlock: 19000000}}
+		},
+	}, []types.Log{confirmedLog(t, &rollupAbi, "NodeConfirmed", common.BigToHash(big.NewInt(5)), header)})
+	if _, err = NewNodeSource(c, rollupAddr).LatestConfirmed(context.Background()); !errors.Is(err, ErrInvalidConfirmed) {
+		t.Fatalf("got %v, want ErrInvalidConfirmed", err)
+	}
+}
+
+func TestBoldSource(t *testing.T) {
+	header := testHeader(150000000, types.EmptyRootHash)
+	hash := crypto.Keccak256Hash([]byte("assertion"))
+	c := rollupServer(t, &boldAbi, map[string]func([]interface{}) []interface{}{
+		"latestConfirmed": func([]interface{}) []interface{} { return []interface{}{[32]byte(hash)} },
+		"getAssertion": func([]interface{}) []interface{} {
+			return []interface{}{assertionNode{CreatedAtBlock: 21000000, Status: assertionConfirmed}}
+		},
+	}, []types.Log{confirmedLog(t, &boldAbi, "AssertionConfirmed", hash, header)})
+
+	confirmed, err := NewBoldSource(c, rollupAddr).LatestConfirmed(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if confirmed.AssertionHash != hash || confirmed.BlockHash != header.Hash() || confirmed.SendRoot != header.SendRoot() {
+		t.Fatalf("confirmed %+v", confirmed)
+	}
+}
+
+func TestParseOpts(t *testing.T) {
+	for _, c := range []struct {
+		opts map[string]string
+		ok   bool
+	}{
+		{map[string]string{L1EndpointOpt: "http://l1", RollupOpt: rollupAddr.Hex()}, true},
+		{map[string]string{L1EndpointOpt: "http://l1", RollupOpt: rollupAddr.Hex(), BoldOpt: "true"}, true},
+		{map[string]string{RollupOpt: rollupAddr.Hex()}, false},
+		{map[string]string{L1EndpointOpt: "http://l1"}, false},
+		{map[string]string{L1EndpointOpt: "http://l1", RollupOpt: rollupAddr.Hex(), BoldOpt: "yes"}, false},
+	} {
+		if _, err := ParseOpts(c.opts); (err == nil) != c.ok {
+			t.Errorf("%v: %v", c.opts, err)
+		}
+	}
+}
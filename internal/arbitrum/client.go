@@ -0,0 +1,61 @@
+package arbitrum
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client reads the nitro headers and the receipts of a block, the ethclient can not decode the nitro headers, nor
+// the blocks with the arbitrum tx types to list the receipts
+type Client struct {
+	c *rpc.Client
+}
+
+func Dial(ctx context.Context, endpoint string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c: c}
+}
+
+func (c *Client) Close() {
+	c.c.Close()
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*Header, error) {
+	return c.header(ctx, "eth_getBlockByNumber", hexutil.EncodeBig(number), false)
+}
+
+func (c *Client) HeaderByHash(ctx context.Context, hash common.Hash) (*Header, error) {
+	return c.header(ctx, "eth_getBlockByHash", hash, false)
+}
+
+func (c *Client) header(ctx context.Context, method string, args ...interface{}) (*Header, error) {
+	var h *Header
+	err := c.c.CallContext(ctx, &h, method, args...)
+	if err == nil && h == nil {
+		err = ethereum.NotFound
+	}
+	return h, err
+}
+
+// BlockReceipts returns the receipts of all txs of the block, in the order of the receipt trie
+func (c *Client) BlockReceipts(ctx context.Context, number *big.Int) ([]*types.Receipt, error) {
+	var rs []*types.Receipt
+	err := c.c.CallContext(ctx, &rs, "eth_getBlockReceipts", hexutil.EncodeBig(number))
+	if err == nil && rs == nil {
+		err = ethereum.NotFound
+	}
+	return rs, err
+}
@@ -0,0 +1,182 @@
+package arbitrum
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Header is the header of a nitro block. Nitro keeps its own data in the fields of the ethereum header: the extra
+// data is the send root of the outbox, and the mix hash packs the send count, the l1 block number and the arbos
+// version. The fields after london are optional, the block hash is only right with all of them.
+type Header struct {
+	ParentHash       common.Hash
+	UncleHash        common.Hash
+	Coinbase         common.Address
+	Root             common.Hash
+	TxHash           common.Hash
+	ReceiptHash      common.Hash
+	Bloom            types.Bloom
+	Difficulty       *big.Int
+	Number           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Time             uint64
+	Extra            []byte
+	MixDigest        common.Hash
+	Nonce            types.BlockNonce // the count of the delayed messages read
+	BaseFee          *big.Int         `rlp:"optional"`
+	WithdrawalsHash  *common.Hash     `rlp:"optional"`
+	BlobGasUsed      *uint64          `rlp:"optional"`
+	ExcessBlobGas    *uint64          `rlp:"optional"`
+	ParentBeaconRoot *common.Hash     `rlp:"optional"`
+	RequestsHash     *common.Hash     `rlp:"optional"`
+}
+
+// Hash is the keccak256 of the rlp of the header
+func (h *Header) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes(h)
+	return crypto.Keccak256Hash(enc)
+}
+
+// SendRoot is the root of the outbox merkle tree after the block
+func (h *Header) SendRoot() common.Hash {
+	return common.BytesToHash(h.Extra)
+}
+
+// SendCount is the number of the l2 to l1 messages after the block
+func (h *Header) SendCount() uint64 {
+	return binary.BigEndian.Uint64(h.MixDigest[:8])
+}
+
+// L1BlockNumber is the l1 block number the block is derived at
+func (h *Header) L1BlockNumber() uint64 {
+	return binary.BigEndian.Uint64(h.MixDigest[8:16])
+}
+
+func (h *Header) ArbOSVersion() uint64 {
+	return binary.BigEndian.Uint64(h.MixDigest[16:24])
+}
+
+type rpcHeader struct {
+	Hash             common.Hash      `json:"hash"`
+	ParentHash       common.Hash      `json:"parentHash"`
+	UncleHash        common.Hash      `json:"sha3Uncles"`
+	Coinbase         common.Address   `json:"miner"`
+	Root             common.Hash      `json:"stateRoot"`
+	TxHash           common.Hash      `json:"transactionsRoot"`
+	ReceiptHash      common.Hash      `json:"receiptsRoot"`
+	Bloom            types.Bloom      `json:"logsBloom"`
+	Difficulty       *hexutil.Big     `json:"difficulty"`
+	Number           *hexutil.Big     `json:"number"`
+	GasLimit         hexutil.Uint64   `json:"gasLimit"`
+	GasUsed          hexutil.Uint64   `json:"gasUsed"`
+	Time             hexutil.Uint64   `json:"timestamp"`
+	Extra            hexutil.Bytes    `json:"extraData"`
+	MixDigest        common.Hash      `json:"mixHash"`
+	Nonce            types.BlockNonce `json:"nonce"`
+	BaseFee          *hexutil.Big     `json:"baseFeePerGas,omitempty"`
+	WithdrawalsHash  *common.Hash     `json:"withdrawalsRoot,omitempty"`
+	BlobGasUsed      *hexutil.Uint64  `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas    *hexutil.Uint64  `json:"excessBlobGas,omitempty"`
+	ParentBeaconRoot *common.Hash     `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash     *common.Hash     `json:"requestsHash,omitempty"`
+	// the nitro fields the node decodes from extraData and mixHash
+	SendRoot      *common.Hash    `json:"sendRoot,omitempty"`
+	SendCount     *hexutil.Uint64 `json:"sendCount,omitempty"`
+	L1BlockNumber *hexutil.Uint64 `json:"l1BlockNumber,omitempty"`
+}
+
+// UnmarshalJSON decodes the header of eth_getBlockByNumber, it checks the header hashes to the hash the node
+// returns and the nitro fields of the node are the ones in the header
+func (h *Header) UnmarshalJSON(input []byte) error {
+	var dec rpcHeader
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Number == nil || dec.Difficulty == nil {
+		return errors.New("missing number or difficulty of header")
+	}
+	if len(dec.Extra) != common.HashLength {
+		return fmt.Errorf("extra data of nitro header is %d bytes, not the send root", len(dec.Extra))
+	}
+	*h = Header{
+		ParentHash:       dec.ParentHash,
+		UncleHash:        dec.UncleHash,
+		Coinbase:         dec.Coinbase,
+		Root:             dec.Root,
+		TxHash:           dec.TxHash,
+		ReceiptHash:      dec.ReceiptHash,
+		Bloom:            dec.Bloom,
+		Difficulty:       dec.Difficulty.ToInt(),
+		Number:           dec.Number.ToInt(),
+		GasLimit:         uint64(dec.GasLimit),
+		GasUsed:          uint64(dec.GasUsed),
+		Time:             uint64(dec.Time),
+		Extra:            dec.Extra,
+		MixDigest:        dec.MixDigest,
+		Nonce:            dec.Nonce,
+		WithdrawalsHash:  dec.WithdrawalsHash,
+		BlobGasUsed:      (*uint64)(dec.BlobGasUsed),
+		ExcessBlobGas:    (*uint64)(dec.ExcessBlobGas),
+		ParentBeaconRoot: dec.ParentBeaconRoot,
+		RequestsHash:     dec.RequestsHash,
+	}
+	if dec.BaseFee != nil {
+		h.BaseFee = dec.BaseFee.ToInt()
+	}
+	if hash := h.Hash(); hash != dec.Hash {
+		return fmt.Errorf("header of block %s hashes to %s, not %s", h.Number, hash.Hex(), dec.Hash.Hex())
+	}
+	if dec.SendRoot != nil && *dec.SendRoot != h.SendRoot() {
+		return fmt.Errorf("send root of block %s is %s, header has %s", h.Number, dec.SendRoot.Hex(), h.SendRoot().Hex())
+	}
+	if dec.SendCount != nil && uint64(*dec.SendCount) != h.SendCount() {
+		return fmt.Errorf("send count of block %s is %d, header has %d", h.Number, *dec.SendCount, h.SendCount())
+	}
+	if dec.L1BlockNumber != nil && uint64(*dec.L1BlockNumber) != h.L1BlockNumber() {
+		return fmt.Errorf("l1 block of block %s is %d, header has %d", h.Number, *dec.L1BlockNumber, h.L1BlockNumber())
+	}
+	return nil
+}
+
+// MarshalJSON encodes the header like the node does
+func (h *Header) MarshalJSON() ([]byte, error) {
+	sendRoot, sendCount, l1Block := h.SendRoot(), hexutil.Uint64(h.SendCount()), hexutil.Uint64(h.L1BlockNumber())
+	enc := rpcHeader{
+		Hash:             h.Hash(),
+		ParentHash:       h.ParentHash,
+		UncleHash:        h.UncleHash,
+		Coinbase:         h.Coinbase,
+		Root:             h.Root,
+		TxHash:           h.TxHash,
+		ReceiptHash:      h.ReceiptHash,
+		Bloom:            h.Bloom,
+		Difficulty:       (*hexutil.Big)(h.Difficulty),
+		Number:           (*hexutil.Big)(h.Number),
+		GasLimit:         hexutil.Uint64(h.GasLimit),
+		GasUsed:          hexutil.Uint64(h.GasUsed),
+		Time:             hexutil.Uint64(h.Time),
+		Extra:            h.Extra,
+		MixDigest:        h.MixDigest,
+		Nonce:            h.Nonce,
+		BaseFee:          (*hexutil.Big)(h.BaseFee),
+		WithdrawalsHash:  h.WithdrawalsHash,
+		BlobGasUsed:      (*hexutil.Uint64)(h.BlobGasUsed),
+		ExcessBlobGas:    (*hexutil.Uint64)(h.ExcessBlobGas),
+		ParentBeaconRoot: h.ParentBeaconRoot,
+		RequestsHash:     h.RequestsHash,
+		SendRoot:         &sendRoot,
+		SendCount:        &sendCount,
+		L1BlockNumber:    &l1Block,
+	}
+	return json.Marshal(&enc)
+}
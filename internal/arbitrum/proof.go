@@ -0,0 +1,203 @@
+package arbitrum
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+	utils "github.com/mapprotocol/compass/shared/ethereum"
+)
+
+// ProofData proves a receipt against the nitro header of its block, the light client on map checks the header
+// with the blocks confirmed on l1
+type ProofData struct {
+	Header       []byte // rlp of the nitro header
+	ReceiptProof ReceiptProof
+}
+
+type ReceiptProof struct {
+	TxReceipt mapprotocol.TxReceipt
+	KeyIndex  []byte
+	Proof     [][]byte
+}
+
+// ConfirmedData is the confirmed block synced to the light client on map
+type ConfirmedData struct {
+	NodeNum       uint64
+	AssertionHash [32]byte
+	BlockHash     [32]byte
+	SendRoot      [32]byte
+	Header        []byte // rlp of the nitro header of the block
+}
+
+func init() {
+	iproof.Register(chains.Arbitrum, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		opts, err := ParseOpts(cfg.Opts)
+		if err != nil {
+			return nil, err
+		}
+		ctx := context.Background()
+		source, err := opts.NewRollupSource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, err := Dial(ctx, cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return NewBuilder(c, source, cfg.Id), nil
+	})
+}
+
+// Builder assembles the proof with the nitro header of the block of the log. The header has no link to the blocks
+// confirmed on l1 the light client on map keeps, so a log is only proven once a confirmed block of the chain of the
+// node is at or after its block.
+type Builder struct {
+	client *Client
+	source RollupSource
+	fId    msg.ChainId
+
+	lock      sync.Mutex
+	confirmed uint64 // the number of the latest confirmed block, checked to be in the chain of the node
+}
+
+func NewBuilder(c *Client, source RollupSource, fId msg.ChainId) *Builder {
+	return &Builder{client: c, source: source, fId: fId}
+}
+
+// Receipts reads the receipts with eth_getBlockReceipts, the blocks have the arbitrum tx types the ethclient can
+// not decode to list the tx hashes
+func (b *Builder) Receipts(ctx context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return b.client.BlockReceipts(ctx, number)
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != log.BlockHash {
+		return nil, fmt.Errorf("block %d is %s, not %s of the log, it is reorged", log.BlockNumber, header.Hash(), log.BlockHash)
+	}
+	if err = b.covered(ctx, log.BlockNumber); err != nil {
+		return nil, err
+	}
+	return AssembleProof(header, *log, b.fId, receipts, iproof.Method(log.Topics[0]))
+}
+
+// covered checks the block is at or before the latest confirmed block, and the confirmed block is the one of the
+// node at its height. The block of the log is then an ancestor of the confirmed block, as both are in the chain of
+// the node.
+func (b *Builder) covered(ctx context.Context, number uint64) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if number <= b.confirmed {
+		return nil
+	}
+	confirmed, err := b.source.LatestConfirmed(ctx)
+	if err != nil {
+		return err
+	}
+	header, err := b.client.HeaderByHash(ctx, confirmed.BlockHash)
+	if err != nil {
+		return fmt.Errorf("confirmed block %s: %w", confirmed.BlockHash, err)
+	}
+	canonical, err := b.client.HeaderByNumber(ctx, header.Number)
+	if err != nil {
+		return err
+	}
+	if canonical.Hash() != confirmed.BlockHash {
+		return fmt.Errorf("%w: block %s of the node is %s, l1 confirms %s", ErrInvalidConfirmed, header.Number,
+			canonical.Hash(), confirmed.BlockHash)
+	}
+	b.confirmed = header.Number.Uint64()
+	if number > b.confirmed {
+		return fmt.Errorf("%w: block %d is after the latest confirmed block %d", iproof.ErrNotReady, number, b.confirmed)
+	}
+	return nil
+}
+
+func AssembleProof(header *Header, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) (*iproof.Proof, error) {
+	txIndex := log.TxIndex
+	if int(txIndex) >= len(receipts) {
+		return nil, fmt.Errorf("%w: tx index %d out of %d receipts", iproof.ErrInvalidProof, txIndex, len(receipts))
+	}
+	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	key = rlp.AppendUint64(key[:0], uint64(txIndex))
+	ek := utils.Key2Hex(key, len(proof))
+
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	pd := ProofData{
+		Header: enc,
+		ReceiptProof: ReceiptProof{
+			TxReceipt: *receipt,
+			KeyIndex:  ek,
+			Proof:     proof,
+		},
+	}
+
+	input, err := mapprotocol.Arbitrum.Methods[mapprotocol.MethodOfGetBytes].Inputs.Pack(pd)
+	if err != nil {
+		return nil, err
+	}
+	pack, err := mapprotocol.PackInput(mapprotocol.Mcs, method, new(big.Int).SetUint64(uint64(fId)), input)
+	if err != nil {
+		return nil, err
+	}
+
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: header.ReceiptHash,
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
+}
+
+// NewConfirmedData checks the confirmed block is the block of the node, and packs it for the light client
+func NewConfirmedData(confirmed *Confirmed, header *Header) ([]byte, error) {
+	if hash := header.Hash(); hash != confirmed.BlockHash {
+		return nil, fmt.Errorf("%w: block %s is %s, l1 confirms %s", ErrInvalidConfirmed, header.Number, hash, confirmed.BlockHash)
+	}
+	if header.SendRoot() != confirmed.SendRoot {
+		return nil, fmt.Errorf("%w: send root of block %s is %s, l1 confirms %s", ErrInvalidConfirmed, header.Number,
+			header.SendRoot(), confirmed.SendRoot)
+	}
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	return mapprotocol.Arbitrum.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Pack(ConfirmedData{
+		NodeNum:       confirmed.NodeNum,
+		AssertionHash: confirmed.AssertionHash,
+		BlockHash:     confirmed.BlockHash,
+		SendRoot:      confirmed.SendRoot,
+		Header:        enc,
+	})
+}
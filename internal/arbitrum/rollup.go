@@ -0,0 +1,239 @@
+package arbitrum
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Chain specific options of the arbitrum chains
+const (
+	L1EndpointOpt = "l1Endpoint"
+	RollupOpt     = "rollup"
+	BoldOpt       = "bold"
+)
+
+// ErrInvalidConfirmed is returned when a block confirmed on l1 does not match the block of the node
+var ErrInvalidConfirmed = errors.New("invalid confirmed block")
+
+const rollupAbiJson = `[
+	{"inputs":[],"name":"latestConfirmed","outputs":[{"name":"","type":"uint64"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"nodeNum","type":"uint64"}],"name":"getNode","outputs":[{"components":[{"name":"stateHash","type":"bytes32"},{"name":"challengeHash","type":"bytes32"},{"name":"confirmData","type":"bytes32"},{"name":"prevNum","type":"uint64"},{"name":"deadlineBlock","type":"uint64"},{"name":"noChildConfirmedBeforeBlock","type":"uint64"},{"name":"stakerCount","type":"uint64"},{"name":"childStakerCount","type":"uint64"},{"name":"firstChildBlock","type":"uint64"},{"name":"latestChildNumber","type":"uint64"},{"name":"createdAtBlock","type":"uint64"},{"name":"nodeHash","type":"bytes32"}],"name":"","type":"tuple"}],"stateMutability":"view","type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"nodeNum","type":"uint64"},{"indexed":false,"name":"blockHash","type":"bytes32"},{"indexed":false,"name":"sendRoot","type":"bytes32"}],"name":"NodeConfirmed","type":"event"}
+]`
+
+// boldAbiJson is the rollup since BoLD, the assertions are identified by their hashes
+const boldAbiJson = `[
+	{"inputs":[],"name":"latestConfirmed","outputs":[{"name":"","type":"bytes32"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"assertionHash","type":"bytes32"}],"name":"getAssertion","outputs":[{"components":[{"name":"firstChildBlock","type":"uint64"},{"name":"secondChildBlock","type":"uint64"},{"name":"createdAtBlock","type":"uint64"},{"name":"isFirstChild","type":"bool"},{"name":"status","type":"uint8"},{"name":"configHash","type":"bytes32"}],"name":"","type":"tuple"}],"stateMutability":"view","type":"function"},
+	{"anonymous":false,"inputs":[{"indexed":true,"name":"assertionHash","type":"bytes32"},{"indexed":false,"name":"blockHash","type":"bytes32"},{"indexed":false,"name":"sendRoot","type":"bytes32"}],"name":"AssertionConfirmed","type":"event"}
+]`
+
+var (
+	rollupAbi, _ = abi.JSON(strings.NewReader(rollupAbiJson))
+	boldAbi, _   = abi.JSON(strings.NewReader(boldAbiJson))
+)
+
+// Confirmed is the l2 block of an RBlock (a node of the rollup before BoLD) or an assertion confirmed on l1
+type Confirmed struct {
+	NodeNum       uint64      // the node of the rollup, 0 since BoLD
+	AssertionHash common.Hash // the assertion since BoLD, empty before
+	BlockHash     common.Hash
+	SendRoot      common.Hash
+}
+
+// RollupSource reads the latest confirmed block of the rollup on l1
+type RollupSource interface {
+	LatestConfirmed(ctx context.Context) (*Confirmed, error)
+}
+
+// Options are the options of an arbitrum chain in the config
+type Options struct {
+	L1Endpoint string
+	Rollup     common.Address
+	Bold       bool
+}
+
+func ParseOpts(opts map[string]string) (*Options, error) {
+	o := &Options{L1Endpoint: opts[L1EndpointOpt]}
+	if o.L1Endpoint == "" {
+		return nil, fmt.Errorf("must provide opts.%s field for arbitrum config", L1EndpointOpt)
+	}
+	rollup, ok := opts[RollupOpt]
+	if !ok || !common.IsHexAddress(rollup) {
+		return nil, fmt.Errorf("must provide opts.%s field for arbitrum config", RollupOpt)
+	}
+	o.Rollup = common.HexToAddress(rollup)
+	if v, ok := opts[BoldOpt]; ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", BoldOpt, err)
+		}
+		o.Bold = b
+	}
+	return o, nil
+}
+
+// NewRollupSource connects to l1 for the confirmed blocks
+func (o *Options) NewRollupSource(ctx context.Context) (RollupSource, error) {
+	c, err := rpc.DialContext(ctx, o.L1Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if o.Bold {
+		return NewBoldSource(c, o.Rollup), nil
+	}
+	return NewNodeSource(c, o.Rollup), nil
+}
+
+// l1 reads the rollup at the finalized block of l1
+type l1 struct {
+	c      *rpc.Client
+	rollup common.Address
+}
+
+func (r *l1) call(ctx context.Context, a *abi.ABI, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := a.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	var output hexutil.Bytes
+	arg := map[string]interface{}{"to": r.rollup, "data": hexutil.Bytes(input)}
+	if err = r.c.CallContext(ctx, &output, "eth_call", arg, "finalized"); err != nil {
+		return nil, fmt.Errorf("%s of %s: %w", method, r.rollup, err)
+	}
+	return a.Unpack(method, output)
+}
+
+// confirmedEvent reads the block hash and the send root of the confirmation event of the node or the assertion,
+// the event is after the block the node or the assertion is created at
+func (r *l1) confirmedEvent(ctx context.Context, a *abi.ABI, event string, id common.Hash, from uint64) (common.Hash, common.Hash, error) {
+	var logs []types.Log
+	arg := map[string]interface{}{
+		"address":   r.rollup,
+		"topics":    [][]common.Hash{{a.Events[event].ID}, {id}},
+		"fromBlock": hexutil.EncodeUint64(from),
+		"toBlock":   "finalized",
+	}
+	if err := r.c.CallContext(ctx, &logs, "eth_getLogs", arg); err != nil {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("%s of %s: %w", event, id, err)
+	}
+	if len(logs) == 0 {
+		return common.Hash{}, common.Hash{}, fmt.Errorf("%w: no %s event of %s", ErrInvalidConfirmed, event, id)
+	}
+	out, err := a.Unpack(event, logs[len(logs)-1].Data)
+	if err != nil {
+		return common.Hash{}, common.Hash{}, err
+	}
+	return out[0].([32]byte), out[1].([32]byte), nil
+}
+
+// NodeSource reads the RBlocks of the rollup before BoLD
+type NodeSource struct {
+	l1
+}
+
+func NewNodeSource(c *rpc.Client, rollup common.Address) *NodeSource {
+	return &NodeSource{l1{c: c, rollup: rollup}}
+}
+
+// node is Node of the rollup
+type node struct {
+	StateHash                   [32]byte
+	ChallengeHash               [32]byte
+	ConfirmData                 [32]byte
+	PrevNum                     uint64
+	DeadlineBlock               uint64
+	NoChildConfirmedBeforeBlock uint64
+	StakerCount                 uint64
+	ChildStakerCount            uint64
+	FirstChildBlock             uint64
+	LatestChildNumber           uint64
+	CreatedAtBlock              uint64
+	NodeHash                    [32]byte
+}
+
+func (s *NodeSource) LatestConfirmed(ctx context.Context) (*Confirmed, error) {
+	out, err := s.call(ctx, &rollupAbi, "latestConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	num := out[0].(uint64)
+	if num == 0 {
+		// the genesis node, nothing is confirmed after the rollup is created
+		return nil, ethereum.NotFound
+	}
+	out, err = s.call(ctx, &rollupAbi, "getNode", num)
+	if err != nil {
+		return nil, err
+	}
+	n := abi.ConvertType(out[0], new(node)).(*node)
+	blockHash, sendRoot, err := s.confirmedEvent(ctx, &rollupAbi, "NodeConfirmed",
+		common.BigToHash(new(big.Int).SetUint64(num)), n.CreatedAtBlock)
+	if err != nil {
+		return nil, err
+	}
+	if data := ConfirmData(blockHash, sendRoot); data != n.ConfirmData {
+		return nil, fmt.Errorf("%w: node %d confirms %s, the event has %s", ErrInvalidConfirmed, num,
+			common.Hash(n.ConfirmData), data)
+	}
+	return &Confirmed{NodeNum: num, BlockHash: blockHash, SendRoot: sendRoot}, nil
+}
+
+// ConfirmData is what a node of the rollup confirms, keccak256(blockHash ++ sendRoot)
+func ConfirmData(blockHash, sendRoot common.Hash) common.Hash {
+	return crypto.Keccak256Hash(blockHash[:], sendRoot[:])
+}
+
+// BoldSource reads the assertions of the rollup since BoLD
+type BoldSource struct {
+	l1
+}
+
+func NewBoldSource(c *rpc.Client, rollup common.Address) *BoldSource {
+	return &BoldSource{l1{c: c, rollup: rollup}}
+}
+
+// assertionNode is AssertionNode of the rollup
+type assertionNode struct {
+	FirstChildBlock  uint64
+	SecondChildBlock uint64
+	CreatedAtBlock   uint64
+	IsFirstChild     bool
+	Status           uint8
+	ConfigHash       [32]byte
+}
+
+const assertionConfirmed = 2
+
+func (s *BoldSource) LatestConfirmed(ctx context.Context) (*Confirmed, error) {
+	out, err := s.call(ctx, &boldAbi, "latestConfirmed")
+	if err != nil {
+		return nil, err
+	}
+	hash := common.Hash(out[0].([32]byte))
+	out, err = s.call(ctx, &boldAbi, "getAssertion", hash)
+	if err != nil {
+		return nil, err
+	}
+	a := abi.ConvertType(out[0], new(assertionNode)).(*assertionNode)
+	if a.Status != assertionConfirmed {
+		return nil, fmt.Errorf("%w: latest confirmed assertion %s has status %d", ErrInvalidConfirmed, hash, a.Status)
+	}
+	blockHash, sendRoot, err := s.confirmedEvent(ctx, &boldAbi, "AssertionConfirmed", hash, a.CreatedAtBlock)
+	if err != nil {
+		return nil, err
+	}
+	return &Confirmed{AssertionHash: hash, BlockHash: blockHash, SendRoot: sendRoot}, nil
+}
@@ -0,0 +1,104 @@
+package avalanche
+
+import (
+	"bytes"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/internal/proof/prooftest"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+)
+
+func testHeader(number int64, parent common.Hash, receiptsRoot common.Hash) *Header {
+	return &Header{
+		ParentHash:     parent,
+		UncleHash:      types.EmptyUncleHash,
+		Coinbase:       common.HexToAddress("0x0100000000000000000000000000000000000000"),
+		Root:           crypto.Keccak256Hash([]byte("state")),
+		TxHash:         crypto.Keccak256Hash(big.NewInt(number).Bytes()),
+		ReceiptHash:    receiptsRoot,
+		Difficulty:     big.NewInt(1),
+		Number:         big.NewInt(number),
+		GasLimit:       15000000,
+		GasUsed:        152000,
+		Time:           uint64(1700000000 + number*2),
+		Extra:          bytes.Repeat([]byte{0x01}, 80),
+		ExtDataHash:    types.EmptyRootHash,
+		BaseFee:        big.NewInt(25000000000),
+		ExtDataGasUsed: big.NewInt(0),
+		BlockGasCost:   big.NewInt(0),
+	}
+}
+
+func testReceipts() []*types.Receipt {
+	return prooftest.Receipts(
+		&types.Receipt{Type: types.LegacyTxType, Status: 1, CumulativeGasUsed: 21000},
+		&types.Receipt{Type: types.DynamicFeeTxType, Status: 1, CumulativeGasUsed: 120000, Logs: []*types.Log{prooftest.TransferOut()}},
+		&types.Receipt{Type: types.AccessListTxType, Status: 0, CumulativeGasUsed: 152000},
+	)
+}
+
+func TestHeaderJSON(t *testing.T) {
+	h := testHeader(40000000, crypto.Keccak256Hash([]byte("parent")), types.EmptyRootHash)
+	var dec Header
+	enc := prooftest.CheckJSON(t, h, &dec)
+	if dec.BlockGasCost.Sign() != 0 || dec.ExtDataHash != types.EmptyRootHash {
+		t.Fatalf("coreth fields %s %s", dec.BlockGasCost, dec.ExtDataHash)
+	}
+
+	// the hash of the geth header is not the block hash
+	var eth types.Header
+	if err := json.Unmarshal([]byte(enc), &eth); err != nil {
+		t.Fatal(err)
+	}
+	if eth.Hash() == h.Hash() {
+		t.Fatal("geth header hashes to the hash of the C-Chain header")
+	}
+	prooftest.CheckDropped(t, enc, "blockGasCost", "blockGasCosts", &dec)
+	prooftest.CheckDropped(t, enc, "extDataHash", "extraDataHash", &dec)
+}
+
+func TestAssembleProof(t *testing.T) {
+	rs := testReceipts()
+	header := testHeader(40000000, common.Hash{}, receipttrie.DeriveRoot(receipttrie.Receipts(rs)))
+	prooftest.CheckAssemble(t, func(log types.Log) (*iproof.Proof, error) {
+		return AssembleProof(header, log, 43114, rs, mapprotocol.MethodOfTransferIn)
+	}, *rs[1].Logs[0], len(rs))
+}
+
+func TestPackHeaders(t *testing.T) {
+	headers := make([]*Header, 3)
+	parent := crypto.Keccak256Hash([]byte("parent"))
+	for i := range headers {
+		headers[i] = testHeader(int64(40000000+i), parent, types.EmptyRootHash)
+		parent = headers[i].Hash()
+	}
+	input, err := PackHeaders(headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := mapprotocol.Avalanche.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Unpack(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	encs := out[0].([][]byte)
+	var last Header
+	if err = rlp.DecodeBytes(encs[len(encs)-1], &last); err != nil {
+		t.Fatal(err)
+	}
+	if len(encs) != 3 || last.Hash() != headers[2].Hash() {
+		t.Fatalf("%d headers, last %s", len(encs), last.Hash())
+	}
+
+	headers[1] = testHeader(40000001, common.Hash{}, types.EmptyRootHash)
+	if _, err = PackHeaders(headers); err == nil {
+		t.Fatal("headers not linked packed")
+	}
+}
@@ -0,0 +1,40 @@
+package avalanche
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client reads the C-Chain headers with the coreth fields, the ethclient drops them
+type Client struct {
+	c *rpc.Client
+}
+
+func Dial(ctx context.Context, endpoint string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c: c}
+}
+
+func (c *Client) Close() {
+	c.c.Close()
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*Header, error) {
+	var h *Header
+	err := c.c.CallContext(ctx, &h, "eth_getBlockByNumber", hexutil.EncodeBig(number), false)
+	if err == nil && h == nil {
+		err = ethereum.NotFound
+	}
+	return h, err
+}
@@ -0,0 +1,135 @@
+package avalanche
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+	utils "github.com/mapprotocol/compass/shared/ethereum"
+)
+
+// ProofData proves a receipt against the C-Chain header of its block, the light client on map checks the header
+// with the headers synced by the maintainer
+type ProofData struct {
+	Header       []byte // rlp of the coreth header
+	ReceiptProof ReceiptProof
+}
+
+type ReceiptProof struct {
+	TxReceipt mapprotocol.TxReceipt
+	KeyIndex  []byte
+	Proof     [][]byte
+}
+
+func init() {
+	iproof.Register(chains.Avalanche, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		c, err := Dial(context.Background(), cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return &Builder{client: cfg.Client, headers: c, fId: cfg.Id}, nil
+	})
+}
+
+// Builder assembles the proof with the coreth header of the block of the log
+type Builder struct {
+	client  *ethclient.Client
+	headers *Client
+	fId     msg.ChainId
+}
+
+// Receipts reads the receipts like the other evm chains, the C-Chain txs are the ethereum tx types
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return iproof.Receipts(b.client, number)
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	header, err := b.headers.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != log.BlockHash {
+		return nil, fmt.Errorf("block %d is %s, not %s of the log", log.BlockNumber, header.Hash(), log.BlockHash)
+	}
+	return AssembleProof(header, *log, b.fId, receipts, iproof.Method(log.Topics[0]))
+}
+
+func AssembleProof(header *Header, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) (*iproof.Proof, error) {
+	txIndex := log.TxIndex
+	if int(txIndex) >= len(receipts) {
+		return nil, fmt.Errorf("%w: tx index %d out of %d receipts", iproof.ErrInvalidProof, txIndex, len(receipts))
+	}
+	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
+	if err != nil {
+		return nil, err
+	}
+
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	var key []byte
+	key = rlp.AppendUint64(key[:0], uint64(txIndex))
+	ek := utils.Key2Hex(key, len(proof))
+
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	pd := ProofData{
+		Header: enc,
+		ReceiptProof: ReceiptProof{
+			TxReceipt: *receipt,
+			KeyIndex:  ek,
+			Proof:     proof,
+		},
+	}
+
+	input, err := mapprotocol.Avalanche.Methods[mapprotocol.MethodOfGetBytes].Inputs.Pack(pd)
+	if err != nil {
+		return nil, err
+	}
+	pack, err := mapprotocol.PackInput(mapprotocol.Mcs, method, new(big.Int).SetUint64(uint64(fId)), input)
+	if err != nil {
+		return nil, err
+	}
+
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: header.ReceiptHash,
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
+}
+
+// PackHeaders packs the consecutive headers synced to the light client on map, each header has to be the parent
+// of the next one
+func PackHeaders(headers []*Header) ([]byte, error) {
+	encs := make([][]byte, 0, len(headers))
+	for i, h := range headers {
+		if i > 0 && h.ParentHash != headers[i-1].Hash() {
+			return nil, fmt.Errorf("block %s is not the child of block %s", h.Number, headers[i-1].Number)
+		}
+		enc, err := rlp.EncodeToBytes(h)
+		if err != nil {
+			return nil, err
+		}
+		encs = append(encs, enc)
+	}
+	return mapprotocol.Avalanche.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Pack(encs)
+}
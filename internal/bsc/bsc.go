@@ -2,17 +2,17 @@ package bsc
 
 import (
 	"context"
-	"fmt"
 	"math/big"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
 	iproof "github.com/mapprotocol/compass/internal/proof"
-	"github.com/mapprotocol/compass/internal/tx"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
 )
 
@@ -81,20 +81,50 @@ type ReceiptProof struct {
 	Proof     [][]byte
 }
 
-func GetProof(client *ethclient.Client, latestBlock *big.Int, log *types.Log, method string, fId msg.ChainId) ([]byte, error) {
-	txsHash, err := tx.GetTxsHashByBlockNumber(client, latestBlock)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-	}
-	receipts, err := tx.GetReceiptsByTxsHash(client, txsHash)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
+func init() {
+	iproof.Register(chains.Bsc, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		opts, err := ParseOpts(cfg.Opts)
+		if err != nil {
+			return nil, err
+		}
+		b := &Builder{client: cfg.Client, fId: cfg.Id}
+		if opts.FastFinality {
+			b.validators = NewValidatorCache(NewEpochs(cfg.Client, opts.Forks))
+		}
+		return b, nil
+	})
+}
+
+// Builder assembles the proof with HeaderCountOfBsc headers starting at the block of the log, or in the fast
+// finality mode with the header of the block and the two after it finalizing it
+type Builder struct {
+	client     *ethclient.Client
+	fId        msg.ChainId
+	validators *ValidatorCache // nil when the fast finality is off
+}
+
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return iproof.Receipts(b.client, number)
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	latestBlock := new(big.Int).SetUint64(log.BlockNumber)
+	if b.validators != nil {
+		headers, err := FinalizedHeaders(ctx, b.client, latestBlock, b.validators)
+		if err != nil {
+			return nil, err
+		}
+		params := make([]Header, 0, len(headers))
+		for _, h := range headers {
+			params = append(params, ConvertHeader(*h))
+		}
+		return AssembleProof(params, *log, receipts, iproof.Method(log.Topics[0]), b.fId)
 	}
 
 	headers := make([]types.Header, mapprotocol.HeaderCountOfBsc)
 	for i := 0; i < mapprotocol.HeaderCountOfBsc; i++ {
 		headerHeight := new(big.Int).Add(latestBlock, new(big.Int).SetInt64(int64(i)))
-		header, err := client.HeaderByNumber(context.Background(), headerHeight)
+		header, err := b.client.HeaderByNumber(ctx, headerHeight)
 		if err != nil {
 			return nil, err
 		}
@@ -105,17 +135,17 @@ func GetProof(client *ethclient.Client, latestBlock *big.Int, log *types.Log, me
 	for _, h := range headers {
 		params = append(params, ConvertHeader(h))
 	}
-	return AssembleProof(params, *log, receipts, method, fId)
+	return AssembleProof(params, *log, receipts, iproof.Method(log.Topics[0]), b.fId)
 }
 
-func AssembleProof(header []Header, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) ([]byte, error) {
+func AssembleProof(header []Header, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) (*iproof.Proof, error) {
 	txIndex := log.TxIndex
 	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
 	if err != nil {
 		return nil, err
 	}
 
-	proof, err := iproof.Get(receipts, txIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -142,5 +172,16 @@ func AssembleProof(header []Header, log types.Log, receipts []*types.Receipt, me
 	if err != nil {
 		return nil, err
 	}
-	return pack, nil
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: common.BytesToHash(header[0].ReceiptsRoot),
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
 }
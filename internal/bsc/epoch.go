@@ -0,0 +1,124 @@
+package bsc
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	defaultEpochLength = 200
+	lorentzEpochLength = 500
+	maxwellEpochLength = 1000
+	// headersKept bounds the epoch headers cached by Epochs
+	headersKept = 16
+)
+
+// Forks are the times of the forks changing the epochs of bsc. Since Bohr an epoch header carries the turn length
+// of its validators, the number of the blocks a validator produces in a row. Lorentz and Maxwell lengthen the
+// epochs, the new length takes effect at the first block after the fork whose number is a multiple of it.
+type Forks struct {
+	Bohr    uint64
+	Lorentz uint64
+	Maxwell uint64
+}
+
+// MainnetForks are the fork times of bsc mainnet
+var MainnetForks = Forks{Bohr: 1727317200, Lorentz: 1745903100, Maxwell: 1751250600}
+
+type epochLength struct {
+	length uint64
+	since  uint64 // the time of the fork
+}
+
+// epochLengths are the lengths of the epochs, the latest fork first
+func (f *Forks) epochLengths() []epochLength {
+	return []epochLength{
+		{maxwellEpochLength, f.Maxwell},
+		{lorentzEpochLength, f.Lorentz},
+		{defaultEpochLength, 0},
+	}
+}
+
+// TurnLength returns the turn length of the validators of the epoch header, it is 1 before Bohr
+func (f *Forks) TurnLength(epoch *types.Header) (uint64, error) {
+	if epoch.Time < f.Bohr {
+		return 1, nil
+	}
+	extra := epoch.Extra
+	if len(extra) <= extraVanity+extraSeal {
+		return 0, fmt.Errorf("header %d has no validators", epoch.Number)
+	}
+	pos := extraVanity + validatorNumberSize + int(extra[extraVanity])*validatorBytesLength
+	if pos >= len(extra)-extraSeal || extra[pos] == 0 {
+		return 0, fmt.Errorf("epoch header %d has no turn length", epoch.Number)
+	}
+	return uint64(extra[pos]), nil
+}
+
+// Epochs finds the epoch headers of the blocks by the fork times, it is safe for concurrent use
+type Epochs struct {
+	client HeaderReader
+	forks  Forks
+
+	lock    sync.Mutex
+	headers map[uint64]*types.Header // the headers read at the multiples of the epoch lengths
+}
+
+func NewEpochs(client HeaderReader, forks Forks) *Epochs {
+	return &Epochs{client: client, forks: forks, headers: make(map[uint64]*types.Header)}
+}
+
+// Start returns the epoch header of the epoch of the block. The block of the latest length is its epoch header
+// when it is after the fork of the length, the new length has taken effect at it or before.
+func (e *Epochs) Start(ctx context.Context, number uint64) (*types.Header, error) {
+	for _, l := range e.forks.epochLengths() {
+		h, err := e.header(ctx, number-number%l.length)
+		if err != nil {
+			return nil, err
+		}
+		if h.Time >= l.since {
+			return h, nil
+		}
+	}
+	return nil, fmt.Errorf("no epoch of block %d", number)
+}
+
+// IsStart is whether the block is an epoch header, the headers are only read for the multiples of the lengths
+func (e *Epochs) IsStart(ctx context.Context, number uint64) (bool, error) {
+	for _, l := range e.forks.epochLengths() {
+		if number%l.length != 0 {
+			continue
+		}
+		h, err := e.Start(ctx, number)
+		if err != nil {
+			return false, err
+		}
+		return h.Number.Uint64() == number, nil
+	}
+	return false, nil
+}
+
+func (e *Epochs) header(ctx context.Context, number uint64) (*types.Header, error) {
+	e.lock.Lock()
+	h, ok := e.headers[number]
+	e.lock.Unlock()
+	if ok {
+		return h, nil
+	}
+	h, err := e.client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if len(e.headers) >= headersKept {
+		e.headers = make(map[uint64]*types.Header)
+	}
+	e.headers[number] = h
+	return h, nil
+}
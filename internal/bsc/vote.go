@@ -0,0 +1,301 @@
+package bsc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"math/bits"
+	"sort"
+	"strconv"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/pkg/bls"
+)
+
+const (
+	FastFinalityOpt = "fastFinality"
+	BohrTimeOpt     = "bohrTime"
+	LorentzTimeOpt  = "lorentzTime"
+	MaxwellTimeOpt  = "maxwellTime"
+)
+
+const (
+	extraVanity          = 32
+	extraSeal            = 65
+	validatorNumberSize  = 1
+	validatorBytesLength = common.AddressLength + bls.PublicKeyLength
+	// epochsKept is the number of the epochs whose validators are cached
+	epochsKept = 4
+)
+
+// ErrNotFinalized is returned when the vote attestations of the headers do not finalize the first one
+var ErrNotFinalized = errors.New("bsc header not finalized")
+
+// Options are the options of a bsc chain in the config
+type Options struct {
+	FastFinality bool
+	Forks        Forks // the fork times of the chain, those of mainnet by default
+}
+
+func ParseOpts(opts map[string]string) (*Options, error) {
+	o := &Options{Forks: MainnetForks}
+	if v, ok := opts[FastFinalityOpt]; ok && v != "" {
+		b, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", FastFinalityOpt, err)
+		}
+		o.FastFinality = b
+	}
+	for opt, t := range map[string]*uint64{BohrTimeOpt: &o.Forks.Bohr, LorentzTimeOpt: &o.Forks.Lorentz,
+		MaxwellTimeOpt: &o.Forks.Maxwell} {
+		v, ok := opts[opt]
+		if !ok || v == "" {
+			continue
+		}
+		n, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", opt, err)
+		}
+		*t = n
+	}
+	return o, nil
+}
+
+// VoteData is the source and the target of the votes, the target is justified by a quorum of the votes and the
+// source is finalized when the target is its child
+type VoteData struct {
+	SourceNumber uint64
+	SourceHash   common.Hash
+	TargetNumber uint64
+	TargetHash   common.Hash
+}
+
+// Hash is the message the validators sign
+func (d *VoteData) Hash() common.Hash {
+	data, _ := rlp.EncodeToBytes(d)
+	return crypto.Keccak256Hash(data)
+}
+
+// VoteAttestation is the aggregated votes for the parent a header carries in its extra data since Luban
+type VoteAttestation struct {
+	VoteAddressSet uint64 // the bits of the voting validators in the order of their addresses
+	AggSignature   [bls.SignatureLength]byte
+	Data           *VoteData
+	Extra          []byte
+}
+
+// Validator is a validator of an epoch with the bls public key it votes with
+type Validator struct {
+	Address     common.Address
+	VoteAddress []byte
+}
+
+// ParseValidators returns the validators set in the extra data of the epoch header, in the order of their addresses
+func ParseValidators(header *types.Header) ([]Validator, error) {
+	extra := header.Extra
+	if len(extra) <= extraVanity+extraSeal {
+		return nil, fmt.Errorf("header %d has no validators", header.Number)
+	}
+	num := int(extra[extraVanity])
+	start := extraVanity + validatorNumberSize
+	if num == 0 || len(extra) < start+num*validatorBytesLength+extraSeal {
+		return nil, fmt.Errorf("header %d has %d validators in %d bytes of extra", header.Number, num, len(extra))
+	}
+	ret := make([]Validator, 0, num)
+	for i := 0; i < num; i++ {
+		raw := extra[start+i*validatorBytesLength : start+(i+1)*validatorBytesLength]
+		ret = append(ret, Validator{
+			Address:     common.BytesToAddress(raw[:common.AddressLength]),
+			VoteAddress: append([]byte{}, raw[common.AddressLength:]...),
+		})
+	}
+	sort.Slice(ret, func(i, j int) bool { return bytes.Compare(ret[i].Address[:], ret[j].Address[:]) < 0 })
+	return ret, nil
+}
+
+// ParseVoteAttestation returns the vote attestation in the extra data of the header, nil when it has none. The
+// attestation of an epoch header is after its validators.
+func ParseVoteAttestation(header *types.Header, epoch bool) (*VoteAttestation, error) {
+	extra := header.Extra
+	if len(extra) <= extraVanity+extraSeal {
+		return nil, nil
+	}
+	start, end := extraVanity, len(extra)-extraSeal
+	if epoch {
+		start += validatorNumberSize + int(extra[extraVanity])*validatorBytesLength
+		// the turn length of the validators follows them since Bohr, an attestation is a rlp list
+		if start < end && extra[start] < 0xc0 {
+			start++
+		}
+	}
+	if start >= end {
+		return nil, nil
+	}
+	att := new(VoteAttestation)
+	if err := rlp.DecodeBytes(extra[start:end], att); err != nil {
+		return nil, fmt.Errorf("decode vote attestation of header %d: %w", header.Number, err)
+	}
+	if att.Data == nil {
+		return nil, fmt.Errorf("vote attestation of header %d has no data", header.Number)
+	}
+	return att, nil
+}
+
+// Verify checks the attestation is signed by a quorum, two thirds rounded up, of the validators
+func (a *VoteAttestation) Verify(validators []Validator) error {
+	if len(validators) == 0 || len(validators) > 64 {
+		return fmt.Errorf("%d validators can not vote", len(validators))
+	}
+	if a.VoteAddressSet>>uint(len(validators)) != 0 {
+		return fmt.Errorf("votes of %x out of the %d validators", a.VoteAddressSet, len(validators))
+	}
+	if voted, quorum := bits.OnesCount64(a.VoteAddressSet), (len(validators)*2+2)/3; voted < quorum {
+		return fmt.Errorf("%d votes of %d validators, want %d", voted, len(validators), quorum)
+	}
+	pubkeys := make([]*bls12381.PointG1, 0, len(validators))
+	for i, v := range validators {
+		if a.VoteAddressSet&(1<<uint(i)) == 0 {
+			continue
+		}
+		pk, err := bls.PublicKeyFromBytes(v.VoteAddress)
+		if err != nil {
+			return fmt.Errorf("vote address of validator %s: %w", v.Address, err)
+		}
+		pubkeys = append(pubkeys, pk)
+	}
+	hash := a.Data.Hash()
+	return bls.FastAggregateVerify(pubkeys, hash[:], a.AggSignature[:])
+}
+
+// HeaderReader reads the headers of bsc
+type HeaderReader interface {
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+}
+
+// ValidatorCache returns the validators voting at a block from the epoch headers, it is safe for concurrent use
+type ValidatorCache struct {
+	epochs *Epochs
+	lock   sync.Mutex
+	cached map[uint64][]Validator // by the number of the epoch header
+}
+
+func NewValidatorCache(epochs *Epochs) *ValidatorCache {
+	return &ValidatorCache{epochs: epochs, cached: make(map[uint64][]Validator)}
+}
+
+func (c *ValidatorCache) epochValidators(epoch *types.Header) ([]Validator, error) {
+	number := epoch.Number.Uint64()
+	c.lock.Lock()
+	vs, ok := c.cached[number]
+	c.lock.Unlock()
+	if ok {
+		return vs, nil
+	}
+	vs, err := ParseValidators(epoch)
+	if err != nil {
+		return nil, err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.cached[number] = vs
+	for n := range c.cached {
+		if n+epochsKept*maxwellEpochLength <= number {
+			delete(c.cached, n)
+		}
+	}
+	return vs, nil
+}
+
+// At returns the validators after the block is applied. The validators of an epoch header take over when the
+// validators before have produced the turns of half of them in the epoch, at the block (len/2+1)*turnLength-1 of
+// the epoch with their number and turn length.
+func (c *ValidatorCache) At(ctx context.Context, number uint64) ([]Validator, error) {
+	start, err := c.epochs.Start(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	cur, err := c.epochValidators(start)
+	if err != nil || start.Number.Sign() == 0 {
+		return cur, err
+	}
+	prevStart, err := c.epochs.Start(ctx, start.Number.Uint64()-1)
+	if err != nil {
+		return nil, err
+	}
+	prev, err := c.epochValidators(prevStart)
+	if err != nil {
+		return nil, err
+	}
+	turnLength, err := c.epochs.forks.TurnLength(prevStart)
+	if err != nil {
+		return nil, err
+	}
+	if number-start.Number.Uint64() >= (uint64(len(prev))/2+1)*turnLength-1 {
+		return cur, nil
+	}
+	return prev, nil
+}
+
+// VerifyFinality checks the headers are a block and the two after it, the second justifying the block by a quorum
+// of the votes and the third finalizing it. The votes in a header are of the validators at its grandparent.
+func VerifyFinality(ctx context.Context, headers []*types.Header, validators *ValidatorCache) error {
+	if len(headers) != mapprotocol.HeaderCountOfBscFinal {
+		return fmt.Errorf("%w: %d headers, want %d", ErrNotFinalized, len(headers), mapprotocol.HeaderCountOfBscFinal)
+	}
+	for i := 1; i < len(headers); i++ {
+		parent := headers[i-1]
+		if headers[i].ParentHash != parent.Hash() || headers[i].Number.Uint64() != parent.Number.Uint64()+1 {
+			return fmt.Errorf("%w: header %d is not the child of %d", ErrNotFinalized, headers[i].Number, parent.Number)
+		}
+		epoch, err := validators.epochs.IsStart(ctx, headers[i].Number.Uint64())
+		if err != nil {
+			return err
+		}
+		att, err := ParseVoteAttestation(headers[i], epoch)
+		if err != nil {
+			return err
+		}
+		if att == nil {
+			return fmt.Errorf("%w: header %d has no vote attestation", ErrNotFinalized, headers[i].Number)
+		}
+		if att.Data.TargetNumber != parent.Number.Uint64() || att.Data.TargetHash != parent.Hash() {
+			return fmt.Errorf("%w: votes of header %d target %d", ErrNotFinalized, headers[i].Number, att.Data.TargetNumber)
+		}
+		if i == 2 && (att.Data.SourceNumber != headers[0].Number.Uint64() || att.Data.SourceHash != headers[0].Hash()) {
+			return fmt.Errorf("%w: votes of header %d are from source %d", ErrNotFinalized, headers[i].Number, att.Data.SourceNumber)
+		}
+		vs, err := validators.At(ctx, parent.Number.Uint64()-1)
+		if err != nil {
+			return err
+		}
+		if err = att.Verify(vs); err != nil {
+			return fmt.Errorf("%w: votes of header %d: %v", ErrNotFinalized, headers[i].Number, err)
+		}
+	}
+	return nil
+}
+
+// FinalizedHeaders returns the header of the block and the two after it finalizing it
+func FinalizedHeaders(ctx context.Context, client HeaderReader, number *big.Int, validators *ValidatorCache) ([]*types.Header, error) {
+	headers := make([]*types.Header, 0, mapprotocol.HeaderCountOfBscFinal)
+	for i := int64(0); i < mapprotocol.HeaderCountOfBscFinal; i++ {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).Add(number, big.NewInt(i)))
+		if err != nil {
+			return nil, err
+		}
+		headers = append(headers, header)
+	}
+	if err := VerifyFinality(ctx, headers, validators); err != nil {
+		return nil, err
+	}
+	return headers, nil
+}
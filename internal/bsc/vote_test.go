@@ -0,0 +1,272 @@
+package bsc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/pkg/bls"
+)
+
+const testEpoch = defaultEpochLength
+
+// preBohr is before all the forks, the epochs are of 200 blocks and the validators take turns of one block
+var preBohr = Forks{Bohr: 1 << 40, Lorentz: 1 << 40, Maxwell: 1 << 40}
+
+// bohr has the turn lengths in the epoch headers and the epochs of 200 blocks
+var bohr = Forks{Lorentz: 1 << 40, Maxwell: 1 << 40}
+
+type headerMap map[uint64]*types.Header
+
+func (m headerMap) HeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	h, ok := m[number.Uint64()]
+	if !ok {
+		return nil, fmt.Errorf("header %s not found", number)
+	}
+	return h, nil
+}
+
+// the validator of a secret key has it as its address, so the keys are in the order of the addresses
+func validatorBytes(sks ...int64) []byte {
+	g1 := bls12381.NewG1()
+	ret := []byte{byte(len(sks))}
+	for _, sk := range sks {
+		ret = append(ret, common.BigToAddress(big.NewInt(sk)).Bytes()...)
+		ret = append(ret, bls.CompressG1(g1.MulScalar(g1.New(), g1.One(), big.NewInt(sk)))...)
+	}
+	return ret
+}
+
+// sign returns the attestation of the data with the bits of the voters and the signature of the signers
+func sign(t *testing.T, data *VoteData, voted uint64, signers ...int64) *VoteAttestation {
+	hash := data.Hash()
+	h, err := bls.HashToG2(hash[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	sum := new(big.Int)
+	for _, sk := range signers {
+		sum.Add(sum, big.NewInt(sk))
+	}
+	g2 := bls12381.NewG2()
+	att := &VoteAttestation{VoteAddressSet: voted, Data: data}
+	copy(att.AggSignature[:], bls.CompressG2(g2.MulScalar(g2.New(), h, sum)))
+	return att
+}
+
+func extraOf(t *testing.T, validators []byte, att *VoteAttestation) []byte {
+	extra := append(make([]byte, extraVanity), validators...)
+	if att != nil {
+		raw, err := rlp.EncodeToBytes(att)
+		if err != nil {
+			t.Fatal(err)
+		}
+		extra = append(extra, raw...)
+	}
+	return append(extra, make([]byte, extraSeal)...)
+}
+
+// switchOf returns the block the validators 5 to 7 take over at, with the four validators before them taking turns
+// of the length
+func switchOf(turnLength uint64) uint64 {
+	return testEpoch + (4/2+1)*turnLength - 1
+}
+
+// newChain builds the headers up to two after the switch, the validators are 1 to 4 from the header 0 and 5 to 7 from
+// the epoch header after it. The epoch headers carry the turn length unless it is 1 before Bohr, and the two headers
+// after the switch carry the attestations the votes return from the headers before them.
+func newChain(t *testing.T, turnLength uint64, withTurn bool, voteA, voteB func(chain headerMap) *VoteAttestation) headerMap {
+	chain := make(headerMap)
+	var parent common.Hash
+	sw := switchOf(turnLength)
+	for n := uint64(0); n <= sw+2; n++ {
+		var validators []byte
+		switch n {
+		case 0:
+			validators = validatorBytes(1, 2, 3, 4)
+		case testEpoch:
+			validators = validatorBytes(5, 6, 7)
+		}
+		if validators != nil && withTurn {
+			validators = append(validators, byte(turnLength))
+		}
+		var att *VoteAttestation
+		switch n {
+		case sw + 1:
+			att = voteA(chain)
+		case sw + 2:
+			att = voteB(chain)
+		}
+		h := &types.Header{
+			ParentHash: parent,
+			Number:     new(big.Int).SetUint64(n),
+			Difficulty: big.NewInt(2),
+			Extra:      extraOf(t, validators, att),
+		}
+		chain[n] = h
+		parent = h.Hash()
+	}
+	return chain
+}
+
+func dataOf(source, target *types.Header) *VoteData {
+	return &VoteData{
+		SourceNumber: source.Number.Uint64(),
+		SourceHash:   source.Hash(),
+		TargetNumber: target.Number.Uint64(),
+		TargetHash:   target.Hash(),
+	}
+}
+
+func TestParseOpts(t *testing.T) {
+	opts, err := ParseOpts(map[string]string{FastFinalityOpt: "true", MaxwellTimeOpt: "1100"})
+	if err != nil || !opts.FastFinality || opts.Forks.Maxwell != 1100 || opts.Forks.Lorentz != MainnetForks.Lorentz {
+		t.Fatalf("options %+v %v", opts, err)
+	}
+	opts, err = ParseOpts(nil)
+	if err != nil || opts.FastFinality || opts.Forks != MainnetForks {
+		t.Fatalf("default options %+v %v", opts, err)
+	}
+	if _, err = ParseOpts(map[string]string{FastFinalityOpt: "yes"}); err == nil {
+		t.Fatal("invalid option is parsed")
+	}
+	if _, err = ParseOpts(map[string]string{BohrTimeOpt: "-1"}); err == nil {
+		t.Fatal("invalid fork time is parsed")
+	}
+}
+
+func TestParseVoteAttestation(t *testing.T) {
+	data := &VoteData{SourceNumber: 8, TargetNumber: 9}
+	att := sign(t, data, 0x7, 1, 2, 3)
+	// the epoch header of Bohr has the turn length after the validators
+	validators := append(validatorBytes(1, 2, 3), 4)
+	header := &types.Header{Number: big.NewInt(testEpoch), Extra: extraOf(t, validators, att)}
+	got, err := ParseVoteAttestation(header, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got == nil || got.VoteAddressSet != 0x7 || got.AggSignature != att.AggSignature || *got.Data != *data {
+		t.Fatalf("attestation %+v", got)
+	}
+	vs, err := ParseValidators(header)
+	if err != nil || len(vs) != 3 || vs[2].Address != common.BigToAddress(big.NewInt(3)) {
+		t.Fatalf("validators %+v %v", vs, err)
+	}
+	if turn, err := bohr.TurnLength(header); err != nil || turn != 4 {
+		t.Fatalf("turn length %d %v", turn, err)
+	}
+	if turn, err := preBohr.TurnLength(header); err != nil || turn != 1 {
+		t.Fatalf("turn length before bohr %d %v", turn, err)
+	}
+
+	header = &types.Header{Number: big.NewInt(testEpoch + 1), Extra: extraOf(t, nil, nil)}
+	if got, err = ParseVoteAttestation(header, false); got != nil || err != nil {
+		t.Fatalf("attestation of header without votes %+v %v", got, err)
+	}
+}
+
+func TestEpochs(t *testing.T) {
+	// the time of a header is its number, Lorentz is at 450 and Maxwell at 1100
+	chain := make(headerMap)
+	for n := uint64(0); n <= 2100; n++ {
+		chain[n] = &types.Header{Number: new(big.Int).SetUint64(n), Time: n}
+	}
+	epochs := NewEpochs(chain, Forks{Lorentz: 450, Maxwell: 1100})
+	ctx := context.Background()
+	starts := map[uint64]uint64{0: 0, 199: 0, 450: 400, 499: 400, 500: 500, 999: 500, 1000: 1000, 1100: 1000,
+		1999: 1500, 2000: 2000, 2100: 2000}
+	for number, want := range starts {
+		h, err := epochs.Start(ctx, number)
+		if err != nil || h.Number.Uint64() != want {
+			t.Errorf("epoch of %d: %v %v, want %d", number, h, err, want)
+		}
+	}
+	for number, want := range map[uint64]bool{400: true, 600: false, 1000: true, 1200: false, 1500: true,
+		2000: true, 1001: false} {
+		if got, err := epochs.IsStart(ctx, number); err != nil || got != want {
+			t.Errorf("block %d is epoch: %v %v", number, got, err)
+		}
+	}
+}
+
+func TestValidatorCacheAt(t *testing.T) {
+	noVote := func(headerMap) *VoteAttestation { return nil }
+	ctx := context.Background()
+	for _, c := range []struct {
+		forks      Forks
+		turnLength uint64
+	}{{preBohr, 1}, {bohr, 2}, {bohr, 4}} {
+		sw := switchOf(c.turnLength)
+		chain := newChain(t, c.turnLength, c.forks == bohr, noVote, noVote)
+		validators := NewValidatorCache(NewEpochs(chain, c.forks))
+		for number, want := range map[uint64]int{testEpoch - 1: 4, testEpoch: 4, sw - 1: 4, sw: 3, sw + 1: 3} {
+			vs, err := validators.At(ctx, number)
+			if err != nil || len(vs) != want {
+				t.Errorf("turn length %d: %d validators at %d %v, want %d", c.turnLength, len(vs), number, err, want)
+			}
+		}
+	}
+}
+
+func TestVerifyFinality(t *testing.T) {
+	ctx := context.Background()
+	for _, c := range []struct {
+		forks      Forks
+		turnLength uint64
+	}{{preBohr, 1}, {bohr, 2}} {
+		sw := switchOf(c.turnLength)
+		newChain := func(voteA, voteB func(chain headerMap) *VoteAttestation) headerMap {
+			return newChain(t, c.turnLength, c.forks == bohr, voteA, voteB)
+		}
+		// the votes after the switch are of the validators 1 to 4, the validators 5 to 7 take over at the switch
+		// and vote in the header after
+		voteA := func(chain headerMap) *VoteAttestation {
+			return sign(t, dataOf(chain[sw-1], chain[sw]), 0xe, 2, 3, 4)
+		}
+		chain := newChain(voteA, func(chain headerMap) *VoteAttestation {
+			return sign(t, dataOf(chain[sw], chain[sw+1]), 0x3, 5, 6)
+		})
+		validators := NewValidatorCache(NewEpochs(chain, c.forks))
+		headers, err := FinalizedHeaders(ctx, chain, new(big.Int).SetUint64(sw), validators)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(headers) != 3 || headers[0].Number.Uint64() != sw {
+			t.Fatalf("finalized headers %v", headers)
+		}
+		if _, err = FinalizedHeaders(ctx, chain, new(big.Int).SetUint64(sw-1), validators); !errors.Is(err, ErrNotFinalized) {
+			t.Fatalf("header without votes is finalized: %v", err)
+		}
+
+		cases := map[string]func(chain headerMap) *VoteAttestation{
+			"below quorum": func(chain headerMap) *VoteAttestation {
+				return sign(t, dataOf(chain[sw], chain[sw+1]), 0x1, 5)
+			},
+			"wrong target": func(chain headerMap) *VoteAttestation {
+				return sign(t, dataOf(chain[sw-1], chain[sw]), 0x3, 5, 6)
+			},
+			"wrong source": func(chain headerMap) *VoteAttestation {
+				return sign(t, dataOf(chain[sw-1], chain[sw+1]), 0x3, 5, 6)
+			},
+			"bad signature": func(chain headerMap) *VoteAttestation {
+				return sign(t, dataOf(chain[sw], chain[sw+1]), 0x3, 5, 7)
+			},
+			"validators before the switch": func(chain headerMap) *VoteAttestation {
+				return sign(t, dataOf(chain[sw], chain[sw+1]), 0x7, 1, 2, 3)
+			},
+		}
+		for name, voteB := range cases {
+			bad := newChain(voteA, voteB)
+			_, err = FinalizedHeaders(ctx, bad, new(big.Int).SetUint64(sw), NewValidatorCache(NewEpochs(bad, c.forks)))
+			if !errors.Is(err, ErrNotFinalized) {
+				t.Errorf("turn length %d, %s: %v", c.turnLength, name, err)
+			}
+		}
+	}
+}
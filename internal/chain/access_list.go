@@ -0,0 +1,79 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+type AccessListMetrics struct {
+	Used     prometheus.Counter
+	Skipped  prometheus.Counter
+	GasSaved prometheus.Counter
+}
+
+func NewAccessListMetrics(chain string) *AccessListMetrics {
+	m := &AccessListMetrics{
+		Used: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_access_list_used", chain),
+			Help: "Number of txs sent with access list",
+		}),
+		Skipped: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_access_list_skipped", chain),
+			Help: "Number of txs sent without access list since it did not lower the gas",
+		}),
+		GasSaved: prometheus.NewCounter(prometheus.CounterOpts{
+			Name: fmt.Sprintf("%s_access_list_gas_saved", chain),
+			Help: "Estimated gas saved by access lists",
+		}),
+	}
+
+	prometheus.MustRegister(m.Used)
+	prometheus.MustRegister(m.Skipped)
+	prometheus.MustRegister(m.GasSaved)
+
+	return m
+}
+
+// accessList asks the node for the access list of the call, the list is only returned
+// when the gas estimated with it is lower than gasLimit
+func (w *Writer) accessList(msg ethereum.CallMsg, gasLimit uint64) (types.AccessList, uint64) {
+	al, _, vmErr, err := w.conn.Client().CreateAccessList(context.Background(), msg)
+	if err != nil {
+		w.log.Warn("CreateAccessList failed, send without access list", "err", err)
+		return nil, gasLimit
+	}
+	if vmErr != "" {
+		w.log.Warn("CreateAccessList execution failed, send without access list", "err", vmErr)
+		return nil, gasLimit
+	}
+	if al == nil || len(*al) == 0 {
+		w.log.Debug("Access list is empty, send without access list")
+		return nil, gasLimit
+	}
+
+	msg.AccessList = *al
+	withList, err := w.conn.Client().EstimateGas(context.Background(), msg)
+	if err != nil {
+		w.log.Warn("EstimateGas with access list failed, send without access list", "err", err)
+		return nil, gasLimit
+	}
+	if withList >= gasLimit {
+		w.log.Info("Access list does not lower the gas, send without access list", "gas", gasLimit, "withAccessList", withList)
+		if w.alMetrics != nil {
+			w.alMetrics.Skipped.Inc()
+		}
+		return nil, gasLimit
+	}
+
+	w.log.Info("Send with access list", "gas", gasLimit, "withAccessList", withList, "saved", gasLimit-withList,
+		"addresses", len(*al), "storageKeys", al.StorageKeys())
+	if w.alMetrics != nil {
+		w.alMetrics.Used.Inc()
+		w.alMetrics.GasSaved.Add(float64(gasLimit - withList))
+	}
+	return *al, withList
+}
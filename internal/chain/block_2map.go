@@ -46,7 +46,7 @@ func (w *Writer) execToMapMsg(m msg.Message) bool {
 				errorCount++
 				if errorCount >= 10 {
 					util.Alarm(context.Background(), fmt.Sprintf("%s2map updateHeader failed, err is %s",
-						mapprotocol.OnlineChaId[m.Source], err.Error()))
+						w.mc.Name(m.Source), err.Error()))
 					errorCount = 0
 				}
 				continue
@@ -73,13 +73,15 @@ func (w *Writer) toMap(m msg.Message, id *big.Int, marshal []byte, method string
 		w.conn.UnlockOpts()
 		return err
 	}
-	tx, err := w.sendTx(&w.cfg.LightNode, nil, data)
+	entry := newEntry(m, method)
+	tx, err := w.sendTx(&w.cfg.LightNode, nil, data, entry)
 	w.conn.UnlockOpts()
 	if err == nil {
 		// message successfully handled
 		w.log.Info("Sync Header to map tx execution", "tx", tx.Hash(), "src", m.Source, "dst", m.Destination,
 			"method", method, "needNonce", needNonce, "nonce", w.conn.Opts().Nonce)
 		err = w.txStatus(tx.Hash())
+		w.finish(entry, err)
 		if err != nil {
 			w.log.Warn("TxHash Status is not successful, will retry", "err", err)
 		} else {
@@ -6,8 +6,6 @@ import (
 	"strings"
 	"time"
 
-	"github.com/mapprotocol/compass/mapprotocol"
-
 	"github.com/mapprotocol/compass/internal/constant"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/util"
@@ -32,12 +30,14 @@ func (w *Writer) execMap2OtherMsg(m msg.Message) bool {
 			}
 			// These store the gas limit and price before a transaction is sent for logging in case of a failure
 			// This is necessary as tx will be nil in the case of an error when sending VoteProposal()
-			tx, err := w.sendTx(&w.cfg.LightNode, nil, m.Payload[0].([]byte))
+			entry := newEntry(m, "")
+			tx, err := w.sendTx(&w.cfg.LightNode, nil, m.Payload[0].([]byte), entry)
 			w.conn.UnlockOpts()
 			if err == nil {
 				// message successfully handled
 				w.log.Info("Sync Map Header to other chain tx execution", "tx", tx.Hash(), "src", m.Source, "dst", m.Destination, "needNonce", needNonce, "nonce", w.conn.Opts().Nonce)
 				err = w.txStatus(tx.Hash())
+				w.finish(entry, err)
 				if err != nil {
 					w.log.Warn("TxHash Status is not successful, will retry", "err", err)
 				} else {
@@ -57,7 +57,7 @@ func (w *Writer) execMap2OtherMsg(m msg.Message) bool {
 			needNonce = w.needNonce(err)
 			errorCount++
 			if errorCount >= 10 {
-				util.Alarm(context.Background(), fmt.Sprintf("map2%s updateHeader failed, err is %s", mapprotocol.OnlineChaId[m.Destination], err.Error()))
+				util.Alarm(context.Background(), fmt.Sprintf("map2%s updateHeader failed, err is %s", w.mc.Name(m.Destination), err.Error()))
 				errorCount = 0
 			}
 			time.Sleep(constant.TxRetryInterval)
@@ -23,7 +23,7 @@ type Chain struct {
 }
 
 func New(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m *metrics.ChainMetrics,
-	role mapprotocol.Role, createConn CreateConn, opts ...SyncOpt) (*Chain, error) {
+	role mapprotocol.Role, mc *mapprotocol.MapContext, createConn CreateConn, opts ...SyncOpt) (*Chain, error) {
 	cfg, err := ParseConfig(chainCfg)
 	if err != nil {
 		return nil, err
@@ -40,6 +40,16 @@ func New(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m
 		return nil, err
 	}
 
+	jn, err := SetupJournal(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
+	dl, err := SetupDeadLetter(cfg, kp, role)
+	if err != nil {
+		return nil, err
+	}
+
 	stop := make(chan int)
 	conn := createConn(cfg.Endpoint, cfg.Http, kp, logger, cfg.GasLimit, cfg.MaxGasPrice,
 		cfg.GasMultiplier, cfg.EgsApiKey, cfg.EgsSpeed)
@@ -57,7 +67,7 @@ func New(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m
 	}
 
 	var listen chains.Listener
-	cs := NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs, opts...)
+	cs := NewCommonSync(conn, cfg, logger, stop, sysErr, m, bs, mc, append(opts, OptOfDeadLetter(dl))...)
 	if role == mapprotocol.RoleOfMaintainer { // 请求获取同步的map高度
 		fn := mapprotocol.Map2EthHeight(cfg.From, cfg.LightNode, conn.Client())
 		height, err := fn()
@@ -65,8 +75,7 @@ func New(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m
 			return nil, errors.Wrap(err, "get init headerHeight failed")
 		}
 		logger.Info("Map2other Current situation", "id", cfg.Id, "height", height, "lightNode", cfg.LightNode)
-		mapprotocol.SyncOtherMap[cfg.Id] = height
-		mapprotocol.Map2OtherHeight[cfg.Id] = fn
+		mc.SetMap2OtherHeight(cfg.Id, height, fn)
 		listen = NewMaintainer(cs)
 	} else if role == mapprotocol.RoleOfMessenger {
 		err = conn.EnsureHasBytecode(cfg.McsContract)
@@ -80,10 +89,10 @@ func New(chainCfg *core.ChainConfig, logger log15.Logger, sysErr chan<- error, m
 			return nil, errors.Wrap(err, "get init verifyHeight failed")
 		}
 		logger.Info("Map2other Current verify range", "id", cfg.Id, "left", left, "right", right, "lightNode", cfg.LightNode)
-		mapprotocol.Map2OtherVerifyRange[cfg.Id] = fn
+		mc.SetMap2OtherVerifyRange(cfg.Id, fn)
 		listen = NewMessenger(cs)
 	}
-	wri := NewWriter(conn, cfg, logger, stop, sysErr)
+	wri := NewWriter(conn, cfg, logger, stop, sysErr, m, jn, mc)
 
 	return &Chain{
 		cfg:    chainCfg,
@@ -100,7 +109,12 @@ func (c *Chain) SetRouter(r *core.Router) {
 }
 
 func (c *Chain) Start() error {
-	err := c.listen.Sync()
+	err := c.writer.Start()
+	if err != nil {
+		return err
+	}
+
+	err = c.listen.Sync()
 	if err != nil {
 		return err
 	}
@@ -129,7 +143,7 @@ func (c *Chain) Stop() {
 	}
 }
 
-// EthClient return EthClient for global map connection
+// EthClient return EthClient for the map connection
 func (c *Chain) EthClient() *ethclient.Client {
 	return c.conn.Client()
 }
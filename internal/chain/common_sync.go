@@ -6,6 +6,7 @@ import (
 
 	eth "github.com/ethereum/go-ethereum"
 	ethcommon "github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
 
@@ -13,6 +14,7 @@ import (
 	"github.com/ChainSafe/log15"
 	"github.com/mapprotocol/compass/blockstore"
 	"github.com/mapprotocol/compass/chains"
+	"github.com/mapprotocol/compass/deadletter"
 )
 
 type (
@@ -46,6 +48,12 @@ func OptOfSync2Map(fn SyncHeader2Map) SyncOpt {
 	}
 }
 
+func OptOfDeadLetter(dl deadletter.DeadLetterer) SyncOpt {
+	return func(sync *CommonSync) {
+		sync.DeadLetter = dl
+	}
+}
+
 func OptOfMos(fn Mos) SyncOpt {
 	return func(sync *CommonSync) {
 		sync.mosHandler = fn
@@ -61,6 +69,7 @@ func OptOfSyncMap2Other(fn SyncMap2Other) SyncOpt {
 type CommonSync struct {
 	Cfg                Config
 	Conn               Connection
+	Map                *mapprotocol.MapContext
 	Log                log15.Logger
 	Router             chains.Router
 	Stop               <-chan int
@@ -74,14 +83,17 @@ type CommonSync struct {
 	syncMap2Other      SyncMap2Other
 	syncHeaderToMap    SyncHeader2Map
 	mosHandler         Mos
+	DeadLetter         deadletter.DeadLetterer
+	proofBuilder       proof.ProofBuilder
 }
 
 // NewCommonSync creates and returns a listener
 func NewCommonSync(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error,
-	m *metrics.ChainMetrics, bs blockstore.Blockstorer, opts ...SyncOpt) *CommonSync {
+	m *metrics.ChainMetrics, bs blockstore.Blockstorer, mc *mapprotocol.MapContext, opts ...SyncOpt) *CommonSync {
 	cs := &CommonSync{
 		Cfg:                *cfg,
 		Conn:               conn,
+		Map:                mc,
 		Log:                log,
 		Stop:               stop,
 		SysErr:             sysErr,
@@ -90,6 +102,7 @@ func NewCommonSync(conn Connection, cfg *Config, log log15.Logger, stop <-chan i
 		BlockConfirmations: cfg.BlockConfirmations,
 		MsgCh:              make(chan struct{}),
 		BlockStore:         bs,
+		DeadLetter:         &deadletter.EmptyDeadLetter{},
 		height:             1,
 	}
 	for _, op := range opts {
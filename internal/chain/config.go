@@ -43,16 +43,20 @@ var (
 	WaterLine             = "waterLine"
 	ChangeInterval        = "changeInterval"
 	Eth2Url               = "eth2Url"
+	AccessListOpt         = "accessList"
 )
 
 // Config encapsulates all necessary parameters in ethereum compatible forms
 type Config struct {
 	Name               string      // Human-readable chain name
+	Type               string      // Chain type
 	Id                 msg.ChainId // ChainID
 	Endpoint           string      // url for rpc endpoint
 	From               string      // address of key to use
 	KeystorePath       string      // Location of keyfiles
 	BlockstorePath     string
+	JournalPath        string
+	DeadLetterPath     string
 	FreshStart         bool // Disables loading from blockstore at start
 	McsContract        common.Address
 	GasLimit           *big.Int
@@ -75,17 +79,28 @@ type Config struct {
 	WaterLine          string
 	ChangeInterval     string
 	Eth2Endpoint       string
+	AccessList         bool // Whether to send tx with eip-2930 access list when it lowers the gas
+	Opts               map[string]string
+}
+
+// CheckConfig parses the config only for its errors, it is the config parser of the evm chain types
+func CheckConfig(chainCfg *core.ChainConfig) error {
+	_, err := ParseConfig(chainCfg)
+	return err
 }
 
 // ParseConfig uses a core.ChainConfig to construct a corresponding Config
 func ParseConfig(chainCfg *core.ChainConfig) (*Config, error) {
 	config := &Config{
 		Name:               chainCfg.Name,
+		Type:               chainCfg.Type,
 		Id:                 chainCfg.Id,
 		Endpoint:           chainCfg.Endpoint,
 		From:               chainCfg.From,
 		KeystorePath:       chainCfg.KeystorePath,
 		BlockstorePath:     chainCfg.BlockstorePath,
+		JournalPath:        chainCfg.JournalPath,
+		DeadLetterPath:     chainCfg.DeadLetterPath,
 		FreshStart:         chainCfg.FreshStart,
 		McsContract:        utils.ZeroAddress,
 		GasLimit:           big.NewInt(DefaultGasLimit),
@@ -153,6 +168,14 @@ func ParseConfig(chainCfg *core.ChainConfig) (*Config, error) {
 		}
 	}
 
+	if v, ok := chainCfg.Opts[AccessListOpt]; ok && v == "true" {
+		config.AccessList = true
+		delete(chainCfg.Opts, AccessListOpt)
+	} else if v, ok := chainCfg.Opts[AccessListOpt]; ok && v == "false" {
+		config.AccessList = false
+		delete(chainCfg.Opts, AccessListOpt)
+	}
+
 	if HTTP, ok := chainCfg.Opts[HttpOpt]; ok && HTTP == "true" {
 		config.Http = true
 		delete(chainCfg.Opts, HttpOpt)
@@ -249,6 +272,7 @@ func ParseConfig(chainCfg *core.ChainConfig) (*Config, error) {
 	}
 
 	config.HooksUrl = os.Getenv("hooks")
+	config.Opts = chainCfg.Opts
 
 	return config, nil
 }
@@ -0,0 +1,115 @@
+package chain
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/pkg/errors"
+)
+
+func SetupJournal(cfg *Config, kp *secp256k1.Keypair, role mapprotocol.Role) (*journal.Journal, error) {
+	return journal.NewJournal(cfg.JournalPath, cfg.Id, kp.Address(), role)
+}
+
+// newEntry builds the journal entry of the message, tx fields are filled in sendTx
+func newEntry(m msg.Message, method string) *journal.Entry {
+	e := &journal.Entry{
+		Source:      m.Source,
+		Destination: m.Destination,
+		Type:        m.Type,
+		Method:      method,
+	}
+	switch m.Type {
+	case msg.SwapTransfer, msg.SwapWithProof, msg.SwapWithMapProof:
+		if len(m.Payload) > 1 {
+			if orderId, ok := m.Payload[1].([]byte); ok {
+				e.OrderId = "0x" + common.Bytes2Hex(orderId)
+			}
+		}
+		if len(m.Payload) > 3 {
+			e.SrcHash = fmt.Sprintf("%v", m.Payload[3])
+		}
+	}
+	return e
+}
+
+// record writes the entry with the status to journal, failing to write journal does not block the tx
+func (w *Writer) record(e *journal.Entry, status journal.Status, err error) {
+	if e == nil {
+		return
+	}
+	e.Status = status
+	e.Time = time.Now()
+	e.Error = ""
+	if err != nil {
+		e.Error = err.Error()
+	}
+	if errr := w.journal.Append(e); errr != nil {
+		w.log.Warn("Failed to write journal", "tx", e.TxHash, "status", status, "err", errr)
+	}
+}
+
+// finish records the final state of a submitted tx according to the result of txStatus,
+// a tx whose receipt is not found yet is left as submitted
+func (w *Writer) finish(e *journal.Entry, err error) {
+	if err == nil {
+		w.record(e, journal.StatusSuccess, nil)
+	} else if errors.Is(err, errTxNotSuccess) {
+		w.record(e, journal.StatusFailed, err)
+	}
+}
+
+// reconcile checks the unfinished journal entries left by the last run against chain state
+func (w *Writer) reconcile() error {
+	entries, err := w.journal.Unfinished()
+	if err != nil {
+		return err
+	}
+	if len(entries) == 0 {
+		return nil
+	}
+	w.log.Info("Reconciling unfinished journal entries", "count", len(entries))
+
+	nonce, err := w.conn.Client().NonceAt(context.Background(), w.conn.Keypair().CommonAddress(), nil)
+	if err != nil {
+		return err
+	}
+	for _, e := range entries {
+		txHash := common.HexToHash(e.TxHash)
+		receipt, err := w.conn.Client().TransactionReceipt(context.Background(), txHash)
+		if err == nil {
+			if receipt.Status == types.ReceiptStatusSuccessful {
+				w.record(e, journal.StatusSuccess, nil)
+			} else {
+				w.record(e, journal.StatusFailed, fmt.Errorf("receipt status is (%d)", receipt.Status))
+			}
+			w.log.Info("Journal entry reconciled", "tx", e.TxHash, "srcHash", e.SrcHash, "status", e.Status)
+			continue
+		}
+		if strings.Index(err.Error(), "not found") == -1 {
+			w.log.Warn("Journal entry reconcile failed", "tx", e.TxHash, "err", err)
+			continue
+		}
+		if e.Nonce < nonce {
+			w.record(e, journal.StatusDropped, fmt.Errorf("nonce %d has been used, current nonce is %d", e.Nonce, nonce))
+			w.log.Info("Journal entry reconciled", "tx", e.TxHash, "srcHash", e.SrcHash, "status", e.Status)
+			continue
+		}
+		_, pending, err := w.conn.Client().TransactionByHash(context.Background(), txHash)
+		if err == nil && pending {
+			w.log.Warn("Journal entry still pending", "tx", e.TxHash, "srcHash", e.SrcHash, "nonce", e.Nonce)
+			continue
+		}
+		w.record(e, journal.StatusDropped, fmt.Errorf("tx not found and nonce %d not used", e.Nonce))
+		w.log.Info("Journal entry reconciled", "tx", e.TxHash, "srcHash", e.SrcHash, "status", e.Status)
+	}
+	return nil
+}
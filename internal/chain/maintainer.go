@@ -5,7 +5,6 @@ import (
 	"fmt"
 
 	"github.com/mapprotocol/compass/internal/constant"
-	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/pkg/util"
 	"github.com/pkg/errors"
 
@@ -45,8 +44,8 @@ func (m *Maintainer) sync() error {
 	m.Log.Info("Polling Blocks...", "block", currentBlock)
 
 	if m.Cfg.SyncToMap {
-		syncedHeight, err := mapprotocol.Get2MapHeight(m.Cfg.Id)
-		//syncedHeight, err := mapprotocol.Get2MapByLight()
+		syncedHeight, err := m.Map.Get2MapHeight(m.Cfg.Id)
+		//syncedHeight, err := m.Map.Get2MapByLight()
 		if err != nil {
 			m.Log.Error("Get synced Height failed", "err", err)
 			return err
@@ -62,7 +61,7 @@ func (m *Maintainer) sync() error {
 		}
 	} else if m.Cfg.Id == m.Cfg.MapChainID {
 		minHeight := big.NewInt(0)
-		for cId, height := range mapprotocol.SyncOtherMap {
+		for cId, height := range m.Map.SyncedHeights() {
 			if minHeight.Uint64() == 0 || minHeight.Cmp(height) == 1 {
 				m.Log.Info("map to other chain find min sync height ", "chainId", cId,
 					"syncedHeight", minHeight, "currentHeight", height)
@@ -64,11 +64,13 @@ func (w *Writer) callContractWithMsg(addr common.Address, m msg.Message) bool {
 				inputHash = m.Payload[3]
 			}
 			w.log.Info("Send transaction", "addr", addr, "srcHash", inputHash, "needNonce", needNonce, "nonce", w.conn.Opts().Nonce)
-			mcsTx, err := w.sendTx(&addr, nil, m.Payload[0].([]byte))
+			entry := newEntry(m, "")
+			mcsTx, err := w.sendTx(&addr, nil, m.Payload[0].([]byte), entry)
 			//err = w.call(&addr, m.Payload[0].([]byte), mapprotocol.Near, mapprotocol.MethodVerifyProofData)
 			if err == nil {
 				w.log.Info("Submitted cross tx execution", "src", m.Source, "dst", m.Destination, "srcHash", inputHash, "mcsTx", mcsTx.Hash())
 				err = w.txStatus(mcsTx.Hash())
+				w.finish(entry, err)
 				if err != nil {
 					w.log.Warn("TxHash Status is not successful, will retry", "err", err)
 				} else {
@@ -101,8 +103,8 @@ func (w *Writer) callContractWithMsg(addr common.Address, m msg.Message) bool {
 }
 
 func (w *Writer) mosAlarm(m msg.Message, tx interface{}, err error) {
-	util.Alarm(context.Background(), fmt.Sprintf("mos %s2%s failed, srcHash=%v err is %s", mapprotocol.OnlineChaId[m.Source],
-		mapprotocol.OnlineChaId[m.Destination], tx, err.Error()))
+	util.Alarm(context.Background(), fmt.Sprintf("mos %s2%s failed, srcHash=%v err is %s", w.mc.Name(m.Source),
+		w.mc.Name(m.Destination), tx, err.Error()))
 }
 
 func (w *Writer) call(toAddress *common.Address, input []byte, useAbi abi.ABI, method string) error {
@@ -185,6 +187,8 @@ func (w *Writer) checkOrderId(toAddress *common.Address, input []byte, useAbi ab
 	return exist, nil
 }
 
+var errTxNotSuccess = errors.New("status not success")
+
 func (w *Writer) txStatus(txHash common.Hash) error {
 	var count int64
 	time.Sleep(time.Second * 2)
@@ -230,6 +234,6 @@ func (w *Writer) txStatus(txHash common.Hash) error {
 			w.log.Info("Tx receipt status is success", "hash", txHash)
 			return nil
 		}
-		return fmt.Errorf("txHash(%s), status not success, current status is (%d)", txHash, receipt.Status)
+		return errors.Wrapf(errTxNotSuccess, "txHash(%s), current status is (%d)", txHash, receipt.Status)
 	}
 }
@@ -8,7 +8,7 @@ import (
 	"time"
 
 	"github.com/mapprotocol/compass/internal/constant"
-	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/pkg/util"
 )
 
@@ -61,7 +61,7 @@ func (m *Messenger) sync() error {
 				m.Metrics.LatestKnownBlock.Set(float64(latestBlock.Int64()))
 			}
 
-			left, right, err := mapprotocol.Get2MapVerifyRange(m.Cfg.Id)
+			left, right, err := m.Map.Get2MapVerifyRange(m.Cfg.Id)
 			if err != nil {
 				m.Log.Warn("Get2MapVerifyRange failed", "err", err)
 			}
@@ -84,6 +84,11 @@ func (m *Messenger) sync() error {
 			// messager
 			// Parse out events
 			count, err := m.mosHandler(m, currentBlock)
+			if errors.Is(err, proof.ErrNotReady) {
+				m.Log.Info("Block can not be proven yet, will retry", "block", currentBlock, "err", err)
+				time.Sleep(time.Minute)
+				continue
+			}
 			if err != nil {
 				m.Log.Error("Failed to get events for block", "block", currentBlock, "err", err)
 				time.Sleep(constant.BlockRetryInterval)
@@ -0,0 +1,146 @@
+package chain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ChainSafe/chainbridge-utils/crypto/secp256k1"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/deadletter"
+	"github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func SetupDeadLetter(cfg *Config, kp *secp256k1.Keypair, role mapprotocol.Role) (*deadletter.DeadLetter, error) {
+	return deadletter.NewDeadLetter(cfg.DeadLetterPath, cfg.Id, kp.Address(), role)
+}
+
+// ProofBuilder returns the proof builder registered for the chain type, it is created on first use
+func (c *CommonSync) ProofBuilder() (proof.ProofBuilder, error) {
+	if c.proofBuilder != nil {
+		return c.proofBuilder, nil
+	}
+	pb, err := proof.New(c.Cfg.Type, &proof.Config{
+		Id:         c.Cfg.Id,
+		MapChainID: c.Cfg.MapChainID,
+		Endpoint:   c.Cfg.Endpoint,
+		Mcs:        c.Cfg.McsContract,
+		Client:     c.Conn.Client(),
+		Opts:       c.Cfg.Opts,
+		Map:        c.Map,
+	})
+	if err != nil {
+		return nil, err
+	}
+	c.proofBuilder = pb
+	return pb, nil
+}
+
+// BuildProof builds the proof of the log and verifies it locally before it is routed. A proof failing the
+// verification won't be fixed by retrying, it is put to the dead-letter and proof.ErrInvalidProof is returned.
+func (c *CommonSync) BuildProof(pb proof.ProofBuilder, log *types.Log, receipts []*types.Receipt) (*proof.Proof, error) {
+	p, err := pb.Build(context.Background(), log, receipts)
+	if err != nil {
+		return nil, fmt.Errorf("unable to Parse Log: %w", err)
+	}
+	if err = p.Verify(); err != nil {
+		c.Log.Error("Receipt proof verification failed, put to dead-letter", "block", log.BlockNumber,
+			"txHash", log.TxHash, "logIdx", log.Index, "err", err)
+		c.putDeadLetter(log, receipts, p, err)
+		return nil, err
+	}
+	return p, nil
+}
+
+func (c *CommonSync) putDeadLetter(log *types.Log, receipts []*types.Receipt, p *proof.Proof, reason error) {
+	nodes := make([]string, 0, len(p.Nodes))
+	for _, n := range p.Nodes {
+		nodes = append(nodes, "0x"+common.Bytes2Hex(n))
+	}
+	diagnostics := map[string]string{
+		"chainType":    c.Cfg.Type,
+		"receiptsRoot": p.ReceiptsRoot.Hex(),
+		"keyIndex":     "0x" + common.Bytes2Hex(p.KeyIndex),
+		"nodes":        strings.Join(nodes, ","),
+		"receipt":      "0x" + common.Bytes2Hex(p.Receipt),
+		"receiptCount": fmt.Sprintf("%d", len(receipts)),
+		"payload":      "0x" + common.Bytes2Hex(p.Data),
+	}
+	if int(log.TxIndex) < len(receipts) {
+		if data, err := receipts[log.TxIndex].MarshalJSON(); err == nil {
+			diagnostics["txReceipt"] = string(data)
+		}
+	}
+	l := &deadletter.Letter{
+		Source:      c.Cfg.Id,
+		Destination: p.ToChainId,
+		BlockNumber: log.BlockNumber,
+		TxHash:      log.TxHash.Hex(),
+		LogIndex:    log.Index,
+		Method:      p.Method,
+		Reason:      reason.Error(),
+		Diagnostics: diagnostics,
+	}
+	if len(log.Data) >= 32 {
+		l.OrderId = "0x" + common.Bytes2Hex(log.Data[:32])
+	}
+	if err := c.DeadLetter.Put(l); err != nil {
+		c.Log.Error("Failed to write dead-letter", "txHash", log.TxHash, "err", err)
+	}
+}
+
+// MosOfProof is the Mos of the chain types whose events are routed to map with the proof of their proof builder
+func MosOfProof(m *Messenger, latestBlock *big.Int) (int, error) {
+	if !m.Cfg.SyncToMap {
+		return 0, nil
+	}
+	m.Log.Debug("Querying block for events", "block", latestBlock)
+	query := m.BuildQuery(m.Cfg.McsContract, m.Cfg.Events, latestBlock, latestBlock)
+	// querying for logs
+	logs, err := m.Conn.Client().FilterLogs(context.Background(), query)
+	if err != nil {
+		return 0, fmt.Errorf("unable to Filter Logs: %w", err)
+	}
+
+	m.Log.Debug("event", "latestBlock ", latestBlock, " logs ", len(logs))
+	if len(logs) == 0 {
+		return 0, nil
+	}
+	pb, err := m.ProofBuilder()
+	if err != nil {
+		return 0, err
+	}
+	receipts, err := pb.Receipts(context.Background(), latestBlock)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, log := range logs {
+		orderId := log.Data[:32]
+		p, err := m.BuildProof(pb, &log, receipts)
+		if errors.Is(err, proof.ErrInvalidProof) {
+			m.Log.Info("Event skipped", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "err", err)
+			continue
+		} else if err != nil {
+			return 0, err
+		}
+
+		msgPayload := []interface{}{p.Data, orderId, latestBlock.Uint64(), log.TxHash}
+		message := msg.NewSwapWithProof(m.Cfg.Id, m.Cfg.MapChainID, msgPayload, m.MsgCh)
+
+		m.Log.Info("Event found", "BlockNumber", log.BlockNumber, "txHash", log.TxHash, "logIdx", log.Index,
+			"orderId", common.Bytes2Hex(orderId))
+		err = m.Router.Send(message)
+		if err != nil {
+			m.Log.Error("Subscription error: failed to route message", "err", err)
+		}
+		count++
+	}
+
+	return count, nil
+}
@@ -7,34 +7,53 @@ import (
 
 	"github.com/mapprotocol/compass/internal/constant"
 
+	metrics "github.com/ChainSafe/chainbridge-utils/metrics/types"
 	"github.com/ChainSafe/log15"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/journal"
+	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 )
 
 type Writer struct {
-	cfg    Config
-	conn   Connection
-	log    log15.Logger
-	stop   <-chan int
-	sysErr chan<- error // Reports fatal error to core
+	cfg       Config
+	conn      Connection
+	mc        *mapprotocol.MapContext
+	log       log15.Logger
+	stop      <-chan int
+	sysErr    chan<- error // Reports fatal error to core
+	journal   journal.Journaler
+	alMetrics *AccessListMetrics
 }
 
 // NewWriter creates and returns Writer
-func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error) *Writer {
+func NewWriter(conn Connection, cfg *Config, log log15.Logger, stop <-chan int, sysErr chan<- error,
+	m *metrics.ChainMetrics, jn journal.Journaler, mc *mapprotocol.MapContext) *Writer {
+	var alMetrics *AccessListMetrics
+	if m != nil && cfg.AccessList {
+		alMetrics = NewAccessListMetrics(cfg.Name)
+	}
 	return &Writer{
-		cfg:    *cfg,
-		conn:   conn,
-		log:    log,
-		stop:   stop,
-		sysErr: sysErr,
+		cfg:       *cfg,
+		conn:      conn,
+		mc:        mc,
+		log:       log,
+		stop:      stop,
+		sysErr:    sysErr,
+		journal:   jn,
+		alMetrics: alMetrics,
 	}
 }
 
-func (w *Writer) start() error {
+// Start reconciles the unfinished journal entries of the last run, it is not a critical operation
+func (w *Writer) Start() error {
 	w.log.Debug("Starting Writer...")
+	err := w.reconcile()
+	if err != nil {
+		w.log.Warn("Failed to reconcile journal", "err", err)
+	}
 	return nil
 }
 
@@ -61,8 +80,8 @@ func (w *Writer) ResolveMessage(m msg.Message) bool {
 	}
 }
 
-// sendTx send tx to an address with value and input data
-func (w *Writer) sendTx(toAddress *common.Address, value *big.Int, input []byte) (*types.Transaction, error) {
+// sendTx send tx to an address with value and input data, the tx is recorded to journal before and after it is sent
+func (w *Writer) sendTx(toAddress *common.Address, value *big.Int, input []byte, e *journal.Entry) (*types.Transaction, error) {
 	gasPrice := w.conn.Opts().GasPrice
 	nonce := w.conn.Opts().Nonce
 	from := w.conn.Keypair().CommonAddress()
@@ -80,15 +99,33 @@ func (w *Writer) sendTx(toAddress *common.Address, value *big.Int, input []byte)
 		return nil, err
 	}
 
+	var accessList types.AccessList
+	if w.cfg.AccessList {
+		accessList, gasLimit = w.accessList(msg, gasLimit)
+	}
+
 	if w.cfg.LimitMultiplier > 1 {
 		gasLimit = uint64(float64(gasLimit) * w.cfg.LimitMultiplier)
 	}
 	w.log.Info("SendTx gasPrice", "gasPrice", gasPrice,
 		"gasTipCap", w.conn.Opts().GasTipCap, "gasFeeCap", w.conn.Opts().GasFeeCap, "limitMultiplier", w.cfg.LimitMultiplier)
+	chainID := big.NewInt(int64(w.cfg.Id))
 	// td interface
 	var td types.TxData
 	// EIP-1559
-	if gasPrice != nil {
+	if gasPrice != nil && accessList != nil {
+		// EIP-2930 branch
+		td = &types.AccessListTx{
+			ChainID:    chainID,
+			Nonce:      nonce.Uint64(),
+			Value:      value,
+			To:         toAddress,
+			Gas:        gasLimit,
+			GasPrice:   gasPrice,
+			Data:       input,
+			AccessList: accessList,
+		}
+	} else if gasPrice != nil {
 		// legacy branch
 		td = &types.LegacyTx{
 			Nonce:    nonce.Uint64(),
@@ -101,18 +138,18 @@ func (w *Writer) sendTx(toAddress *common.Address, value *big.Int, input []byte)
 	} else {
 		// london branch
 		td = &types.DynamicFeeTx{
-			Nonce:     nonce.Uint64(),
-			Value:     value,
-			To:        toAddress,
-			Gas:       gasLimit,
-			GasTipCap: w.conn.Opts().GasTipCap,
-			GasFeeCap: w.conn.Opts().GasFeeCap,
-			Data:      input,
+			Nonce:      nonce.Uint64(),
+			Value:      value,
+			To:         toAddress,
+			Gas:        gasLimit,
+			GasTipCap:  w.conn.Opts().GasTipCap,
+			GasFeeCap:  w.conn.Opts().GasFeeCap,
+			Data:       input,
+			AccessList: accessList,
 		}
 	}
 
 	tx := types.NewTx(td)
-	chainID := big.NewInt(int64(w.cfg.Id))
 	privateKey := w.conn.Keypair().PrivateKey()
 
 	signedTx, err := types.SignTx(tx, types.NewLondonSigner(chainID), privateKey)
@@ -121,11 +158,28 @@ func (w *Writer) sendTx(toAddress *common.Address, value *big.Int, input []byte)
 		return nil, err
 	}
 
+	if e != nil {
+		e.Id = signedTx.Hash().Hex()
+		e.TxHash = signedTx.Hash().Hex()
+		e.To = toAddress.Hex()
+		e.Nonce = signedTx.Nonce()
+		e.GasLimit = signedTx.Gas()
+		if gasPrice != nil {
+			e.GasPrice = gasPrice.String()
+		} else {
+			e.GasTipCap = signedTx.GasTipCap().String()
+			e.GasFeeCap = signedTx.GasFeeCap().String()
+		}
+		w.record(e, journal.StatusPending, nil)
+	}
+
 	err = w.conn.Client().SendTransaction(context.Background(), signedTx)
 	if err != nil {
 		w.log.Error("SendTransaction failed", "error:", err.Error())
+		w.record(e, journal.StatusFailed, err)
 		return nil, err
 	}
+	w.record(e, journal.StatusSubmitted, nil)
 	return signedTx, nil
 }
 
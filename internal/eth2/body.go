@@ -0,0 +1,622 @@
+package eth2
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// forks of the beacon block body, as the version of the beacon api
+const (
+	VersionBellatrix = "bellatrix"
+	VersionCapella   = "capella"
+	VersionDeneb     = "deneb"
+	VersionElectra   = "electra"
+	VersionFulu      = "fulu"
+)
+
+// limits of the lists in the beacon block body, mainnet preset
+const (
+	maxProposerSlashings        = 16
+	maxAttesterSlashings        = 2
+	maxAttestations             = 128
+	maxDeposits                 = 16
+	maxVoluntaryExits           = 16
+	maxBlsToExecutionChanges    = 16
+	maxBlobCommitmentsPerBlock  = 4096
+	maxValidatorsPerCommittee   = 2048
+	maxCommitteesPerSlot        = 64
+	maxAttesterSlashingsElectra = 1
+	maxAttestationsElectra      = 8
+	maxDepositRequests          = 8192
+	maxWithdrawalRequests       = 16
+	maxConsolidationRequests    = 2
+	maxBytesPerTransaction      = 1 << 30
+	maxTransactionsPerPayload   = 1 << 20
+	maxWithdrawalsPerPayload    = 16
+	maxExtraDataBytes           = 32
+	depositContractTreeDepth    = 32
+	executionPayloadIndex       = 9 // index of execution_payload in the fields of the body
+	bodyFieldsLimit             = 16
+	blsSignatureLength          = 96
+	blsPubkeyLength             = 48
+	syncCommitteeBitsLength     = 64
+	logsBloomLength             = 256
+	executionAddressLength      = 20
+	depositProofLength          = depositContractTreeDepth + 1
+	blobKzgCommitmentLength     = blsPubkeyLength
+)
+
+// ExecutionProof is the proof of the execution payload in the beacon block body
+type ExecutionProof struct {
+	BodyRoot         common.Hash
+	Branch           [][32]byte // from the bottom up
+	TransactionsRoot common.Hash
+	WithdrawalsRoot  common.Hash // zero before capella
+}
+
+// Generate computes the hash tree root of the body and the branch of its execution payload, with the roots of
+// the transactions and withdrawals of the payload, which the light client update has in place of the lists.
+func Generate(version string, body *Body) (*ExecutionProof, error) {
+	fields, txRoot, wdRoot, err := bodyFields(version, body)
+	if err != nil {
+		return nil, err
+	}
+	root, err := merkleize(fields, bodyFieldsLimit)
+	if err != nil {
+		return nil, err
+	}
+	branch, err := merkleBranch(fields, bodyFieldsLimit, executionPayloadIndex)
+	if err != nil {
+		return nil, err
+	}
+	return &ExecutionProof{
+		BodyRoot:         root,
+		Branch:           branch,
+		TransactionsRoot: txRoot,
+		WithdrawalsRoot:  wdRoot,
+	}, nil
+}
+
+func bodyFields(version string, body *Body) ([][32]byte, common.Hash, common.Hash, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return nil, common.Hash{}, common.Hash{}, err
+	}
+	// electra moves the committee index of an attestation to its bits, which span the committees of the slot
+	attesterSlashings, attestations, attesters := uint64(maxAttesterSlashings), uint64(maxAttestations), uint64(maxValidatorsPerCommittee)
+	if f >= forkElectra {
+		attesterSlashings, attestations = maxAttesterSlashingsElectra, maxAttestationsElectra
+		attesters = maxValidatorsPerCommittee * maxCommitteesPerSlot
+	}
+
+	var txRoot, wdRoot [32]byte
+	fs := []func() ([32]byte, error){
+		func() ([32]byte, error) { return hexVectorRoot(body.RandaoReveal, blsSignatureLength) },
+		func() ([32]byte, error) { return eth1DataRoot(&body.Eth1Data) },
+		func() ([32]byte, error) { return parseBytes32(body.Graffiti) },
+		func() ([32]byte, error) {
+			return listRoot(len(body.ProposerSlashings), maxProposerSlashings, func(i int) ([32]byte, error) {
+				return proposerSlashingRoot(&body.ProposerSlashings[i])
+			})
+		},
+		func() ([32]byte, error) {
+			return listRoot(len(body.AttesterSlashings), attesterSlashings, func(i int) ([32]byte, error) {
+				return attesterSlashingRoot(&body.AttesterSlashings[i], attesters)
+			})
+		},
+		func() ([32]byte, error) {
+			return listRoot(len(body.Attestations), attestations, func(i int) ([32]byte, error) {
+				return attestationRoot(f, &body.Attestations[i], attesters)
+			})
+		},
+		func() ([32]byte, error) {
+			return listRoot(len(body.Deposits), maxDeposits, func(i int) ([32]byte, error) {
+				return depositRoot(&body.Deposits[i])
+			})
+		},
+		func() ([32]byte, error) {
+			return listRoot(len(body.VoluntaryExits), maxVoluntaryExits, func(i int) ([32]byte, error) {
+				return voluntaryExitRoot(&body.VoluntaryExits[i])
+			})
+		},
+		func() ([32]byte, error) { return syncAggregateRoot(&body.SyncAggregate) },
+		func() (r [32]byte, err error) {
+			r, txRoot, wdRoot, err = payloadRoot(f, &body.ExecutionPayload)
+			return r, err
+		},
+	}
+	if f >= forkCapella {
+		fs = append(fs, func() ([32]byte, error) {
+			return listRoot(len(body.BlsToExecutionChanges), maxBlsToExecutionChanges, func(i int) ([32]byte, error) {
+				return blsToExecutionChangeRoot(&body.BlsToExecutionChanges[i])
+			})
+		})
+	}
+	if f >= forkDeneb {
+		fs = append(fs, func() ([32]byte, error) {
+			return listRoot(len(body.BlobKzgCommitments), maxBlobCommitmentsPerBlock, func(i int) ([32]byte, error) {
+				return hexVectorRoot(body.BlobKzgCommitments[i], blobKzgCommitmentLength)
+			})
+		})
+	}
+	if f >= forkElectra {
+		fs = append(fs, func() ([32]byte, error) { return executionRequestsRoot(&body.ExecutionRequests) })
+	}
+
+	fields := make([][32]byte, 0, len(fs))
+	for _, f := range fs {
+		r, err := f()
+		if err != nil {
+			return nil, common.Hash{}, common.Hash{}, fmt.Errorf("beacon block body field %d: %w", len(fields), err)
+		}
+		fields = append(fields, r)
+	}
+	return fields, txRoot, wdRoot, nil
+}
+
+// listRoot returns the root of a list of n composite values, with the root of each from f
+func listRoot(n int, limit uint64, f func(i int) ([32]byte, error)) ([32]byte, error) {
+	roots := make([][32]byte, 0, n)
+	for i := 0; i < n; i++ {
+		r, err := f(i)
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("item %d: %w", i, err)
+		}
+		roots = append(roots, r)
+	}
+	return list(roots, limit)
+}
+
+// payloadRoot returns the root of the execution payload, with the roots of its transactions and withdrawals
+func payloadRoot(f fork, p *Execution) (root, txRoot, wdRoot [32]byte, err error) {
+	txRoot, err = listRoot(len(p.Transactions), maxTransactionsPerPayload, func(i int) ([32]byte, error) {
+		b, err := parseHex(p.Transactions[i])
+		if err != nil {
+			return [32]byte{}, err
+		}
+		return byteListRoot(b, maxBytesPerTransaction)
+	})
+	if err != nil {
+		return root, txRoot, wdRoot, fmt.Errorf("execution payload transactions: %w", err)
+	}
+	if f >= forkCapella {
+		wdRoot, err = listRoot(len(p.Withdrawals), maxWithdrawalsPerPayload, func(i int) ([32]byte, error) {
+			return withdrawalRoot(&p.Withdrawals[i])
+		})
+		if err != nil {
+			return root, txRoot, wdRoot, fmt.Errorf("execution payload withdrawals: %w", err)
+		}
+	}
+	root, err = executionRoot(f, p, txRoot, wdRoot)
+	return root, txRoot, wdRoot, err
+}
+
+// ExecutionHeaderRoot returns the root of the execution payload header of the light client headers of the
+// version, which has the roots of the transactions and withdrawals in place of the lists of the payload
+func ExecutionHeaderRoot(version string, h *Execution) (common.Hash, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	txRoot, err := parseBytes32(h.TransactionsRoot)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("execution transactions root: %w", err)
+	}
+	var wdRoot [32]byte
+	if f >= forkCapella {
+		if wdRoot, err = parseBytes32(h.WithdrawalsRoot); err != nil {
+			return common.Hash{}, fmt.Errorf("execution withdrawals root: %w", err)
+		}
+	}
+	return executionRoot(f, h, txRoot, wdRoot)
+}
+
+// executionRoot is the root of the execution payload, or of its header, with the roots of the lists
+func executionRoot(f fork, p *Execution, txRoot, wdRoot [32]byte) ([32]byte, error) {
+	u64 := func(s string) ([32]byte, error) {
+		v, err := parseUint64(s)
+		return uint64Root(v), err
+	}
+	fields := make([][32]byte, 0, 17)
+	for _, field := range []func() ([32]byte, error){
+		func() ([32]byte, error) { return parseBytes32(p.ParentHash) },
+		func() ([32]byte, error) { return hexVectorRoot(p.FeeRecipient, executionAddressLength) },
+		func() ([32]byte, error) { return parseBytes32(p.StateRoot) },
+		func() ([32]byte, error) { return parseBytes32(p.ReceiptsRoot) },
+		func() ([32]byte, error) { return hexVectorRoot(p.LogsBloom, logsBloomLength) },
+		func() ([32]byte, error) { return parseBytes32(p.PrevRandao) },
+		func() ([32]byte, error) { return u64(p.BlockNumber) },
+		func() ([32]byte, error) { return u64(p.GasLimit) },
+		func() ([32]byte, error) { return u64(p.GasUsed) },
+		func() ([32]byte, error) { return u64(p.Timestamp) },
+		func() ([32]byte, error) {
+			b, err := parseHex(p.ExtraData)
+			if err != nil {
+				return [32]byte{}, err
+			}
+			return byteListRoot(b, maxExtraDataBytes)
+		},
+		func() ([32]byte, error) {
+			v, ok := new(big.Int).SetString(p.BaseFeePerGas, 10)
+			if !ok {
+				return [32]byte{}, fmt.Errorf("base fee %q is not a number", p.BaseFeePerGas)
+			}
+			return uint256Root(v)
+		},
+		func() ([32]byte, error) { return parseBytes32(p.BlockHash) },
+	} {
+		r, err := field()
+		if err != nil {
+			return [32]byte{}, fmt.Errorf("execution payload field %d: %w", len(fields), err)
+		}
+		fields = append(fields, r)
+	}
+	fields = append(fields, txRoot)
+	if f >= forkCapella {
+		fields = append(fields, wdRoot)
+	}
+	if f >= forkDeneb {
+		for _, s := range []string{p.BlobGasUsed, p.ExcessBlobGas} {
+			r, err := u64(s)
+			if err != nil {
+				return [32]byte{}, fmt.Errorf("execution payload blob gas: %w", err)
+			}
+			fields = append(fields, r)
+		}
+	}
+	return container(fields...)
+}
+
+func hexVectorRoot(s string, size int) ([32]byte, error) {
+	b, err := parseHex(s)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return vectorRoot(b, size)
+}
+
+func eth1DataRoot(d *Eth1Data) ([32]byte, error) {
+	depositRoot, err := parseBytes32(d.DepositRoot)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	count, err := parseUint64(d.DepositCount)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	blockHash, err := parseBytes32(d.BlockHash)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(depositRoot, uint64Root(count), blockHash)
+}
+
+func beaconBlockHeaderRoot(m *Message) ([32]byte, error) {
+	slot, err := parseUint64(m.Slot)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	proposer, err := parseUint64(m.ProposerIndex)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	roots := make([][32]byte, 0, 3)
+	for _, s := range []string{m.ParentRoot, m.StateRoot, m.BodyRoot} {
+		r, err := parseBytes32(s)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		roots = append(roots, r)
+	}
+	return container(uint64Root(slot), uint64Root(proposer), roots[0], roots[1], roots[2])
+}
+
+func signedRoot(message [32]byte, signature string) ([32]byte, error) {
+	sig, err := hexVectorRoot(signature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(message, sig)
+}
+
+func proposerSlashingRoot(s *ProposerSlashing) ([32]byte, error) {
+	roots := make([][32]byte, 0, 2)
+	for _, h := range []*Header{&s.SignedHeader1, &s.SignedHeader2} {
+		m, err := beaconBlockHeaderRoot(&h.Message)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		r, err := signedRoot(m, h.Signature)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		roots = append(roots, r)
+	}
+	return container(roots...)
+}
+
+func checkpointRoot(epoch, root string) ([32]byte, error) {
+	e, err := parseUint64(epoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	r, err := parseBytes32(root)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(uint64Root(e), r)
+}
+
+func attestationDataRoot(d *AttestationData) ([32]byte, error) {
+	slot, err := parseUint64(d.Slot)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	index, err := parseUint64(d.Index)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	blockRoot, err := parseBytes32(d.BeaconBlockRoot)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	source, err := checkpointRoot(d.Source.Epoch, d.Source.Root)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	target, err := checkpointRoot(d.Target.Epoch, d.Target.Root)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(uint64Root(slot), uint64Root(index), blockRoot, source, target)
+}
+
+func indexedAttestationRoot(a *IndexedAttestation, attesters uint64) ([32]byte, error) {
+	indices := make([]uint64, 0, len(a.AttestingIndices))
+	for _, s := range a.AttestingIndices {
+		v, err := parseUint64(s)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		indices = append(indices, v)
+	}
+	indicesRoot, err := uint64ListRoot(indices, attesters)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	data, err := attestationDataRoot(&a.Data)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	sig, err := hexVectorRoot(a.Signature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(indicesRoot, data, sig)
+}
+
+func attesterSlashingRoot(s *AttesterSlashing, attesters uint64) ([32]byte, error) {
+	a1, err := indexedAttestationRoot(&s.Attestation1, attesters)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	a2, err := indexedAttestationRoot(&s.Attestation2, attesters)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(a1, a2)
+}
+
+func attestationRoot(f fork, a *Attestations, attesters uint64) ([32]byte, error) {
+	bits, err := parseHex(a.AggregationBits)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	bitsRoot, err := bitlistRoot(bits, attesters)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	data, err := attestationDataRoot(&a.Data)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	sig, err := hexVectorRoot(a.Signature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if f < forkElectra {
+		return container(bitsRoot, data, sig)
+	}
+	committees, err := hexVectorRoot(a.CommitteeBits, maxCommitteesPerSlot/8)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(bitsRoot, data, sig, committees)
+}
+
+func depositRoot(d *Deposit) ([32]byte, error) {
+	if len(d.Proof) != depositProofLength {
+		return [32]byte{}, fmt.Errorf("deposit proof has %d nodes, want %d", len(d.Proof), depositProofLength)
+	}
+	proof := make([][32]byte, 0, len(d.Proof))
+	for _, s := range d.Proof {
+		r, err := parseBytes32(s)
+		if err != nil {
+			return [32]byte{}, err
+		}
+		proof = append(proof, r)
+	}
+	proofRoot, err := merkleize(proof, depositProofLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pubkey, err := hexVectorRoot(d.Data.Pubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	credentials, err := parseBytes32(d.Data.WithdrawalCredentials)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	amount, err := parseUint64(d.Data.Amount)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	sig, err := hexVectorRoot(d.Data.Signature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	data, err := container(pubkey, credentials, uint64Root(amount), sig)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(proofRoot, data)
+}
+
+func voluntaryExitRoot(e *SignedVoluntaryExit) ([32]byte, error) {
+	epoch, err := parseUint64(e.Message.Epoch)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	validator, err := parseUint64(e.Message.ValidatorIndex)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	m, err := container(uint64Root(epoch), uint64Root(validator))
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return signedRoot(m, e.Signature)
+}
+
+func syncAggregateRoot(s *SyncAggregate) ([32]byte, error) {
+	bits, err := hexVectorRoot(s.SyncCommitteeBits, syncCommitteeBitsLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	sig, err := hexVectorRoot(s.SyncCommitteeSignature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(bits, sig)
+}
+
+func blsToExecutionChangeRoot(c *SignedBLSToExecutionChange) ([32]byte, error) {
+	validator, err := parseUint64(c.Message.ValidatorIndex)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pubkey, err := hexVectorRoot(c.Message.FromBlsPubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	address, err := hexVectorRoot(c.Message.ToExecutionAddress, executionAddressLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	m, err := container(uint64Root(validator), pubkey, address)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return signedRoot(m, c.Signature)
+}
+
+func withdrawalRoot(w *Withdrawal) ([32]byte, error) {
+	index, err := parseUint64(w.Index)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	validator, err := parseUint64(w.ValidatorIndex)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	address, err := hexVectorRoot(w.Address, executionAddressLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	amount, err := parseUint64(w.Amount)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(uint64Root(index), uint64Root(validator), address, uint64Root(amount))
+}
+
+func executionRequestsRoot(r *ExecutionRequests) ([32]byte, error) {
+	deposits, err := listRoot(len(r.Deposits), maxDepositRequests, func(i int) ([32]byte, error) {
+		return depositRequestRoot(&r.Deposits[i])
+	})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("deposit requests: %w", err)
+	}
+	withdrawals, err := listRoot(len(r.Withdrawals), maxWithdrawalRequests, func(i int) ([32]byte, error) {
+		return withdrawalRequestRoot(&r.Withdrawals[i])
+	})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("withdrawal requests: %w", err)
+	}
+	consolidations, err := listRoot(len(r.Consolidations), maxConsolidationRequests, func(i int) ([32]byte, error) {
+		return consolidationRequestRoot(&r.Consolidations[i])
+	})
+	if err != nil {
+		return [32]byte{}, fmt.Errorf("consolidation requests: %w", err)
+	}
+	return container(deposits, withdrawals, consolidations)
+}
+
+func depositRequestRoot(d *DepositRequest) ([32]byte, error) {
+	pubkey, err := hexVectorRoot(d.Pubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	credentials, err := parseBytes32(d.WithdrawalCredentials)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	amount, err := parseUint64(d.Amount)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	sig, err := hexVectorRoot(d.Signature, blsSignatureLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	index, err := parseUint64(d.Index)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(pubkey, credentials, uint64Root(amount), sig, uint64Root(index))
+}
+
+func withdrawalRequestRoot(w *WithdrawalRequest) ([32]byte, error) {
+	address, err := hexVectorRoot(w.SourceAddress, executionAddressLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	pubkey, err := hexVectorRoot(w.ValidatorPubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	amount, err := parseUint64(w.Amount)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(address, pubkey, uint64Root(amount))
+}
+
+func consolidationRequestRoot(c *ConsolidationRequest) ([32]byte, error) {
+	address, err := hexVectorRoot(c.SourceAddress, executionAddressLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	source, err := hexVectorRoot(c.SourcePubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	target, err := hexVectorRoot(c.TargetPubkey, blsPubkeyLength)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return container(address, source, target)
+}
@@ -0,0 +1,216 @@
+package eth2
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func loadBlock(t *testing.T) *BlocksResp {
+	return loadBlockFile(t, "block.json")
+}
+
+func loadBlockFile(t *testing.T, name string) *BlocksResp {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	block := &BlocksResp{}
+	if err = json.Unmarshal(data, block); err != nil {
+		t.Fatal(err)
+	}
+	return block
+}
+
+// verifyBranch is is_valid_merkle_branch of the consensus specs
+func verifyBranch(leaf [32]byte, branch [][32]byte, index int, root [32]byte) bool {
+	for _, sibling := range branch {
+		if index%2 == 1 {
+			leaf = hashPair(sibling, leaf)
+		} else {
+			leaf = hashPair(leaf, sibling)
+		}
+		index /= 2
+	}
+	return leaf == root
+}
+
+func TestZeroHashes(t *testing.T) {
+	if want := common.HexToHash("0xf5a5fd42d16a20302798ef6ed309979b43003d2320d9f0e8ea9831a92759fb4b"); common.Hash(zeroHashes[1]) != want {
+		t.Fatalf("zero hash 1 is %x, want %s", zeroHashes[1], want)
+	}
+	if want := common.HexToHash("0xdb56114e00fdd4c1f85c892bf35ac9a89289aaecb1ebd0a96cde606a748b5d71"); common.Hash(zeroHashes[2]) != want {
+		t.Fatalf("zero hash 2 is %x, want %s", zeroHashes[2], want)
+	}
+}
+
+func TestEmptyPayloadLists(t *testing.T) {
+	p := loadBlock(t).Data.Message.Body.ExecutionPayload
+	p.Transactions = nil
+	p.Withdrawals = nil
+	_, txRoot, wdRoot, err := payloadRoot(forkCapella, &p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the roots of the payload of a block without transactions and withdrawals
+	if want := common.HexToHash("0x7ffe241ea60187fdb0187bfa22de35d1f9bed7ab061d9401fd47e34a54fbede1"); common.Hash(txRoot) != want {
+		t.Fatalf("transactions root is %x, want %s", txRoot, want)
+	}
+	if want := common.HexToHash("0x792930bbd5baac43bcc798ee49aa8185ef76bb3b44ba62b91d86ae569e4bb535"); common.Hash(wdRoot) != want {
+		t.Fatalf("withdrawals root is %x, want %s", wdRoot, want)
+	}
+}
+
+// the block of testdata has every kind of operation of the body, the same body is hashed as each fork, which
+// ignores the fields it does not have
+func TestGenerate(t *testing.T) {
+	block := loadBlock(t)
+	body := &block.Data.Message.Body
+	cases := []struct {
+		version  string
+		bodyRoot string
+		txRoot   string
+		wdRoot   string
+	}{
+		{VersionBellatrix, "0x4d259069ef2b9f949cb9309724325d444d0b28589290fa6b9142b64b616bca4b", "0xb23f0ad778980aba33bcf5e1456b39eb9bd473480a24ba690593d0c9b10750d5", ""},
+		{VersionCapella, "0x9e6cd1925748560ad1e7e2b1157bca936c1b707374e71fee1e22c488edb0f65b", "0xb23f0ad778980aba33bcf5e1456b39eb9bd473480a24ba690593d0c9b10750d5", "0x517ed6fd3ca828dfbf7b7e195a9c102b986a75720aa51c6b2e605c2cff7caf87"},
+		{VersionDeneb, "0xff030cea4e5dbcefb668d6e11aa9bd23649a3e69992039cfe4b2069c4340c07c", "0xb23f0ad778980aba33bcf5e1456b39eb9bd473480a24ba690593d0c9b10750d5", "0x517ed6fd3ca828dfbf7b7e195a9c102b986a75720aa51c6b2e605c2cff7caf87"},
+	}
+	for _, c := range cases {
+		ep, err := Generate(c.version, body)
+		if err != nil {
+			t.Fatalf("%s: %v", c.version, err)
+		}
+		if ep.BodyRoot != common.HexToHash(c.bodyRoot) {
+			t.Fatalf("%s: body root is %s, want %s", c.version, ep.BodyRoot.Hex(), c.bodyRoot)
+		}
+		if ep.TransactionsRoot != common.HexToHash(c.txRoot) {
+			t.Fatalf("%s: transactions root is %s, want %s", c.version, ep.TransactionsRoot.Hex(), c.txRoot)
+		}
+		if ep.WithdrawalsRoot != common.HexToHash(c.wdRoot) {
+			t.Fatalf("%s: withdrawals root is %s, want %s", c.version, ep.WithdrawalsRoot.Hex(), c.wdRoot)
+		}
+		if len(ep.Branch) != 4 {
+			t.Fatalf("%s: branch has %d nodes", c.version, len(ep.Branch))
+		}
+		payload, _, _, err := payloadRoot(forks[c.version], &body.ExecutionPayload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verifyBranch(payload, ep.Branch, executionPayloadIndex, ep.BodyRoot) {
+			t.Fatalf("%s: execution branch does not verify against the body root", c.version)
+		}
+	}
+}
+
+// the electra block of testdata is the one of block.json with the committee bits of the attestations and the
+// execution requests
+func TestGenerateElectra(t *testing.T) {
+	body := &loadBlockFile(t, "block_electra.json").Data.Message.Body
+	ep, err := Generate(VersionElectra, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := common.HexToHash("0xed4eb244500d06d38153eea0e63180a854066f60947bb9b21f688f3fdf16337c"); ep.BodyRoot != want {
+		t.Fatalf("body root is %s, want %s", ep.BodyRoot.Hex(), want.Hex())
+	}
+	payload, _, _, err := payloadRoot(forkElectra, &body.ExecutionPayload)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !verifyBranch(payload, ep.Branch, executionPayloadIndex, ep.BodyRoot) {
+		t.Fatal("execution branch does not verify against the body root")
+	}
+
+	attestations := body.Attestations
+	for len(body.Attestations) <= maxAttestationsElectra {
+		body.Attestations = append(body.Attestations, attestations[0])
+	}
+	if _, err = Generate(VersionElectra, body); err == nil {
+		t.Fatal("expected error over the attestations of electra")
+	}
+	body.Attestations = attestations
+	body.Attestations[0].CommitteeBits = ""
+	if _, err = Generate(VersionElectra, body); err == nil {
+		t.Fatal("expected error of an attestation without committee bits")
+	}
+}
+
+func TestGenerateVersion(t *testing.T) {
+	if _, err := Generate("phase0", &loadBlock(t).Data.Message.Body); err == nil {
+		t.Fatal("expected error")
+	}
+}
+
+func TestBitlistRoot(t *testing.T) {
+	// a bitlist of 8 set bits, the delimiter is in a byte of its own
+	full, err := bitlistRoot([]byte{0xff, 0x01}, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var chunk [32]byte
+	chunk[0] = 0xff
+	root, _ := merkleize([][32]byte{chunk}, 8)
+	if full != mixInLength(root, 8) {
+		t.Fatalf("root of the full byte is %x", full)
+	}
+	// the empty bitlist
+	empty, err := bitlistRoot([]byte{0x01}, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if empty != mixInLength(zeroHashes[3], 0) {
+		t.Fatalf("root of the empty bitlist is %x", empty)
+	}
+	for _, b := range [][]byte{nil, {0xff, 0x00}} {
+		if _, err = bitlistRoot(b, 2048); err == nil {
+			t.Fatalf("%x: expected error", b)
+		}
+	}
+	if _, err = bitlistRoot([]byte{0xff, 0x02}, 8); err == nil {
+		t.Fatal("expected error over the limit")
+	}
+}
+
+func TestUint256Root(t *testing.T) {
+	r, err := uint256Root(big.NewInt(0x0102))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r[0] != 0x02 || r[1] != 0x01 || r[2] != 0 {
+		t.Fatalf("root of 0x0102 is %x, want little endian", r)
+	}
+	if _, err = uint256Root(new(big.Int).Lsh(big.NewInt(1), 256)); err == nil {
+		t.Fatal("expected error over 256 bits")
+	}
+	if _, err = uint256Root(big.NewInt(-1)); err == nil {
+		t.Fatal("expected error of negative")
+	}
+}
+
+func TestMerkleBranch(t *testing.T) {
+	chunks := make([][32]byte, 5)
+	for i := range chunks {
+		chunks[i][0] = byte(i + 1)
+	}
+	root, err := merkleize(chunks, 16)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i := range chunks {
+		branch, err := merkleBranch(chunks, 16, i)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !verifyBranch(chunks[i], branch, i, root) {
+			t.Fatalf("branch of chunk %d does not verify", i)
+		}
+	}
+	if _, err = merkleize(chunks, 4); err == nil {
+		t.Fatal("expected error over the limit")
+	}
+}
@@ -108,6 +108,36 @@ func (c *Client) GetBlocks(ctx context.Context, blockId string) (*BlocksResp, er
 	return &ret, nil
 }
 
+func (c *Client) LightClientBootstrap(ctx context.Context, blockRoot string) (*LightClientBootstrapResp, error) {
+	urlPath := fmt.Sprintf("%s/%s/%s", c.endpoint, "eth/v1/beacon/light_client/bootstrap", blockRoot)
+	var ret LightClientBootstrapResp
+	err := c.CallContext(ctx, urlPath, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (c *Client) Genesis(ctx context.Context) (*GenesisResp, error) {
+	urlPath := fmt.Sprintf("%s/%s", c.endpoint, "eth/v1/beacon/genesis")
+	var ret GenesisResp
+	err := c.CallContext(ctx, urlPath, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
+func (c *Client) ForkSchedule(ctx context.Context) (*ForkScheduleResp, error) {
+	urlPath := fmt.Sprintf("%s/%s", c.endpoint, "eth/v1/config/fork_schedule")
+	var ret ForkScheduleResp
+	err := c.CallContext(ctx, urlPath, &ret)
+	if err != nil {
+		return nil, err
+	}
+	return &ret, nil
+}
+
 type requestOp struct {
 	ids  []json.RawMessage
 	err  error
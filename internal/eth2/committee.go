@@ -0,0 +1,154 @@
+package eth2
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/mapprotocol/compass/internal/constant"
+	"github.com/mapprotocol/compass/pkg/bls"
+)
+
+// ErrInvalidUpdate is returned when a light client update does not verify against the tracked sync committee
+var ErrInvalidUpdate = errors.New("invalid light client update")
+
+const (
+	syncCommitteeSize = 512
+	slotsPerPeriod    = uint64(constant.SlotsPerEpoch * constant.EpochsPerPeriod)
+)
+
+var domainSyncCommittee = [4]byte{7, 0, 0, 0}
+
+// SyncCommittee is a sync committee with its public keys decoded
+type SyncCommittee struct {
+	Pubkeys []*bls12381.PointG1
+	Root    common.Hash
+}
+
+// NewSyncCommittee decodes the concatenated public keys of a sync committee, checking they add up to the aggregate
+func NewSyncCommittee(c *ContractSyncCommittee) (*SyncCommittee, error) {
+	if len(c.Pubkeys) != syncCommitteeSize*blsPubkeyLength {
+		return nil, fmt.Errorf("sync committee has %d bytes of public keys, want %d", len(c.Pubkeys), syncCommitteeSize*blsPubkeyLength)
+	}
+	g1 := bls12381.NewG1()
+	sum := g1.Zero()
+	ret := &SyncCommittee{Pubkeys: make([]*bls12381.PointG1, 0, syncCommitteeSize)}
+	roots := make([][32]byte, 0, syncCommitteeSize)
+	for i := 0; i < syncCommitteeSize; i++ {
+		raw := c.Pubkeys[i*blsPubkeyLength : (i+1)*blsPubkeyLength]
+		pk, err := bls.PublicKeyFromBytes(raw)
+		if err != nil {
+			return nil, fmt.Errorf("public key %d: %w", i, err)
+		}
+		ret.Pubkeys = append(ret.Pubkeys, pk)
+		g1.Add(sum, sum, pk)
+		root, _ := vectorRoot(raw, blsPubkeyLength)
+		roots = append(roots, root)
+	}
+	agg, err := bls.PublicKeyFromBytes(c.AggregatePubkey)
+	if err != nil {
+		return nil, fmt.Errorf("aggregate public key: %w", err)
+	}
+	if !g1.Equal(sum, agg) {
+		return nil, errors.New("aggregate public key is not the sum of the public keys")
+	}
+	pubkeysRoot, err := merkleize(roots, syncCommitteeSize)
+	if err != nil {
+		return nil, err
+	}
+	aggRoot, err := vectorRoot(c.AggregatePubkey, blsPubkeyLength)
+	if err != nil {
+		return nil, err
+	}
+	root, err := container(pubkeysRoot, aggRoot)
+	if err != nil {
+		return nil, err
+	}
+	ret.Root = root
+	return ret, nil
+}
+
+// HeaderRoot is the hash tree root of a beacon block header
+func HeaderRoot(h *BeaconBlockHeader) common.Hash {
+	root, _ := container(uint64Root(h.Slot), uint64Root(h.ProposerIndex), h.ParentRoot, h.StateRoot, h.BodyRoot)
+	return root
+}
+
+// PeriodOf returns the sync committee period of the slot
+func PeriodOf(slot uint64) uint64 {
+	return slot / slotsPerPeriod
+}
+
+// isValidMerkleBranch is is_valid_merkle_branch of the consensus specs
+func isValidMerkleBranch(leaf [32]byte, branch [][32]byte, g gindex, root [32]byte) bool {
+	if len(branch) != g.depth() {
+		return false
+	}
+	index := g.index()
+	for _, sibling := range branch {
+		if index%2 == 1 {
+			leaf = hashPair(sibling, leaf)
+		} else {
+			leaf = hashPair(leaf, sibling)
+		}
+		index /= 2
+	}
+	return leaf == root
+}
+
+// signingRoot is compute_signing_root of the header in the sync committee domain of the fork
+func signingRoot(header *BeaconBlockHeader, forkVersion [4]byte, genesisValidatorsRoot [32]byte) [32]byte {
+	var version [32]byte
+	copy(version[:], forkVersion[:])
+	forkDataRoot := hashPair(version, genesisValidatorsRoot)
+	var domain [32]byte
+	copy(domain[:], domainSyncCommittee[:])
+	copy(domain[4:], forkDataRoot[:28])
+	return hashPair(HeaderRoot(header), domain)
+}
+
+// VerifyUpdate checks the finality and next sync committee branches of the update against its attested header,
+// at the indices of the fork of the version of the update, and the signature of the committee over the attested
+// header. The next sync committee is checked only when the update has its branch, and is returned decoded.
+func VerifyUpdate(u *LightClientUpdate, version string, committee *SyncCommittee, forkVersion [4]byte, genesisValidatorsRoot [32]byte) (*SyncCommittee, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidUpdate, err)
+	}
+	if u.SignatureSlot <= u.AttestedHeader.Slot || u.AttestedHeader.Slot < u.FinalizedHeader.Slot {
+		return nil, fmt.Errorf("%w: slots of signature %d, attested %d and finalized %d are out of order",
+			ErrInvalidUpdate, u.SignatureSlot, u.AttestedHeader.Slot, u.FinalizedHeader.Slot)
+	}
+	if !isValidMerkleBranch(HeaderRoot(&u.FinalizedHeader), u.FinalityBranch, f.finalizedRoot(), u.AttestedHeader.StateRoot) {
+		return nil, fmt.Errorf("%w: finality branch", ErrInvalidUpdate)
+	}
+
+	var next *SyncCommittee
+	if len(u.NextSyncCommitteeBranch) != 0 {
+		c, err := NewSyncCommittee(&u.NextSyncCommittee)
+		if err != nil {
+			return nil, fmt.Errorf("%w: next sync committee: %v", ErrInvalidUpdate, err)
+		}
+		if !isValidMerkleBranch(c.Root, u.NextSyncCommitteeBranch, f.nextSyncCommittee(), u.AttestedHeader.StateRoot) {
+			return nil, fmt.Errorf("%w: next sync committee branch", ErrInvalidUpdate)
+		}
+		next = c
+	}
+
+	bits := u.SyncAggregate.SyncCommitteeBits
+	if len(bits) != syncCommitteeSize/8 {
+		return nil, fmt.Errorf("%w: %d bytes of sync committee bits", ErrInvalidUpdate, len(bits))
+	}
+	participants := make([]*bls12381.PointG1, 0, syncCommitteeSize)
+	for i, pk := range committee.Pubkeys {
+		if bits[i/8]>>(uint(i)%8)&1 == 1 {
+			participants = append(participants, pk)
+		}
+	}
+	root := signingRoot(&u.AttestedHeader, forkVersion, genesisValidatorsRoot)
+	if err := bls.FastAggregateVerify(participants, root[:], u.SyncAggregate.SyncCommitteeSignature); err != nil {
+		return nil, fmt.Errorf("%w: sync committee signature of slot %d: %v", ErrInvalidUpdate, u.SignatureSlot, err)
+	}
+	return next, nil
+}
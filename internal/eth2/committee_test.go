@@ -0,0 +1,156 @@
+package eth2
+
+import (
+	"crypto/sha256"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+	"github.com/mapprotocol/compass/pkg/bls"
+)
+
+func testNode(tag string) [32]byte {
+	return sha256.Sum256([]byte(tag))
+}
+
+type testUpdate struct {
+	update  *LightClientUpdate
+	current *SyncCommittee
+	fork    [4]byte
+	genesis [32]byte
+}
+
+// testCommittee is the committee of the secret keys 1 to 512
+func testCommittee(t *testing.T) (ContractSyncCommittee, *SyncCommittee) {
+	g1 := bls12381.NewG1()
+	pubkeys := make([]byte, 0, syncCommitteeSize*blsPubkeyLength)
+	agg := g1.Zero()
+	for i := 1; i <= syncCommitteeSize; i++ {
+		pk := g1.MulScalar(g1.New(), g1.One(), big.NewInt(int64(i)))
+		g1.Add(agg, agg, pk)
+		pubkeys = append(pubkeys, bls.CompressG1(pk)...)
+	}
+	contract := ContractSyncCommittee{Pubkeys: pubkeys, AggregatePubkey: bls.CompressG1(agg)}
+	committee, err := NewSyncCommittee(&contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contract, committee
+}
+
+// testAggregate is the sync aggregate of the first participants of the test committee over the header
+func testAggregate(t *testing.T, header *BeaconBlockHeader, participants int, fork [4]byte, genesis [32]byte) ContractSyncAggregate {
+	bits := make([]byte, syncCommitteeSize/8)
+	sk := new(big.Int)
+	for i := 0; i < participants; i++ {
+		bits[i/8] |= 1 << (uint(i) % 8)
+		sk.Add(sk, big.NewInt(int64(i+1)))
+	}
+	root := signingRoot(header, fork, genesis)
+	h, err := bls.HashToG2(root[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	g2 := bls12381.NewG2()
+	return ContractSyncAggregate{
+		SyncCommitteeBits:      bits,
+		SyncCommitteeSignature: bls.CompressG2(g2.MulScalar(g2.New(), h, sk)),
+	}
+}
+
+// newTestUpdate signs an update by the first participants of a committee of the secret keys 1 to 512, in a state
+// with the finalized header at gindex 105 and the next committee at 55
+func newTestUpdate(t *testing.T, participants int) *testUpdate {
+	contract, committee := testCommittee(t)
+
+	finalized := BeaconBlockHeader{Slot: 8626144, ProposerIndex: 7, ParentRoot: testNode("fp"), StateRoot: testNode("fs"), BodyRoot: testNode("fb")}
+	n54, n55 := testNode("54"), committee.Root
+	n27 := hashPair(n54, n55)
+	n104, n105 := testNode("104"), [32]byte(HeaderRoot(&finalized))
+	n52, n53 := hashPair(n104, n105), testNode("53")
+	n26 := hashPair(n52, n53)
+	n13, n12 := hashPair(n26, n27), testNode("12")
+	n6, n7 := hashPair(n12, n13), testNode("7")
+	n3, n2 := hashPair(n6, n7), testNode("2")
+	stateRoot := hashPair(n2, n3)
+
+	u := &LightClientUpdate{
+		AttestedHeader:          BeaconBlockHeader{Slot: 8626208, ProposerIndex: 9, ParentRoot: testNode("ap"), StateRoot: stateRoot, BodyRoot: testNode("ab")},
+		SignatureSlot:           8626209,
+		NextSyncCommittee:       contract,
+		NextSyncCommitteeBranch: [][32]byte{n54, n26, n12, n7, n2},
+		FinalizedHeader:         finalized,
+		FinalityBranch:          [][32]byte{n104, n53, n27, n12, n7, n2},
+	}
+	ret := &testUpdate{update: u, current: committee, fork: [4]byte{4, 0, 0, 0}, genesis: testNode("genesis")}
+	u.SyncAggregate = testAggregate(t, &u.AttestedHeader, participants, ret.fork, ret.genesis)
+	return ret
+}
+
+func TestVerifyUpdate(t *testing.T) {
+	tu := newTestUpdate(t, 400)
+	next, err := VerifyUpdate(tu.update, VersionDeneb, tu.current, tu.fork, tu.genesis)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next == nil || next.Root != tu.current.Root {
+		t.Fatal("next sync committee is not returned")
+	}
+
+	// a finality update has no next sync committee
+	tu.update.NextSyncCommittee = ContractSyncCommittee{}
+	tu.update.NextSyncCommitteeBranch = nil
+	if next, err = VerifyUpdate(tu.update, VersionDeneb, tu.current, tu.fork, tu.genesis); err != nil || next != nil {
+		t.Fatalf("finality update: %v %v", next, err)
+	}
+}
+
+func TestVerifyUpdateInvalid(t *testing.T) {
+	cases := map[string]func(tu *testUpdate){
+		"participant":      func(tu *testUpdate) { tu.update.SyncAggregate.SyncCommitteeBits[63] |= 0x80 },
+		"fork":             func(tu *testUpdate) { tu.fork[0] = 3 },
+		"attested header":  func(tu *testUpdate) { tu.update.AttestedHeader.ProposerIndex++ },
+		"finalized header": func(tu *testUpdate) { tu.update.FinalizedHeader.Slot++ },
+		"finality branch":  func(tu *testUpdate) { tu.update.FinalityBranch = tu.update.FinalityBranch[1:] },
+		"committee branch": func(tu *testUpdate) { tu.update.NextSyncCommitteeBranch[0][0] ^= 1 },
+		"committee":        func(tu *testUpdate) { tu.update.NextSyncCommittee.Pubkeys = tu.update.NextSyncCommittee.Pubkeys[48:] },
+		"signature":        func(tu *testUpdate) { tu.update.SyncAggregate.SyncCommitteeSignature[95] ^= 1 },
+		"signature slot":   func(tu *testUpdate) { tu.update.SignatureSlot = tu.update.AttestedHeader.Slot },
+		"aggregate pub key": func(tu *testUpdate) {
+			tu.update.NextSyncCommittee.AggregatePubkey = tu.update.NextSyncCommittee.Pubkeys[:48]
+		},
+	}
+	tu := newTestUpdate(t, 400)
+	for name, tamper := range cases {
+		c := *tu
+		u := *tu.update
+		u.SyncAggregate.SyncCommitteeBits = append([]byte{}, u.SyncAggregate.SyncCommitteeBits...)
+		u.SyncAggregate.SyncCommitteeSignature = append([]byte{}, u.SyncAggregate.SyncCommitteeSignature...)
+		u.NextSyncCommitteeBranch = append([][32]byte{}, u.NextSyncCommitteeBranch...)
+		c.update = &u
+		tamper(&c)
+		if _, err := VerifyUpdate(c.update, VersionDeneb, c.current, c.fork, c.genesis); !errors.Is(err, ErrInvalidUpdate) {
+			t.Fatalf("%s: expected invalid update, got %v", name, err)
+		}
+	}
+}
+
+func TestForkVersion(t *testing.T) {
+	tracker := &SyncCommitteeTracker{forks: []forkVersion{
+		{epoch: 0, version: [4]byte{0}},
+		{epoch: 74240, version: [4]byte{1}},
+		{epoch: 144896, version: [4]byte{2}},
+	}}
+	cases := map[uint64]byte{
+		0:             0,
+		74240 * 32:    0, // the first slot of the fork is signed with the version of the slot before it
+		74240*32 + 1:  1,
+		144896*32 + 1: 2,
+	}
+	for slot, want := range cases {
+		if v := tracker.forkVersion(slot); v[0] != want {
+			t.Fatalf("fork version of slot %d is %x, want %x", slot, v, want)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package eth2
+
+import (
+	"errors"
+	"fmt"
+	"math/bits"
+)
+
+// fork is a fork of the beacon chain since the merge, in the order they are activated
+type fork int
+
+const (
+	forkBellatrix fork = iota
+	forkCapella
+	forkDeneb
+	forkElectra
+	forkFulu // peerdas changes neither the block body nor the beacon state, it has the indices and the body of electra
+)
+
+var forks = map[string]fork{
+	VersionBellatrix: forkBellatrix,
+	VersionCapella:   forkCapella,
+	VersionDeneb:     forkDeneb,
+	VersionElectra:   forkElectra,
+	VersionFulu:      forkFulu,
+}
+
+// ErrUnsupportedFork is returned for the versions of the forks after the ones known, the indices and the body of a
+// new fork have to be checked before it is supported
+var ErrUnsupportedFork = errors.New("beacon fork not supported")
+
+func forkOf(version string) (fork, error) {
+	f, ok := forks[version]
+	if !ok {
+		return 0, fmt.Errorf("%w: version %q", ErrUnsupportedFork, version)
+	}
+	return f, nil
+}
+
+// gindex is a generalized index of a node in a merkle tree
+type gindex uint64
+
+func (g gindex) depth() int {
+	return bits.Len64(uint64(g)) - 1
+}
+
+// index is the index of the node at its depth
+func (g gindex) index() int {
+	return int(g) - 1<<uint(g.depth())
+}
+
+// the fields of the beacon state grow past 32 in electra, which deepens the indices of the light client by one
+
+func (f fork) finalizedRoot() gindex {
+	if f >= forkElectra {
+		return 169
+	}
+	return 105
+}
+
+func (f fork) currentSyncCommittee() gindex {
+	if f >= forkElectra {
+		return 86
+	}
+	return 54
+}
+
+func (f fork) nextSyncCommittee() gindex {
+	if f >= forkElectra {
+		return 87
+	}
+	return 55
+}
+
+// executionPayload is the gindex of the execution payload in the beacon block body, the body has less than 16
+// fields in every fork
+func (f fork) executionPayload() gindex {
+	return 1<<4 + executionPayloadIndex
+}
+
+// lightClientExecution is whether the light client headers carry the execution header and its branch, which
+// they do since capella
+func (f fork) lightClientExecution() bool {
+	return f >= forkCapella
+}
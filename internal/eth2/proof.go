@@ -1,102 +1,60 @@
 package eth2
 
 import (
-	"fmt"
+	"context"
 	"math/big"
-	"os"
-	"os/exec"
-	"path/filepath"
-	"strings"
 
-	log "github.com/ChainSafe/log15"
-	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethdb/memorydb"
-	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/trie"
-	"github.com/mapprotocol/compass/internal/tx"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
-	"github.com/pkg/errors"
 )
 
-var execPath = "./eth2-proof"
-
-func init() {
-	if filepath.Dir(os.Args[0]) == "." {
-		return
-	}
-	execPath = filepath.Join(filepath.Dir(os.Args[0]), "eth2-proof")
+type ReceiptProof struct {
+	Header    BlockHeader
+	TxReceipt mapprotocol.TxReceipt
+	KeyIndex  []byte
+	Proof     [][]byte
 }
 
-func Generate(slot, endpoint string) ([][32]byte, string, string, error) {
-	c := exec.Command(execPath, "generate", "--slot", slot, "--endpoint", endpoint)
-	log.Debug("eth exec", "path", execPath, "cmd", c.String())
-	subOutPut, err := c.CombinedOutput()
-	if err != nil {
-		return nil, "", "", errors.Wrap(err, "command exec failed")
-	}
-
-	outPuts := strings.Split(string(subOutPut), "\n")
-	ret := make([][32]byte, 0, len(outPuts))
-	var txRoot, wdRoot string
-	for _, op := range outPuts {
-		if strings.HasPrefix(op, "0x") {
-			ret = append(ret, common.HexToHash(op))
-		} else if strings.HasPrefix(op, "txRoot") {
-			txRoot = strings.TrimSpace(strings.TrimPrefix(op, "txRoot"))
-		} else if strings.HasPrefix(op, "wdRoot") {
-			wdRoot = strings.TrimSpace(strings.TrimPrefix(op, "wdRoot"))
-		}
-	}
-
-	return ret, txRoot, wdRoot, nil
+func init() {
+	iproof.Register(chains.Eth2, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		return &Builder{client: cfg.Client, endpoint: cfg.Endpoint, fId: cfg.Id}, nil
+	})
 }
 
-func GenerateByApi(slot []string) [][32]byte {
-	ret := make([][32]byte, 0, len(slot))
-	for _, op := range slot {
-		ret = append(ret, common.HexToHash(op))
-	}
-
-	return ret
+// Builder assembles the proof with the execution header of the block of the log
+type Builder struct {
+	client   *ethclient.Client
+	endpoint string
+	fId      msg.ChainId
 }
 
-type ReceiptProof struct {
-	Header    BlockHeader
-	TxReceipt mapprotocol.TxReceipt
-	KeyIndex  []byte
-	Proof     [][]byte
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return iproof.Receipts(b.client, number)
 }
 
-func GetProof(client *ethclient.Client, endPoint string, latestBlock *big.Int, log *types.Log, method string, fId msg.ChainId) ([]byte, error) {
-	header, err := client.EthLatestHeaderByNumber(endPoint, latestBlock)
+func (b *Builder) Build(_ context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	header, err := b.client.EthLatestHeaderByNumber(b.endpoint, new(big.Int).SetUint64(log.BlockNumber))
 	if err != nil {
 		return nil, err
 	}
-	// when syncToMap we need to assemble a tx proof
-	txsHash, err := tx.GetTxsHashByBlockNumber(client, latestBlock)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-	}
-	receipts, err := tx.GetReceiptsByTxsHash(client, txsHash)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-	}
-	return AssembleProof(*ConvertHeader(header), *log, receipts, method, fId)
+	return AssembleProof(*ConvertHeader(header), *log, receipts, iproof.Method(log.Topics[0]), b.fId)
 }
 
-func AssembleProof(header BlockHeader, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) ([]byte, error) {
+func AssembleProof(header BlockHeader, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) (*iproof.Proof, error) {
 	txIndex := log.TxIndex
 	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
 	if err != nil {
 		return nil, err
 	}
 
-	proof, err := getProof(receipts, txIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -123,29 +81,16 @@ func AssembleProof(header BlockHeader, log types.Log, receipts []*types.Receipt,
 	if err != nil {
 		return nil, err
 	}
-	return pack, nil
-}
-
-func getProof(receipts []*types.Receipt, txIndex uint) ([][]byte, error) {
-	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
-	if err != nil {
-		return nil, err
-	}
-
-	tr = utils.DeriveTire(receipts, tr)
-	ns := light.NewNodeSet()
-	key, err := rlp.EncodeToBytes(txIndex)
+	encReceipt, err := iproof.EncodeReceipt(receipt)
 	if err != nil {
 		return nil, err
 	}
-	if err = tr.Prove(key, 0, ns); err != nil {
-		return nil, err
-	}
-
-	proof := make([][]byte, 0, len(ns.NodeList()))
-	for _, v := range ns.NodeList() {
-		proof = append(proof, v)
-	}
-
-	return proof, nil
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: header.ReceiptsRoot,
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
 }
@@ -26,6 +26,7 @@ type FinalityUpdateResp struct {
 type BlocksResp struct {
 	Data                BlockData `json:"data"`
 	ExecutionOptimistic bool      `json:"execution_optimistic"`
+	Version             string    `json:"version"`
 }
 
 type LightClientUpdatesResp struct {
@@ -97,19 +98,98 @@ type Attestations struct {
 	AggregationBits string          `json:"aggregation_bits"`
 	Data            AttestationData `json:"data"`
 	Signature       string          `json:"signature"`
+	CommitteeBits   string          `json:"committee_bits,omitempty"` // since electra
+}
+
+type ProposerSlashing struct {
+	SignedHeader1 Header `json:"signed_header_1"`
+	SignedHeader2 Header `json:"signed_header_2"`
+}
+
+type IndexedAttestation struct {
+	AttestingIndices []string        `json:"attesting_indices"`
+	Data             AttestationData `json:"data"`
+	Signature        string          `json:"signature"`
+}
+
+type AttesterSlashing struct {
+	Attestation1 IndexedAttestation `json:"attestation_1"`
+	Attestation2 IndexedAttestation `json:"attestation_2"`
+}
+
+type DepositData struct {
+	Pubkey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                string `json:"amount"`
+	Signature             string `json:"signature"`
+}
+
+type Deposit struct {
+	Proof []string    `json:"proof"`
+	Data  DepositData `json:"data"`
+}
+
+type VoluntaryExit struct {
+	Epoch          string `json:"epoch"`
+	ValidatorIndex string `json:"validator_index"`
+}
+
+type SignedVoluntaryExit struct {
+	Message   VoluntaryExit `json:"message"`
+	Signature string        `json:"signature"`
+}
+
+type BLSToExecutionChange struct {
+	ValidatorIndex     string `json:"validator_index"`
+	FromBlsPubkey      string `json:"from_bls_pubkey"`
+	ToExecutionAddress string `json:"to_execution_address"`
+}
+
+type SignedBLSToExecutionChange struct {
+	Message   BLSToExecutionChange `json:"message"`
+	Signature string               `json:"signature"`
 }
 
 type Body struct {
-	RandaoReveal      string         `json:"randao_reveal"`
-	Eth1Data          Eth1Data       `json:"eth1_data"`
-	Graffiti          string         `json:"graffiti"`
-	ProposerSlashings []interface{}  `json:"proposer_slashings"`
-	AttesterSlashings []interface{}  `json:"attester_slashings"`
-	Attestations      []Attestations `json:"attestations"`
-	Deposits          []interface{}  `json:"deposits"`
-	VoluntaryExits    []interface{}  `json:"voluntary_exits"`
-	SyncAggregate     SyncAggregate  `json:"sync_aggregate"`
-	ExecutionPayload  Execution      `json:"execution_payload"`
+	RandaoReveal          string                       `json:"randao_reveal"`
+	Eth1Data              Eth1Data                     `json:"eth1_data"`
+	Graffiti              string                       `json:"graffiti"`
+	ProposerSlashings     []ProposerSlashing           `json:"proposer_slashings"`
+	AttesterSlashings     []AttesterSlashing           `json:"attester_slashings"`
+	Attestations          []Attestations               `json:"attestations"`
+	Deposits              []Deposit                    `json:"deposits"`
+	VoluntaryExits        []SignedVoluntaryExit        `json:"voluntary_exits"`
+	SyncAggregate         SyncAggregate                `json:"sync_aggregate"`
+	ExecutionPayload      Execution                    `json:"execution_payload"`
+	BlsToExecutionChanges []SignedBLSToExecutionChange `json:"bls_to_execution_changes,omitempty"` // since capella
+	BlobKzgCommitments    []string                     `json:"blob_kzg_commitments,omitempty"`     // since deneb
+	ExecutionRequests     ExecutionRequests            `json:"execution_requests"`                 // since electra
+}
+
+type ExecutionRequests struct {
+	Deposits       []DepositRequest       `json:"deposits"`
+	Withdrawals    []WithdrawalRequest    `json:"withdrawals"`
+	Consolidations []ConsolidationRequest `json:"consolidations"`
+}
+
+type DepositRequest struct {
+	Pubkey                string `json:"pubkey"`
+	WithdrawalCredentials string `json:"withdrawal_credentials"`
+	Amount                string `json:"amount"`
+	Signature             string `json:"signature"`
+	Index                 string `json:"index"`
+}
+
+type WithdrawalRequest struct {
+	SourceAddress   string `json:"source_address"`
+	ValidatorPubkey string `json:"validator_pubkey"`
+	Amount          string `json:"amount"`
+}
+
+type ConsolidationRequest struct {
+	SourceAddress string `json:"source_address"`
+	SourcePubkey  string `json:"source_pubkey"`
+	TargetPubkey  string `json:"target_pubkey"`
 }
 
 type BlocksMessage struct {
@@ -170,6 +250,19 @@ type Execution struct {
 	BlockHash        string `json:"block_hash"`
 	TransactionsRoot string `json:"transactions_root"`
 	WithdrawalsRoot  string `json:"withdrawals_root"`
+
+	// the payload in a beacon block has the transactions and withdrawals instead of their roots
+	Transactions  []string     `json:"transactions,omitempty"`
+	Withdrawals   []Withdrawal `json:"withdrawals,omitempty"`
+	BlobGasUsed   string       `json:"blob_gas_used,omitempty"`
+	ExcessBlobGas string       `json:"excess_blob_gas,omitempty"`
+}
+
+type Withdrawal struct {
+	Index          string `json:"index"`
+	ValidatorIndex string `json:"validator_index"`
+	Address        string `json:"address"`
+	Amount         string `json:"amount"`
 }
 
 type NewFinalizedHeader struct {
@@ -185,3 +278,34 @@ type AttestationData struct {
 	Source          Source `json:"source"`
 	Target          Target `json:"target"`
 }
+
+type LightClientBootstrapResp struct {
+	Data    LightClientBootstrapData `json:"data"`
+	Version string                   `json:"version"`
+}
+
+type LightClientBootstrapData struct {
+	Header                     NewAttestedHeader `json:"header"`
+	CurrentSyncCommittee       NextSyncCommittee `json:"current_sync_committee"`
+	CurrentSyncCommitteeBranch []string          `json:"current_sync_committee_branch"`
+}
+
+type GenesisResp struct {
+	Data GenesisData `json:"data"`
+}
+
+type GenesisData struct {
+	GenesisTime           string `json:"genesis_time"`
+	GenesisValidatorsRoot string `json:"genesis_validators_root"`
+	GenesisForkVersion    string `json:"genesis_fork_version"`
+}
+
+type ForkScheduleResp struct {
+	Data []Fork `json:"data"`
+}
+
+type Fork struct {
+	PreviousVersion string `json:"previous_version"`
+	CurrentVersion  string `json:"current_version"`
+	Epoch           string `json:"epoch"`
+}
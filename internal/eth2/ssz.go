@@ -0,0 +1,211 @@
+package eth2
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+// hash tree root of simple serialize, only what the beacon block body needs
+
+const maxDepth = 40
+
+var zeroHashes [maxDepth + 1][32]byte
+
+func init() {
+	for i := 1; i <= maxDepth; i++ {
+		zeroHashes[i] = hashPair(zeroHashes[i-1], zeroHashes[i-1])
+	}
+}
+
+func hashPair(a, b [32]byte) [32]byte {
+	h := sha256.New()
+	h.Write(a[:])
+	h.Write(b[:])
+	var ret [32]byte
+	copy(ret[:], h.Sum(nil))
+	return ret
+}
+
+func depthOf(limit uint64) int {
+	depth := 0
+	for uint64(1)<<depth < limit {
+		depth++
+	}
+	return depth
+}
+
+// merkleize returns the root of the chunks padded with zero chunks to the power of two of limit
+func merkleize(chunks [][32]byte, limit uint64) ([32]byte, error) {
+	if uint64(len(chunks)) > limit {
+		return [32]byte{}, fmt.Errorf("ssz: %d chunks over the limit %d", len(chunks), limit)
+	}
+	depth := depthOf(limit)
+	if len(chunks) == 0 {
+		return zeroHashes[depth], nil
+	}
+	layer := append(make([][32]byte, 0, len(chunks)+1), chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+	}
+	return layer[0], nil
+}
+
+// merkleBranch returns the siblings of the chunk at index from the bottom up, the proof of the chunk in merkleize
+func merkleBranch(chunks [][32]byte, limit uint64, index int) ([][32]byte, error) {
+	if index >= len(chunks) || uint64(len(chunks)) > limit {
+		return nil, fmt.Errorf("ssz: index %d of %d chunks is out of range", index, len(chunks))
+	}
+	depth := depthOf(limit)
+	branch := make([][32]byte, 0, depth)
+	layer := append(make([][32]byte, 0, len(chunks)+1), chunks...)
+	for d := 0; d < depth; d++ {
+		if len(layer)%2 == 1 {
+			layer = append(layer, zeroHashes[d])
+		}
+		branch = append(branch, layer[index^1])
+		next := make([][32]byte, len(layer)/2)
+		for i := range next {
+			next[i] = hashPair(layer[2*i], layer[2*i+1])
+		}
+		layer = next
+		index /= 2
+	}
+	return branch, nil
+}
+
+func mixInLength(root [32]byte, length uint64) [32]byte {
+	var l [32]byte
+	binary.LittleEndian.PutUint64(l[:], length)
+	return hashPair(root, l)
+}
+
+func pack(b []byte) [][32]byte {
+	chunks := make([][32]byte, (len(b)+31)/32)
+	for i := range chunks {
+		copy(chunks[i][:], b[i*32:])
+	}
+	return chunks
+}
+
+// container returns the root of a container of the roots of its fields
+func container(fields ...[32]byte) ([32]byte, error) {
+	return merkleize(fields, uint64(len(fields)))
+}
+
+// list returns the root of a list of composite values of the roots
+func list(roots [][32]byte, limit uint64) ([32]byte, error) {
+	root, err := merkleize(roots, limit)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return mixInLength(root, uint64(len(roots))), nil
+}
+
+func uint64Root(v uint64) [32]byte {
+	var ret [32]byte
+	binary.LittleEndian.PutUint64(ret[:], v)
+	return ret
+}
+
+func uint256Root(v *big.Int) ([32]byte, error) {
+	var ret [32]byte
+	if v.Sign() < 0 || v.BitLen() > 256 {
+		return ret, fmt.Errorf("ssz: %s is not an uint256", v)
+	}
+	be := v.Bytes()
+	for i := range be {
+		ret[i] = be[len(be)-1-i]
+	}
+	return ret, nil
+}
+
+// vectorRoot is the root of a fixed size byte vector, like a bls signature or an address
+func vectorRoot(b []byte, size int) ([32]byte, error) {
+	if len(b) != size {
+		return [32]byte{}, fmt.Errorf("ssz: vector of %d bytes, want %d", len(b), size)
+	}
+	return merkleize(pack(b), uint64((size+31)/32))
+}
+
+// byteListRoot is the root of a byte list of at most limit bytes
+func byteListRoot(b []byte, limit uint64) ([32]byte, error) {
+	root, err := merkleize(pack(b), (limit+31)/32)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return mixInLength(root, uint64(len(b))), nil
+}
+
+// bitlistRoot is the root of a bitlist of at most limit bits, in its serialized form with the delimiter bit
+func bitlistRoot(b []byte, limit uint64) ([32]byte, error) {
+	if len(b) == 0 || b[len(b)-1] == 0 {
+		return [32]byte{}, fmt.Errorf("ssz: bitlist without delimiter")
+	}
+	last := b[len(b)-1]
+	msb := 7
+	for last>>uint(msb) == 0 {
+		msb--
+	}
+	length := uint64(len(b)-1)*8 + uint64(msb)
+	if length > limit {
+		return [32]byte{}, fmt.Errorf("ssz: bitlist of %d bits over the limit %d", length, limit)
+	}
+	bits := append([]byte{}, b...)
+	bits[len(bits)-1] &^= 1 << uint(msb)
+	if msb == 0 {
+		bits = bits[:len(bits)-1]
+	}
+	root, err := merkleize(pack(bits), (limit+255)/256)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return mixInLength(root, length), nil
+}
+
+// uint64ListRoot is the root of a list of at most limit uint64
+func uint64ListRoot(vs []uint64, limit uint64) ([32]byte, error) {
+	b := make([]byte, len(vs)*8)
+	for i, v := range vs {
+		binary.LittleEndian.PutUint64(b[i*8:], v)
+	}
+	root, err := merkleize(pack(b), (limit*8+31)/32)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return mixInLength(root, uint64(len(vs))), nil
+}
+
+// parsers of the beacon api json, numbers are decimal strings and bytes are hex strings
+
+func parseUint64(s string) (uint64, error) {
+	return strconv.ParseUint(s, 10, 64)
+}
+
+func parseHex(s string) ([]byte, error) {
+	return hexutil.Decode(s)
+}
+
+func parseBytes32(s string) ([32]byte, error) {
+	b, err := parseHex(s)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	if len(b) != 32 {
+		return [32]byte{}, fmt.Errorf("%q is not 32 bytes", s)
+	}
+	var ret [32]byte
+	copy(ret[:], b)
+	return ret, nil
+}
@@ -2,6 +2,7 @@ package eth2
 
 import (
 	"math/big"
+	"strconv"
 	"strings"
 
 	"github.com/mapprotocol/compass/pkg/ethclient"
@@ -105,7 +106,13 @@ type ContractExecution struct {
 	WithdrawalsRoot  [32]byte       `json:"withdrawals_root"`
 }
 
-func ConvertExecution(execution *Execution) (*ContractExecution, error) {
+// ConvertExecution converts the execution header of the version. The withdrawals root is zero before capella, and
+// the blob gas since deneb is checked but not taken by the contract, which has it in the root of the header.
+func ConvertExecution(version string, execution *Execution) (*ContractExecution, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return nil, err
+	}
 	blockNumber, ok := big.NewInt(0).SetString(execution.BlockNumber, 10)
 	if !ok {
 		return nil, errors.New("execution blockNumber error")
@@ -126,6 +133,21 @@ func ConvertExecution(execution *Execution) (*ContractExecution, error) {
 	if !ok {
 		return nil, errors.New("execution baseFeePerGas error")
 	}
+	var withdrawalsRoot common.Hash
+	if f >= forkCapella {
+		if _, err = parseBytes32(execution.WithdrawalsRoot); err != nil {
+			return nil, errors.Wrap(err, "execution withdrawalsRoot error")
+		}
+		withdrawalsRoot = common.HexToHash(execution.WithdrawalsRoot)
+	}
+	if f >= forkDeneb {
+		if _, err = parseUint64(execution.BlobGasUsed); err != nil {
+			return nil, errors.Wrap(err, "execution blobGasUsed error")
+		}
+		if _, err = parseUint64(execution.ExcessBlobGas); err != nil {
+			return nil, errors.Wrap(err, "execution excessBlobGas error")
+		}
+	}
 	return &ContractExecution{
 		ParentHash:       common.HexToHash(execution.ParentHash),
 		FeeRecipient:     common.HexToAddress(execution.FeeRecipient),
@@ -141,6 +163,71 @@ func ConvertExecution(execution *Execution) (*ContractExecution, error) {
 		BaseFeePerGas:    baseFeePerGas,
 		BlockHash:        common.HexToHash(execution.BlockHash),
 		TransactionsRoot: common.HexToHash(execution.TransactionsRoot),
-		WithdrawalsRoot:  common.HexToHash(execution.WithdrawalsRoot),
+		WithdrawalsRoot:  withdrawalsRoot,
+	}, nil
+}
+
+func ConvertBeacon(beacon *Beacon) (BeaconBlockHeader, error) {
+	slot, err := strconv.ParseUint(beacon.Slot, 10, 64)
+	if err != nil {
+		return BeaconBlockHeader{}, errors.Wrap(err, "beacon slot error")
+	}
+	proposerIndex, err := strconv.ParseUint(beacon.ProposerIndex, 10, 64)
+	if err != nil {
+		return BeaconBlockHeader{}, errors.Wrap(err, "beacon proposerIndex error")
+	}
+	return BeaconBlockHeader{
+		Slot:          slot,
+		ProposerIndex: proposerIndex,
+		ParentRoot:    common.HexToHash(beacon.ParentRoot),
+		StateRoot:     common.HexToHash(beacon.StateRoot),
+		BodyRoot:      common.HexToHash(beacon.BodyRoot),
+	}, nil
+}
+
+func ConvertSyncCommittee(committee *NextSyncCommittee) ContractSyncCommittee {
+	pubkeys := make([]byte, 0, len(committee.Pubkeys)*48)
+	for _, pk := range committee.Pubkeys {
+		pubkeys = append(pubkeys, common.FromHex(pk)...)
+	}
+	return ContractSyncCommittee{
+		Pubkeys:         pubkeys,
+		AggregatePubkey: common.FromHex(committee.AggregatePubkey),
+	}
+}
+
+func convertBranch(branch []string) [][32]byte {
+	ret := make([][32]byte, 0, len(branch))
+	for _, b := range branch {
+		ret = append(ret, common.HexToHash(b))
+	}
+	return ret
+}
+
+// ConvertUpdate converts the beacon part of a period update, the execution of the finalized header is left to the caller
+func ConvertUpdate(data *LightClientUpdatesData) (*LightClientUpdate, error) {
+	attested, err := ConvertBeacon(&data.AttestedHeader.Beacon)
+	if err != nil {
+		return nil, errors.Wrap(err, "attested header")
+	}
+	finalized, err := ConvertBeacon(&data.FinalizedHeader.Beacon)
+	if err != nil {
+		return nil, errors.Wrap(err, "finalized header")
+	}
+	signatureSlot, err := strconv.ParseUint(data.SignatureSlot, 10, 64)
+	if err != nil {
+		return nil, errors.Wrap(err, "signature slot error")
+	}
+	return &LightClientUpdate{
+		AttestedHeader: attested,
+		SignatureSlot:  signatureSlot,
+		SyncAggregate: ContractSyncAggregate{
+			SyncCommitteeBits:      common.FromHex(data.SyncAggregate.SyncCommitteeBits),
+			SyncCommitteeSignature: common.FromHex(data.SyncAggregate.SyncCommitteeSignature),
+		},
+		NextSyncCommittee:       ConvertSyncCommittee(&data.NextSyncCommittee),
+		NextSyncCommitteeBranch: convertBranch(data.NextSyncCommitteeBranch),
+		FinalizedHeader:         finalized,
+		FinalityBranch:          convertBranch(data.FinalityBranch),
 	}, nil
 }
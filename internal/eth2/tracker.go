@@ -0,0 +1,181 @@
+package eth2
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/internal/constant"
+)
+
+// SyncCommitteeTracker keeps the sync committees verified from a bootstrap on, to check the light client updates
+// of the beacon endpoint before they are sent to the light client on map.
+type SyncCommitteeTracker struct {
+	client                *Client
+	genesisValidatorsRoot [32]byte
+	forks                 []forkVersion // by epoch ascending
+	committees            map[uint64]*SyncCommittee
+}
+
+type forkVersion struct {
+	epoch   uint64
+	version [4]byte
+}
+
+func NewSyncCommitteeTracker(client *Client) *SyncCommitteeTracker {
+	return &SyncCommitteeTracker{
+		client:     client,
+		committees: make(map[uint64]*SyncCommittee),
+	}
+}
+
+func (t *SyncCommitteeTracker) Bootstrapped() bool {
+	return len(t.committees) != 0
+}
+
+// Bootstrap takes the sync committee of the block at the slot, which should be one the light client on map has
+// already finalized, the committees of the later periods are verified from it.
+func (t *SyncCommitteeTracker) Bootstrap(ctx context.Context, slot uint64) error {
+	genesis, err := t.client.Genesis(ctx)
+	if err != nil {
+		return err
+	}
+	schedule, err := t.client.ForkSchedule(ctx)
+	if err != nil {
+		return err
+	}
+	forks := make([]forkVersion, 0, len(schedule.Data))
+	for _, f := range schedule.Data {
+		epoch, err := strconv.ParseUint(f.Epoch, 10, 64)
+		if err != nil {
+			return fmt.Errorf("fork epoch %q: %w", f.Epoch, err)
+		}
+		v := forkVersion{epoch: epoch}
+		copy(v.version[:], common.FromHex(f.CurrentVersion))
+		forks = append(forks, v)
+	}
+
+	headers, err := t.client.BeaconHeaders(ctx, constant.BlockIdOfEth2(strconv.FormatUint(slot, 10)))
+	if err != nil {
+		return err
+	}
+	bootstrap, err := t.client.LightClientBootstrap(ctx, headers.Data.Root)
+	if err != nil {
+		return err
+	}
+	header, err := ConvertBeacon(&bootstrap.Data.Header.Beacon)
+	if err != nil {
+		return err
+	}
+	if root := common.HexToHash(headers.Data.Root); HeaderRoot(&header) != root {
+		return fmt.Errorf("%w: bootstrap header is not block %s", ErrInvalidUpdate, root.Hex())
+	}
+	f, err := forkOf(bootstrap.Version)
+	if err != nil {
+		return err
+	}
+	current := ConvertSyncCommittee(&bootstrap.Data.CurrentSyncCommittee)
+	committee, err := NewSyncCommittee(&current)
+	if err != nil {
+		return fmt.Errorf("%w: bootstrap sync committee: %v", ErrInvalidUpdate, err)
+	}
+	if !isValidMerkleBranch(committee.Root, convertBranch(bootstrap.Data.CurrentSyncCommitteeBranch),
+		f.currentSyncCommittee(), header.StateRoot) {
+		return fmt.Errorf("%w: bootstrap sync committee branch", ErrInvalidUpdate)
+	}
+
+	t.genesisValidatorsRoot = common.HexToHash(genesis.Data.GenesisValidatorsRoot)
+	t.forks = forks
+	t.committees = map[uint64]*SyncCommittee{PeriodOf(header.Slot): committee}
+	return nil
+}
+
+// Verify checks the update of the version with the committee of the period of its signature slot, following the
+// period updates of the endpoint up to it when it is not tracked yet, and keeps the next sync committee the update
+// carries.
+func (t *SyncCommitteeTracker) Verify(ctx context.Context, version string, u *LightClientUpdate) error {
+	if !t.Bootstrapped() {
+		return fmt.Errorf("sync committee tracker is not bootstrapped")
+	}
+	committee, err := t.committee(ctx, PeriodOf(u.SignatureSlot))
+	if err != nil {
+		return err
+	}
+	next, err := VerifyUpdate(u, version, committee, t.forkVersion(u.SignatureSlot), t.genesisValidatorsRoot)
+	if err != nil {
+		return err
+	}
+	if next != nil {
+		t.keep(PeriodOf(u.AttestedHeader.Slot)+1, next)
+	}
+	return nil
+}
+
+func (t *SyncCommitteeTracker) committee(ctx context.Context, period uint64) (*SyncCommittee, error) {
+	for {
+		if c, ok := t.committees[period]; ok {
+			return c, nil
+		}
+		latest := t.latest()
+		if latest > period {
+			return nil, fmt.Errorf("sync committee of period %d is before the tracked period %d", period, latest)
+		}
+		// the update of the latest tracked period carries the committee of the one after it
+		resp, err := t.client.LightClientUpdate(ctx, latest)
+		if err != nil {
+			return nil, err
+		}
+		u, err := ConvertUpdate(&resp.Data)
+		if err != nil {
+			return nil, err
+		}
+		if PeriodOf(u.AttestedHeader.Slot) != latest || PeriodOf(u.SignatureSlot) != latest {
+			return nil, fmt.Errorf("%w: update of period %d is signed at slot %d", ErrInvalidUpdate, latest, u.SignatureSlot)
+		}
+		next, err := VerifyUpdate(u, resp.Version, t.committees[latest], t.forkVersion(u.SignatureSlot), t.genesisValidatorsRoot)
+		if err != nil {
+			return nil, err
+		}
+		if next == nil {
+			return nil, fmt.Errorf("%w: update of period %d has no next sync committee", ErrInvalidUpdate, latest)
+		}
+		t.keep(latest+1, next)
+	}
+}
+
+// keep tracks the committee of the period, dropping the ones before the period of the current committee
+func (t *SyncCommitteeTracker) keep(period uint64, c *SyncCommittee) {
+	t.committees[period] = c
+	for p := range t.committees {
+		if p+1 < period {
+			delete(t.committees, p)
+		}
+	}
+}
+
+func (t *SyncCommitteeTracker) latest() uint64 {
+	var latest uint64
+	for p := range t.committees {
+		if p > latest {
+			latest = p
+		}
+	}
+	return latest
+}
+
+// forkVersion is the version the committee signs with at the slot, of the epoch of the slot before it
+func (t *SyncCommitteeTracker) forkVersion(signatureSlot uint64) [4]byte {
+	if signatureSlot > 0 {
+		signatureSlot--
+	}
+	epoch := signatureSlot / uint64(constant.SlotsPerEpoch)
+	var version [4]byte
+	for _, f := range t.forks {
+		if f.epoch > epoch {
+			break
+		}
+		version = f.version
+	}
+	return version
+}
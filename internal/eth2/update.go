@@ -0,0 +1,107 @@
+package eth2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// UpdateBuilder converts the light client updates of the beacon api for the light client on map, by the fork of
+// their version
+type UpdateBuilder struct {
+	client *Client
+}
+
+func NewUpdateBuilder(client *Client) *UpdateBuilder {
+	return &UpdateBuilder{client: client}
+}
+
+// Build converts the update of the version with the execution of its finalized header. Since capella the light
+// client header carries the execution header and its branch, which are checked against the body root, before it
+// they are hashed from the block of the finalized header.
+func (b *UpdateBuilder) Build(ctx context.Context, version string, data *LightClientUpdatesData) (*LightClientUpdate, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return nil, err
+	}
+	u, err := ConvertUpdate(data)
+	if err != nil {
+		return nil, err
+	}
+	if depth := f.finalizedRoot().depth(); len(u.FinalityBranch) != depth {
+		return nil, fmt.Errorf("%w: finality branch of %s has %d nodes, want %d", ErrInvalidUpdate, version,
+			len(u.FinalityBranch), depth)
+	}
+	if depth := f.nextSyncCommittee().depth(); len(u.NextSyncCommitteeBranch) != 0 && len(u.NextSyncCommitteeBranch) != depth {
+		return nil, fmt.Errorf("%w: next sync committee branch of %s has %d nodes, want %d", ErrInvalidUpdate, version,
+			len(u.NextSyncCommitteeBranch), depth)
+	}
+
+	if f.lightClientExecution() {
+		u.ExecutionBranch, u.FinalizedExecution, err = headerExecution(version, &data.FinalizedHeader)
+	} else {
+		u.ExecutionBranch, u.FinalizedExecution, err = b.blockExecution(ctx, version, &data.FinalizedHeader)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return u, nil
+}
+
+func headerExecution(version string, header *NewFinalizedHeader) ([][32]byte, *ContractExecution, error) {
+	f, err := forkOf(version)
+	if err != nil {
+		return nil, nil, err
+	}
+	root, err := ExecutionHeaderRoot(version, &header.Execution)
+	if err != nil {
+		return nil, nil, err
+	}
+	branch := convertBranch(header.ExecutionBranch)
+	if !isValidMerkleBranch(root, branch, f.executionPayload(), common.HexToHash(header.Beacon.BodyRoot)) {
+		return nil, nil, fmt.Errorf("%w: execution branch of slot %s", ErrInvalidUpdate, header.Beacon.Slot)
+	}
+	execution, err := ConvertExecution(version, &header.Execution)
+	if err != nil {
+		return nil, nil, err
+	}
+	return branch, execution, nil
+}
+
+func (b *UpdateBuilder) blockExecution(ctx context.Context, version string, header *NewFinalizedHeader) ([][32]byte, *ContractExecution, error) {
+	block, err := b.client.GetBlocks(ctx, header.Beacon.Slot)
+	if err != nil {
+		return nil, nil, err
+	}
+	if block.Version != "" {
+		version = block.Version
+	}
+	ep, err := Generate(version, &block.Data.Message.Body)
+	if err != nil {
+		return nil, nil, err
+	}
+	if bodyRoot := common.HexToHash(header.Beacon.BodyRoot); ep.BodyRoot != bodyRoot {
+		return nil, nil, fmt.Errorf("body root of slot %s is %s, finalized header has %s",
+			header.Beacon.Slot, ep.BodyRoot, bodyRoot)
+	}
+	payload := block.Data.Message.Body.ExecutionPayload
+	payload.TransactionsRoot = ep.TransactionsRoot.Hex()
+	payload.WithdrawalsRoot = ep.WithdrawalsRoot.Hex()
+	execution, err := ConvertExecution(version, &payload)
+	if err != nil {
+		return nil, nil, err
+	}
+	return ep.Branch, execution, nil
+}
+
+// Update returns the finality update as a light client update without the next sync committee
+func (d *FinalityUpdateData) Update() *LightClientUpdatesData {
+	return &LightClientUpdatesData{
+		AttestedHeader:  d.AttestedHeader,
+		FinalizedHeader: d.FinalizedHeader,
+		FinalityBranch:  d.FinalityBranch,
+		SyncAggregate:   d.SyncAggregate,
+		SignatureSlot:   d.SignatureSlot,
+	}
+}
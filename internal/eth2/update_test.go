@@ -0,0 +1,316 @@
+package eth2
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+)
+
+var updateFixtures = flag.Bool("update", false, "regenerate testdata/updates.json")
+
+var fixtureVersions = []string{VersionBellatrix, VersionCapella, VersionDeneb, VersionElectra}
+
+// testForkVersion is the version the test committee signs with in the fork, as the ones of mainnet
+func testForkVersion(f fork) [4]byte {
+	return [4]byte{byte(f) + 2, 0, 0, 0}
+}
+
+// the body of the finalized block of the fixture of the version
+func fixtureBlock(t *testing.T, version string) *BlocksResp {
+	if version == VersionElectra {
+		return loadBlockFile(t, "block_electra.json")
+	}
+	block := loadBlock(t)
+	block.Version = version
+	return block
+}
+
+func loadUpdates(t *testing.T) map[string]*LightClientUpdatesResp {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "updates.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ret map[string]*LightClientUpdatesResp
+	if err = json.Unmarshal(data, &ret); err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+// blockServer answers the blocks of the beacon api with the finalized block of the bellatrix fixture
+func blockServer(t *testing.T) *Client {
+	block := fixtureBlock(t, VersionBellatrix)
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if req.URL.Path != "/eth/v2/beacon/blocks/"+block.Data.Message.Slot {
+			rw.WriteHeader(http.StatusNotFound)
+			return
+		}
+		rw.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(rw).Encode(block)
+	}))
+	t.Cleanup(server.Close)
+	client, err := DialHttp(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return client
+}
+
+func isAncestor(a, g gindex) bool {
+	for g > a {
+		g /= 2
+	}
+	return g == a
+}
+
+// testState returns the root of a state with the leaves at their gindices and opaque nodes elsewhere, with the
+// branches of the leaves
+func testState(leaves map[gindex][32]byte) ([32]byte, map[gindex][][32]byte) {
+	var node func(g gindex) [32]byte
+	node = func(g gindex) [32]byte {
+		if leaf, ok := leaves[g]; ok {
+			return leaf
+		}
+		for l := range leaves {
+			if isAncestor(g, l) {
+				return hashPair(node(2*g), node(2*g+1))
+			}
+		}
+		return testNode(fmt.Sprintf("state %d", g))
+	}
+	branches := make(map[gindex][][32]byte, len(leaves))
+	for l := range leaves {
+		for g := l; g > 1; g /= 2 {
+			branches[l] = append(branches[l], node(g^1))
+		}
+	}
+	return node(1), branches
+}
+
+func hexBranch(branch [][32]byte) []string {
+	ret := make([]string, 0, len(branch))
+	for _, b := range branch {
+		ret = append(ret, common.Hash(b).Hex())
+	}
+	return ret
+}
+
+// TestGenerateUpdateFixtures writes testdata/updates.json with -update. The updates are synthetic, one of each
+// fork as the beacon api answers them: the finalized header is of the block of the fork in testdata, with the
+// execution header and its branch since capella, the state of the attested header has the finalized header and
+// the next sync committee at the gindices of the fork, and the update is signed by the test committee. The updates
+// since deneb are period updates with the next sync committee, the ones before are finality updates.
+func TestGenerateUpdateFixtures(t *testing.T) {
+	if !*updateFixtures {
+		t.Skip("run with -update to regenerate the fixtures")
+	}
+	contract, committee := testCommittee(t)
+	pubkeys := make([]string, 0, syncCommitteeSize)
+	for i := 0; i < syncCommitteeSize; i++ {
+		pubkeys = append(pubkeys, hexutil.Encode(contract.Pubkeys[i*blsPubkeyLength:(i+1)*blsPubkeyLength]))
+	}
+
+	fixtures := make(map[string]*LightClientUpdatesResp, len(fixtureVersions))
+	for _, version := range fixtureVersions {
+		f := forks[version]
+		block := fixtureBlock(t, version)
+		ep, err := Generate(version, &block.Data.Message.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		finalized := Beacon{
+			Slot:          block.Data.Message.Slot,
+			ProposerIndex: block.Data.Message.ProposerIndex,
+			ParentRoot:    block.Data.Message.ParentRoot,
+			StateRoot:     block.Data.Message.StateRoot,
+			BodyRoot:      ep.BodyRoot.Hex(),
+		}
+		data := LightClientUpdatesData{FinalizedHeader: NewFinalizedHeader{Beacon: finalized}}
+		if f.lightClientExecution() {
+			execution := block.Data.Message.Body.ExecutionPayload
+			execution.Transactions, execution.Withdrawals = nil, nil
+			execution.TransactionsRoot, execution.WithdrawalsRoot = ep.TransactionsRoot.Hex(), ep.WithdrawalsRoot.Hex()
+			if f < forkDeneb {
+				execution.BlobGasUsed, execution.ExcessBlobGas = "", ""
+			}
+			data.FinalizedHeader.Execution = execution
+			data.FinalizedHeader.ExecutionBranch = hexBranch(ep.Branch)
+		}
+
+		header, err := ConvertBeacon(&finalized)
+		if err != nil {
+			t.Fatal(err)
+		}
+		leaves := map[gindex][32]byte{f.finalizedRoot(): HeaderRoot(&header)}
+		if f >= forkDeneb {
+			leaves[f.nextSyncCommittee()] = committee.Root
+			data.NextSyncCommittee = NextSyncCommittee{Pubkeys: pubkeys, AggregatePubkey: hexutil.Encode(contract.AggregatePubkey)}
+		}
+		stateRoot, branches := testState(leaves)
+		data.FinalityBranch = hexBranch(branches[f.finalizedRoot()])
+		data.NextSyncCommitteeBranch = hexBranch(branches[f.nextSyncCommittee()])
+
+		attested := BeaconBlockHeader{
+			Slot:          header.Slot + 64,
+			ProposerIndex: header.ProposerIndex + 1,
+			ParentRoot:    testNode(version + " parent"),
+			StateRoot:     stateRoot,
+			BodyRoot:      testNode(version + " body"),
+		}
+		data.AttestedHeader.Beacon = Beacon{
+			Slot:          fmt.Sprint(attested.Slot),
+			ProposerIndex: fmt.Sprint(attested.ProposerIndex),
+			ParentRoot:    common.Hash(attested.ParentRoot).Hex(),
+			StateRoot:     common.Hash(attested.StateRoot).Hex(),
+			BodyRoot:      common.Hash(attested.BodyRoot).Hex(),
+		}
+		aggregate := testAggregate(t, &attested, 400, testForkVersion(f), testNode("genesis"))
+		data.SyncAggregate = SyncAggregate{
+			SyncCommitteeBits:      hexutil.Encode(aggregate.SyncCommitteeBits),
+			SyncCommitteeSignature: hexutil.Encode(aggregate.SyncCommitteeSignature),
+		}
+		data.SignatureSlot = fmt.Sprint(attested.Slot + 1)
+		fixtures[version] = &LightClientUpdatesResp{Data: data, Version: version}
+	}
+
+	out, err := json.MarshalIndent(fixtures, "", "  ")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = ioutil.WriteFile(filepath.Join("testdata", "updates.json"), append(out, '\n'), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBuildUpdate(t *testing.T) {
+	fixtures := loadUpdates(t)
+	builder := NewUpdateBuilder(blockServer(t))
+	_, committee := testCommittee(t)
+	for _, version := range fixtureVersions {
+		resp := fixtures[version]
+		f := forks[version]
+		u, err := builder.Build(context.Background(), resp.Version, &resp.Data)
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+
+		payload := fixtureBlock(t, version).Data.Message.Body.ExecutionPayload
+		if u.FinalizedExecution.BlockNumber.String() != payload.BlockNumber ||
+			common.Hash(u.FinalizedExecution.BlockHash).Hex() != payload.BlockHash {
+			t.Fatalf("%s: finalized execution is of block %s %x", version, u.FinalizedExecution.BlockNumber, u.FinalizedExecution.BlockHash)
+		}
+		if (u.FinalizedExecution.WithdrawalsRoot == [32]byte{}) != (f == forkBellatrix) {
+			t.Fatalf("%s: withdrawals root %x", version, u.FinalizedExecution.WithdrawalsRoot)
+		}
+		if len(u.ExecutionBranch) != f.executionPayload().depth() {
+			t.Fatalf("%s: execution branch has %d nodes", version, len(u.ExecutionBranch))
+		}
+
+		next, err := VerifyUpdate(u, resp.Version, committee, testForkVersion(f), testNode("genesis"))
+		if err != nil {
+			t.Fatalf("%s: %v", version, err)
+		}
+		if (next != nil) != (f >= forkDeneb) {
+			t.Fatalf("%s: next sync committee %v", version, next)
+		}
+	}
+
+	// a finality update is converted the same without the next sync committee
+	resp := fixtures[VersionCapella]
+	finality := &FinalityUpdateData{
+		AttestedHeader:  resp.Data.AttestedHeader,
+		FinalizedHeader: resp.Data.FinalizedHeader,
+		FinalityBranch:  resp.Data.FinalityBranch,
+		SyncAggregate:   resp.Data.SyncAggregate,
+		SignatureSlot:   resp.Data.SignatureSlot,
+	}
+	u, err := builder.Build(context.Background(), VersionCapella, finality.Update())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if u.FinalizedHeader.Slot != 8626176 || len(u.NextSyncCommitteeBranch) != 0 {
+		t.Fatalf("finality update %+v", u.FinalizedHeader)
+	}
+}
+
+func TestBuildUpdateOfOtherFork(t *testing.T) {
+	fixtures := loadUpdates(t)
+	builder := NewUpdateBuilder(blockServer(t))
+	cases := []struct {
+		name    string
+		data    string
+		version string
+		invalid bool // the update is rejected as invalid, not only unconvertible
+	}{
+		{"electra branches at deneb indices", VersionElectra, VersionDeneb, true},
+		{"deneb branches at electra indices", VersionDeneb, VersionElectra, true},
+		{"deneb execution without blob gas", VersionDeneb, VersionCapella, true},
+		{"capella execution without blob gas", VersionCapella, VersionDeneb, false},
+		{"unknown fork", VersionElectra, "gloas", false},
+	}
+	for _, c := range cases {
+		data := fixtures[c.data].Data
+		_, err := builder.Build(context.Background(), c.version, &data)
+		if err == nil || errors.Is(err, ErrInvalidUpdate) != c.invalid {
+			t.Errorf("%s: %v", c.name, err)
+		}
+	}
+
+	data := fixtures[VersionDeneb].Data
+	data.FinalizedHeader.Execution.ExcessBlobGas = "1"
+	if _, err := builder.Build(context.Background(), VersionDeneb, &data); !errors.Is(err, ErrInvalidUpdate) {
+		t.Fatalf("blob gas is not checked: %v", err)
+	}
+	// the branch of the body root is one of the light client header before capella
+	data = fixtures[VersionBellatrix].Data
+	data.FinalizedHeader.Beacon.BodyRoot = fixtures[VersionCapella].Data.FinalizedHeader.Beacon.BodyRoot
+	if _, err := builder.Build(context.Background(), VersionBellatrix, &data); err == nil || !strings.Contains(err.Error(), "body root") {
+		t.Fatalf("body root is not checked: %v", err)
+	}
+}
+
+func TestForkIndices(t *testing.T) {
+	cases := []struct {
+		g            gindex
+		depth, index int
+	}{
+		{forkDeneb.finalizedRoot(), 6, 41},
+		{forkElectra.finalizedRoot(), 7, 41},
+		{forkCapella.currentSyncCommittee(), 5, 22},
+		{forkElectra.currentSyncCommittee(), 6, 22},
+		{forkBellatrix.nextSyncCommittee(), 5, 23},
+		{forkElectra.nextSyncCommittee(), 6, 23},
+		{forkElectra.executionPayload(), 4, executionPayloadIndex},
+		{forkFulu.finalizedRoot(), 7, 41},
+		{forkFulu.nextSyncCommittee(), 6, 23},
+	}
+	for _, c := range cases {
+		if c.g.depth() != c.depth || c.g.index() != c.index {
+			t.Fatalf("gindex %d is at depth %d index %d, want %d %d", c.g, c.g.depth(), c.g.index(), c.depth, c.index)
+		}
+	}
+}
+
+func TestForkOf(t *testing.T) {
+	f, err := forkOf(VersionFulu)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.finalizedRoot() != forkElectra.finalizedRoot() || f.currentSyncCommittee() != forkElectra.currentSyncCommittee() {
+		t.Fatalf("fulu has other indices than electra")
+	}
+	if _, err := forkOf("gloas"); !errors.Is(err, ErrUnsupportedFork) {
+		t.Fatalf("unknown version returns %v, want %v", err, ErrUnsupportedFork)
+	}
+}
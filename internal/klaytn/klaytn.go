@@ -6,18 +6,17 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
-	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethdb/memorydb"
-	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/trie"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/internal/tx"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
 	"github.com/pkg/errors"
 )
@@ -171,6 +170,20 @@ type ReceiptRLP struct {
 // ReceiptRlps implements DerivableList for receipts.
 type ReceiptRlps []*ReceiptRLP
 
+// NewReceiptRlps converts the receipts into the klaytn receipts stored in the receipt trie
+func NewReceiptRlps(receipts []*types.Receipt) ReceiptRlps {
+	rs := make(ReceiptRlps, 0, len(receipts))
+	for _, receipt := range receipts {
+		rs = append(rs, &ReceiptRLP{
+			Status:  uint(receipt.Status),
+			GasUsed: receipt.GasUsed,
+			Bloom:   receipt.Bloom,
+			Logs:    receipt.Logs,
+		})
+	}
+	return rs
+}
+
 // Len returns the number of receipts in this list.
 func (rs ReceiptRlps) Len() int { return len(rs) }
 
@@ -185,50 +198,55 @@ type TxLog struct {
 	Data   []byte
 }
 
-func GetProof(client *ethclient.Client, kClient *Client, latestBlock *big.Int, log *types.Log, method string, fId msg.ChainId) ([]byte, error) {
-	txsHash, err := GetTxsHashByBlockNumber(kClient, latestBlock)
+func init() {
+	iproof.Register(chains.Klaytn, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		kc, err := DialHttp(cfg.Endpoint, true)
+		if err != nil {
+			return nil, err
+		}
+		return NewBuilder(cfg.Client, kc, cfg.Id), nil
+	})
+}
+
+func NewBuilder(client *ethclient.Client, kc *Client, fId msg.ChainId) *Builder {
+	return &Builder{client: client, kClient: kc, fId: fId}
+}
+
+// Builder assembles the proof with the klaytn header of the block of the log
+type Builder struct {
+	client  *ethclient.Client
+	kClient *Client
+	fId     msg.ChainId
+}
+
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	txsHash, err := GetTxsHashByBlockNumber(b.kClient, number)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
 	}
-	receipts, err := tx.GetReceiptsByTxsHash(client, txsHash)
+	receipts, err := tx.GetReceiptsByTxsHash(b.client, txsHash)
 	if err != nil {
 		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
 	}
-	// get block
-	header, err := client.HeaderByNumber(context.Background(), latestBlock)
+	return receipts, nil
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	latestBlock := new(big.Int).SetUint64(log.BlockNumber)
+	header, err := b.client.HeaderByNumber(ctx, latestBlock)
 	if err != nil {
 		return nil, err
 	}
-	kHeader, err := kClient.BlockByNumber(context.Background(), latestBlock)
+	kHeader, err := b.kClient.BlockByNumber(ctx, latestBlock)
 	if err != nil {
 		return nil, err
 	}
-	return AssembleProof(ConvertContractHeader(header, kHeader), *log, fId, receipts, method)
+	return AssembleProof(ConvertContractHeader(header, kHeader), *log, b.fId, receipts, iproof.Method(log.Topics[0]))
 }
 
-func AssembleProof(header Header, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) ([]byte, error) {
-	receiptRlps := make(ReceiptRlps, 0, len(receipts))
-	for _, receipt := range receipts {
-		logs := make([]TxLog, 0, len(receipt.Logs))
-		for _, lg := range receipt.Logs {
-			topics := make([][]byte, len(lg.Topics))
-			for i := range lg.Topics {
-				topics[i] = lg.Topics[i][:]
-			}
-			logs = append(logs, TxLog{
-				Addr:   lg.Address,
-				Topics: topics,
-				Data:   lg.Data,
-			})
-		}
-		receiptRlps = append(receiptRlps, &ReceiptRLP{
-			Status:  uint(receipt.Status),
-			GasUsed: receipt.GasUsed,
-			Bloom:   receipt.Bloom,
-			Logs:    receipt.Logs,
-		})
-	}
-	proof, err := getProof(receiptRlps, log.TxIndex)
+func AssembleProof(header Header, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) (*iproof.Proof, error) {
+	receiptRlps := NewReceiptRlps(receipts)
+	proof, err := receipttrie.Prove(receiptRlps, log.TxIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -266,8 +284,19 @@ func AssembleProof(header Header, log types.Log, fId msg.ChainId, receipts []*ty
 	if err != nil {
 		return nil, err
 	}
-
-	return pack, nil
+	// klaytn receipts are not typed, the value in the trie is the rlp of ReceiptRLP
+	encReceipt, err := rlp.EncodeToBytes(receiptRlps[log.TxIndex])
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: common.BytesToHash(header.ReceiptsRoot),
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
 }
 
 func GetTxReceipt(receipt *types.Receipt) (*mapprotocol.TxReceipt, error) {
@@ -292,60 +321,3 @@ func GetTxReceipt(receipt *types.Receipt) (*mapprotocol.TxReceipt, error) {
 		Logs:              logs,
 	}, nil
 }
-
-func getProof(receipts utils.DerivableList, txIndex uint) ([][]byte, error) {
-	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
-	if err != nil {
-		return nil, err
-	}
-
-	tr = DeriveTire(receipts, tr)
-	ns := light.NewNodeSet()
-	key, err := rlp.EncodeToBytes(txIndex)
-	if err != nil {
-		return nil, err
-	}
-	if err = tr.Prove(key, 0, ns); err != nil {
-		return nil, err
-	}
-
-	proof := make([][]byte, 0, len(ns.NodeList()))
-	for _, v := range ns.NodeList() {
-		proof = append(proof, v)
-	}
-
-	return proof, nil
-}
-
-var encodeBufferPool = sync.Pool{
-	New: func() interface{} { return new(bytes.Buffer) },
-}
-
-func encodeForDerive(list utils.DerivableList, i int, buf *bytes.Buffer) []byte {
-	buf.Reset()
-	list.EncodeIndex(i, buf)
-	return common.CopyBytes(buf.Bytes())
-}
-
-func DeriveTire(rs utils.DerivableList, tr *trie.Trie) *trie.Trie {
-	valueBuf := encodeBufferPool.Get().(*bytes.Buffer)
-	defer encodeBufferPool.Put(valueBuf)
-
-	var indexBuf []byte
-	for i := 1; i < rs.Len() && i <= 0x7f; i++ {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], uint64(i))
-		value := encodeForDerive(rs, i, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	if rs.Len() > 0 {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], 0)
-		value := encodeForDerive(rs, 0, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	for i := 0x80; i < rs.Len(); i++ {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], uint64(i))
-		value := encodeForDerive(rs, i, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	return tr
-}
@@ -0,0 +1,48 @@
+package klaytn
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+	utils "github.com/mapprotocol/compass/shared/ethereum"
+)
+
+func TestReceiptRlpsGolden(t *testing.T) {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", "receipts.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := struct {
+		ReceiptsRoot common.Hash      `json:"receiptsRoot"`
+		Receipts     []*types.Receipt `json:"receipts"`
+	}{}
+	if err = json.Unmarshal(data, &b); err != nil {
+		t.Fatal(err)
+	}
+
+	rs := NewReceiptRlps(b.Receipts)
+	if root := receipttrie.DeriveRoot(rs); root != b.ReceiptsRoot {
+		t.Fatalf("root %s, header has %s", root.Hex(), b.ReceiptsRoot.Hex())
+	}
+	for i := range rs {
+		nodes, err := receipttrie.Prove(rs, uint(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+		enc, err := rlp.EncodeToBytes(rs[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		key := utils.Key2Hex(rlp.AppendUint64(nil, uint64(i)), len(nodes))
+		if err = iproof.Verify(b.ReceiptsRoot, key, nodes, enc); err != nil {
+			t.Fatalf("index %d: %v", i, err)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package mapchain
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul/backend"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+const (
+	PathPostfix = ".compass/blockstore"
+	FileExt     = ".validators"
+)
+
+// Store keeps the validator snapshot of the last verified epoch header on disk, so the tracking goes on from it
+// after a restart
+type Store struct {
+	path     string // Path excluding filename
+	fullPath string
+}
+
+func NewStore(path string, chain msg.ChainId, relayer string, role mapprotocol.Role) (*Store, error) {
+	if path == "" {
+		def, err := getDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = def
+	}
+
+	return &Store{
+		path:     path,
+		fullPath: filepath.Join(path, fmt.Sprintf("%s-%d-%s%s", relayer, chain, role, FileExt)),
+	}, nil
+}
+
+// Load returns the stored snapshot, nil when there is none yet
+func (s *Store) Load() (*backend.Snapshot, error) {
+	data, err := ioutil.ReadFile(s.fullPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var snap backend.Snapshot
+	if err = json.Unmarshal(data, &snap); err != nil {
+		return nil, fmt.Errorf("validator snapshot %s: %w", s.fullPath, err)
+	}
+	return &snap, nil
+}
+
+// Save replaces the stored snapshot, the file is written aside and renamed so a crash never leaves half of it
+func (s *Store) Save(snap *backend.Snapshot) error {
+	if _, err := os.Stat(s.path); os.IsNotExist(err) {
+		errr := os.MkdirAll(s.path, os.ModePerm)
+		if errr != nil {
+			return errr
+		}
+	}
+
+	data, err := json.Marshal(snap)
+	if err != nil {
+		return err
+	}
+	tmp := s.fullPath + ".tmp"
+	if err = ioutil.WriteFile(tmp, data, 0600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, s.fullPath)
+}
+
+// getDefaultPath returns the home directory joined with PathPostfix
+func getDefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, PathPostfix), nil
+}
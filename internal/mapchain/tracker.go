@@ -0,0 +1,121 @@
+package mapchain
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul/backend"
+	"github.com/mapprotocol/atlas/core/types"
+)
+
+// Client is what the tracker reads of a map node
+type Client interface {
+	MAPHeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	GetSnapshot(ctx context.Context, number *big.Int) (*backend.Snapshot, error)
+}
+
+// ValidatorTracker keeps the validator set verified from epoch header to epoch header, to check the headers of map
+// before they are sent to the light clients on other chains. The validators of the node are only taken as they are
+// when nothing is tracked yet.
+type ValidatorTracker struct {
+	client Client
+	store  *Store
+	epoch  uint64
+	snap   *backend.Snapshot // validators after the last verified epoch header
+}
+
+func NewValidatorTracker(client Client, store *Store, epoch uint64) (*ValidatorTracker, error) {
+	snap, err := store.Load()
+	if err != nil {
+		return nil, err
+	}
+	return &ValidatorTracker{
+		client: client,
+		store:  store,
+		epoch:  epoch,
+		snap:   snap,
+	}, nil
+}
+
+// Tracked returns the number of the last verified epoch header, zero when nothing is tracked
+func (t *ValidatorTracker) Tracked() uint64 {
+	if t.snap == nil {
+		return 0
+	}
+	return t.snap.Number
+}
+
+// Verify checks the epoch header, following the epoch headers of the node up to it from the tracked one. The
+// tracking starts over from the validators of the node when the header is at or before the tracked one and is
+// not the tracked header itself, which happens only when the blocks are synced again from an earlier height.
+func (t *ValidatorTracker) Verify(ctx context.Context, header *types.Header) error {
+	number := header.Number.Uint64()
+	if number == 0 || number%t.epoch != 0 {
+		return fmt.Errorf("block %d is not the last block of an epoch", number)
+	}
+	if t.snap != nil && t.snap.Number == number && t.snap.Hash == header.Hash() {
+		return nil
+	}
+	if t.snap == nil || t.snap.Number >= number {
+		if err := t.bootstrap(ctx, number-t.epoch); err != nil {
+			return err
+		}
+	}
+
+	for n := t.snap.Number + t.epoch; n < number; n += t.epoch {
+		h, err := t.client.MAPHeaderByNumber(ctx, new(big.Int).SetUint64(n))
+		if err != nil {
+			return err
+		}
+		if err = t.apply(ctx, h); err != nil {
+			return err
+		}
+	}
+	return t.apply(ctx, header)
+}
+
+// bootstrap takes the validators of the node after the epoch header of the number
+func (t *ValidatorTracker) bootstrap(ctx context.Context, number uint64) error {
+	snap, err := t.client.GetSnapshot(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return err
+	}
+	t.snap = &backend.Snapshot{Epoch: t.epoch, Number: number, Hash: snap.Hash, ValSet: snap.ValSet}
+	return nil
+}
+
+// apply verifies the header with the tracked validators, which the node should agree on as they sign the aggregated
+// public key relayed with the header, and keeps the validators of the next epoch
+func (t *ValidatorTracker) apply(ctx context.Context, header *types.Header) error {
+	number := header.Number.Uint64()
+	if number != t.snap.Number+t.epoch {
+		return fmt.Errorf("block %d does not follow the tracked epoch block %d", number, t.snap.Number)
+	}
+	parent, err := t.client.GetSnapshot(ctx, new(big.Int).SetUint64(number-1))
+	if err != nil {
+		return err
+	}
+	if !SameValidators(parent.ValSet, t.snap.ValSet) {
+		return fmt.Errorf("%w: validators of the node before block %d are not the tracked ones", ErrInvalidHeader, number)
+	}
+	next, err := VerifyHeader(header, t.snap.ValSet)
+	if err != nil {
+		return err
+	}
+	snap, err := t.client.GetSnapshot(ctx, header.Number)
+	if err != nil {
+		return err
+	}
+	if !SameValidators(snap.ValSet, next) {
+		return fmt.Errorf("%w: validators of the node after block %d are not the ones of its validator changes",
+			ErrInvalidHeader, number)
+	}
+
+	verified := &backend.Snapshot{Epoch: t.epoch, Number: number, Hash: header.Hash(), ValSet: next}
+	if err = t.store.Save(verified); err != nil {
+		return err
+	}
+	t.snap = verified
+	return nil
+}
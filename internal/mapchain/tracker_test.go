@@ -0,0 +1,216 @@
+package mapchain
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	"github.com/mapprotocol/atlas/consensus/istanbul/backend"
+	istanbulCore "github.com/mapprotocol/atlas/consensus/istanbul/core"
+	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+)
+
+const testEpoch = 100
+
+type testValidator struct {
+	data istanbul.ValidatorData
+	sk   *blscrypto.SecretKey
+}
+
+func newTestValidators(t *testing.T, n int, offset int) []testValidator {
+	ret := make([]testValidator, 0, n)
+	for i := 0; i < n; i++ {
+		pk, sk, err := blscrypto.GenKeyPair(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		v := testValidator{sk: sk}
+		v.data.Address = common.BigToAddress(big.NewInt(int64(offset + i + 1)))
+		copy(v.data.BLSPublicKey[:], pk.Marshal())
+		ret = append(ret, v)
+	}
+	return ret
+}
+
+func newTestSet(vals []testValidator) istanbul.ValidatorSet {
+	data := make([]istanbul.ValidatorData, 0, len(vals))
+	for _, v := range vals {
+		data = append(data, v.data)
+	}
+	return validator.NewSet(data)
+}
+
+// newTestHeader seals the header by the signers of the validators, with the validator changes in its extra
+func newTestHeader(t *testing.T, number uint64, vals []testValidator, signers []int, removed *big.Int, added []testValidator) *types.Header {
+	extra := &types.IstanbulExtra{
+		AddedValidators:             []common.Address{},
+		AddedValidatorsPublicKeys:   []blscrypto.SerializedPublicKey{},
+		AddedValidatorsG1PublicKeys: []blscrypto.SerializedG1PublicKey{},
+		RemovedValidators:           removed,
+		Seal:                        []byte{},
+		AggregatedSeal:              types.IstanbulAggregatedSeal{Bitmap: new(big.Int), Signature: []byte{}, Round: new(big.Int)},
+		ParentAggregatedSeal:        types.IstanbulAggregatedSeal{Bitmap: new(big.Int), Signature: []byte{}, Round: new(big.Int)},
+	}
+	for _, v := range added {
+		extra.AddedValidators = append(extra.AddedValidators, v.data.Address)
+		extra.AddedValidatorsPublicKeys = append(extra.AddedValidatorsPublicKeys, v.data.BLSPublicKey)
+		extra.AddedValidatorsG1PublicKeys = append(extra.AddedValidatorsG1PublicKeys, v.data.BLSG1PublicKey)
+	}
+	header := &types.Header{Number: new(big.Int).SetUint64(number), Time: number}
+	setExtra(t, header, extra)
+
+	round := big.NewInt(1)
+	msg := istanbulCore.PrepareCommittedSeal(header.Hash(), round)
+	sigs := make([]*blscrypto.UnsafeSignature, 0, len(signers))
+	bitmap := new(big.Int)
+	for _, i := range signers {
+		sig, err := blscrypto.UnsafeSign(vals[i].sk, msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+		sigs = append(sigs, sig)
+		bitmap.SetBit(bitmap, i, 1)
+	}
+	agg, err := blscrypto.UnsafeBatch(sigs...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	extra.AggregatedSeal = types.IstanbulAggregatedSeal{Bitmap: bitmap, Signature: agg.Marshal(), Round: round}
+	setExtra(t, header, extra)
+	return header
+}
+
+func setExtra(t *testing.T, header *types.Header, extra *types.IstanbulExtra) {
+	payload, err := rlp.EncodeToBytes(extra)
+	if err != nil {
+		t.Fatal(err)
+	}
+	header.Extra = append(make([]byte, types.IstanbulExtraVanity), payload...)
+}
+
+// testClient is a node with the validators changing only at the epoch headers
+type testClient struct {
+	headers map[uint64]*types.Header
+	sets    map[uint64]istanbul.ValidatorSet // validators after the epoch header
+}
+
+func (c *testClient) MAPHeaderByNumber(_ context.Context, number *big.Int) (*types.Header, error) {
+	if h, ok := c.headers[number.Uint64()]; ok {
+		return h, nil
+	}
+	return nil, fmt.Errorf("no block %s", number)
+}
+
+func (c *testClient) GetSnapshot(_ context.Context, number *big.Int) (*backend.Snapshot, error) {
+	epochBlock := number.Uint64() / testEpoch * testEpoch
+	set, ok := c.sets[epochBlock]
+	if !ok {
+		return nil, fmt.Errorf("no snapshot of block %s", number)
+	}
+	return &backend.Snapshot{Epoch: testEpoch, Number: epochBlock, ValSet: set.Copy()}, nil
+}
+
+// newTestChain has 4 validators in epoch 1, the second is replaced at block 200 and the first is removed at 300
+func newTestChain(t *testing.T) (*testClient, [][]testValidator) {
+	v1 := newTestValidators(t, 4, 0)
+	added := newTestValidators(t, 1, 4)
+	v2 := []testValidator{v1[0], v1[2], v1[3], added[0]}
+	v3 := v2[1:]
+
+	c := &testClient{headers: map[uint64]*types.Header{}, sets: map[uint64]istanbul.ValidatorSet{}}
+	c.sets[100] = newTestSet(v1)
+	c.headers[200] = newTestHeader(t, 200, v1, []int{0, 1, 2}, big.NewInt(2), added)
+	c.sets[200] = newTestSet(v2)
+	c.headers[300] = newTestHeader(t, 300, v2, []int{1, 2, 3}, big.NewInt(1), nil)
+	c.sets[300] = newTestSet(v3)
+	c.headers[400] = newTestHeader(t, 400, v3, []int{0, 1}, new(big.Int), nil)
+	c.sets[400] = newTestSet(v3)
+	return c, [][]testValidator{v1, v2, v3}
+}
+
+func newTestTracker(t *testing.T, c Client) *ValidatorTracker {
+	store, err := NewStore(t.TempDir(), 22776, "0x01", "maintainer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tracker, err := NewValidatorTracker(c, store, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tracker
+}
+
+func TestValidatorTracker(t *testing.T) {
+	c, _ := newTestChain(t)
+	tracker := newTestTracker(t, c)
+	if err := tracker.Verify(context.Background(), c.headers[200]); err != nil {
+		t.Fatal(err)
+	}
+	// the header of 300 is followed from the node
+	if err := tracker.Verify(context.Background(), c.headers[400]); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.Tracked() != 400 {
+		t.Fatalf("tracked %d, want 400", tracker.Tracked())
+	}
+
+	// tracking goes on from the store after a restart
+	restarted, err := NewValidatorTracker(c, tracker.store, testEpoch)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restarted.Tracked() != 400 || !SameValidators(restarted.snap.ValSet, c.sets[400]) {
+		t.Fatal("tracked validators are not restored")
+	}
+	if err = restarted.Verify(context.Background(), c.headers[400]); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidatorTrackerInvalid(t *testing.T) {
+	cases := map[string]func(c *testClient, vals [][]testValidator){
+		"no quorum": func(c *testClient, vals [][]testValidator) {
+			c.headers[200] = newTestHeader(t, 200, vals[0], []int{0, 1}, big.NewInt(2), vals[1][3:])
+		},
+		"signer out of the set": func(c *testClient, vals [][]testValidator) {
+			c.headers[200] = newTestHeader(t, 200, vals[1], []int{0, 1, 2, 3}, big.NewInt(2), vals[1][3:])
+		},
+		"bitmap": func(c *testClient, vals [][]testValidator) {
+			c.headers[200] = newTestHeader(t, 200, vals[0], []int{0, 1, 2}, big.NewInt(2), vals[1][3:])
+			extra, _ := types.ExtractIstanbulExtra(c.headers[200])
+			extra.AggregatedSeal.Bitmap.SetBit(extra.AggregatedSeal.Bitmap, 3, 1)
+			setExtra(t, c.headers[200], extra)
+		},
+		"header": func(c *testClient, vals [][]testValidator) {
+			c.headers[200].Time++
+		},
+		"validator changes": func(c *testClient, vals [][]testValidator) {
+			c.sets[200] = newTestSet(vals[0])
+		},
+		"node validators": func(c *testClient, vals [][]testValidator) {
+			c.sets[100] = newTestSet(vals[1])
+			c.sets[200] = newTestSet(vals[1])
+			c.headers[300] = newTestHeader(t, 300, vals[1], []int{1, 2, 3}, big.NewInt(1), nil)
+		},
+	}
+	for name, tamper := range cases {
+		c, vals := newTestChain(t)
+		tracker := newTestTracker(t, c)
+		// the tracker is bootstrapped at 100 and the chain is tampered after it
+		tracker.snap = &backend.Snapshot{Epoch: testEpoch, Number: 100, ValSet: newTestSet(vals[0])}
+		tamper(c, vals)
+		if err := tracker.Verify(context.Background(), c.headers[300]); !errors.Is(err, ErrInvalidHeader) {
+			t.Fatalf("%s: expected invalid header, got %v", name, err)
+		}
+		if tracker.Tracked() != 100 {
+			t.Fatalf("%s: invalid header is tracked", name)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package mapchain
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/mapprotocol/atlas/consensus/istanbul"
+	istanbulCore "github.com/mapprotocol/atlas/consensus/istanbul/core"
+	"github.com/mapprotocol/atlas/core/types"
+	blscrypto "github.com/mapprotocol/atlas/helper/bls"
+)
+
+// ErrInvalidHeader is returned when a header of map does not verify against the tracked validator set
+var ErrInvalidHeader = errors.New("invalid map header")
+
+// VerifySeal checks the aggregated seal of the header is signed by a quorum of the validators
+func VerifySeal(header *types.Header, extra *types.IstanbulExtra, validators istanbul.ValidatorSet) error {
+	seal := extra.AggregatedSeal
+	if seal.Bitmap == nil || seal.Round == nil {
+		return fmt.Errorf("%w: block %s has no aggregated seal", ErrInvalidHeader, header.Number)
+	}
+	if seal.Bitmap.BitLen() > validators.Size() {
+		return fmt.Errorf("%w: seal bitmap of block %s has %d bits for %d validators", ErrInvalidHeader,
+			header.Number, seal.Bitmap.BitLen(), validators.Size())
+	}
+	publicKeys := make([]blscrypto.SerializedPublicKey, 0, validators.Size())
+	for i := 0; i < validators.Size(); i++ {
+		if seal.Bitmap.Bit(i) == 1 {
+			publicKeys = append(publicKeys, validators.GetByIndex(uint64(i)).BLSPublicKey())
+		}
+	}
+	if len(publicKeys) < validators.MinQuorumSize() {
+		return fmt.Errorf("%w: block %s is sealed by %d validators, quorum is %d", ErrInvalidHeader,
+			header.Number, len(publicKeys), validators.MinQuorumSize())
+	}
+	msg := istanbulCore.PrepareCommittedSeal(header.Hash(), seal.Round)
+	err := blscrypto.CryptoType().VerifyAggregatedSignature(publicKeys, msg, []byte{}, seal.Signature, false, false)
+	if err != nil {
+		return fmt.Errorf("%w: aggregated seal of block %s: %v", ErrInvalidHeader, header.Number, err)
+	}
+	return nil
+}
+
+// VerifyHeader checks the seal of the epoch header with the validators of the epoch before it, and returns the
+// validators of the next epoch with the validator changes of the header applied
+func VerifyHeader(header *types.Header, validators istanbul.ValidatorSet) (istanbul.ValidatorSet, error) {
+	extra, err := types.ExtractIstanbulExtra(header)
+	if err != nil {
+		return nil, fmt.Errorf("%w: extra of block %s: %v", ErrInvalidHeader, header.Number, err)
+	}
+	if err = VerifySeal(header, extra, validators); err != nil {
+		return nil, err
+	}
+
+	added, err := istanbul.CombineIstanbulExtraToValidatorData(extra.AddedValidators,
+		extra.AddedValidatorsPublicKeys, extra.AddedValidatorsG1PublicKeys)
+	if err != nil {
+		return nil, fmt.Errorf("%w: added validators of block %s: %v", ErrInvalidHeader, header.Number, err)
+	}
+	next := validators.Copy()
+	removed := extra.RemovedValidators
+	if removed == nil {
+		removed = new(big.Int)
+	}
+	if !next.RemoveValidators(removed) {
+		return nil, fmt.Errorf("%w: block %s removes validators %s of %d", ErrInvalidHeader, header.Number,
+			removed.Text(2), validators.Size())
+	}
+	if !next.AddValidators(added) {
+		return nil, fmt.Errorf("%w: block %s adds validators already in the set", ErrInvalidHeader, header.Number)
+	}
+	return next, nil
+}
+
+// SameValidators reports whether the sets have the same validators in the same order
+func SameValidators(a, b istanbul.ValidatorSet) bool {
+	if a.Size() != b.Size() {
+		return false
+	}
+	for i := 0; i < a.Size(); i++ {
+		va, vb := a.GetByIndex(uint64(i)), b.GetByIndex(uint64(i))
+		if va.Address() != vb.Address() || va.BLSPublicKey() != vb.BLSPublicKey() ||
+			va.BLSG1PublicKey() != vb.BLSG1PublicKey() {
+			return false
+		}
+	}
+	return true
+}
@@ -5,14 +5,13 @@ import (
 	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethdb/memorydb"
-	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/trie"
-	"github.com/mapprotocol/compass/internal/tx"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
 	"math/big"
 )
@@ -84,20 +83,28 @@ type ReceiptProof struct {
 	Proof     [][]byte
 }
 
-func GetProof(client *ethclient.Client, latestBlock *big.Int, log *types.Log, method string, fId msg.ChainId) ([]byte, error) {
-	txsHash, err := tx.GetTxsHashByBlockNumber(client, latestBlock)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
-	}
-	receipts, err := tx.GetReceiptsByTxsHash(client, txsHash)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-	}
+func init() {
+	iproof.Register(chains.Matic, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		return &Builder{client: cfg.Client, fId: cfg.Id}, nil
+	})
+}
 
+// Builder assembles the proof with ConfirmsOfMatic headers starting at the block of the log
+type Builder struct {
+	client *ethclient.Client
+	fId    msg.ChainId
+}
+
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return iproof.Receipts(b.client, number)
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	latestBlock := new(big.Int).SetUint64(log.BlockNumber)
 	headers := make([]*types.Header, mapprotocol.ConfirmsOfMatic.Int64())
 	for i := 0; i < int(mapprotocol.ConfirmsOfMatic.Int64()); i++ {
 		headerHeight := new(big.Int).Add(latestBlock, new(big.Int).SetInt64(int64(i)))
-		tmp, err := client.HeaderByNumber(context.Background(), headerHeight)
+		tmp, err := b.client.HeaderByNumber(ctx, headerHeight)
 		if err != nil {
 			return nil, fmt.Errorf("getHeader failed, err is %v", err)
 		}
@@ -108,17 +115,17 @@ func GetProof(client *ethclient.Client, latestBlock *big.Int, log *types.Log, me
 	for _, h := range headers {
 		mHeaders = append(mHeaders, ConvertHeader(h))
 	}
-	return AssembleProof(mHeaders, *log, fId, receipts, method)
+	return AssembleProof(mHeaders, *log, b.fId, receipts, iproof.Method(log.Topics[0]))
 }
 
-func AssembleProof(headers []BlockHeader, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) ([]byte, error) {
+func AssembleProof(headers []BlockHeader, log types.Log, fId msg.ChainId, receipts []*types.Receipt, method string) (*iproof.Proof, error) {
 	txIndex := log.TxIndex
 	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
 	if err != nil {
 		return nil, err
 	}
 
-	proof, err := getProof(receipts, txIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -147,29 +154,16 @@ func AssembleProof(headers []BlockHeader, log types.Log, fId msg.ChainId, receip
 		return nil, err
 	}
 
-	return pack, nil
-}
-
-func getProof(receipts []*types.Receipt, txIndex uint) ([][]byte, error) {
-	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
-	if err != nil {
-		return nil, err
-	}
-
-	tr = utils.DeriveTire(receipts, tr)
-	ns := light.NewNodeSet()
-	key, err := rlp.EncodeToBytes(txIndex)
+	encReceipt, err := iproof.EncodeReceipt(receipt)
 	if err != nil {
 		return nil, err
 	}
-	if err = tr.Prove(key, 0, ns); err != nil {
-		return nil, err
-	}
-
-	proof := make([][]byte, 0, len(ns.NodeList()))
-	for _, v := range ns.NodeList() {
-		proof = append(proof, v)
-	}
-
-	return proof, nil
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: common.BytesToHash(headers[0].ReceiptsRoot),
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
 }
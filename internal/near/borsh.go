@@ -31,39 +31,74 @@ const (
 func Borshify(block client.LightClientBlockView) []byte {
 	var (
 		buf                bytes.Buffer
-		innerLite          bytes.Buffer
-		nextBps            bytes.Buffer
 		littleEndian       bytes.Buffer
 		approvalsAfterNext bytes.Buffer
 	)
 
 	buf.Write(MustBase58Decode(block.PrevBlockHash.String()))
 	buf.Write(MustBase58Decode(block.NextBlockInnerHash.String()))
+	buf.Write(borshInnerLite(&block.InnerLite))
+	buf.Write(MustBase58Decode(block.InnerRestHash.String()))
+	buf.Write([]byte{1})
+	buf.Write(borshNextBps(block.NextBps))
 
-	MustToLittleEndian(&littleEndian, block.InnerLite.Height)
+	littleEndian.Reset()
+	MustToLittleEndian(&littleEndian, int64(len(block.ApprovalsAfterNext)))
+	buf.Write(littleEndian.Next(4))
+	for _, sign := range block.ApprovalsAfterNext {
+		var aan bytes.Buffer
+		if sign == nil {
+			aan.Write([]byte{0})
+		} else {
+			aan.Write([]byte{1})
+			if sign.Type == signature.SignatureTypeED25519 {
+				aan.Write([]byte{0})
+			} else {
+				aan.Write([]byte{1})
+			}
+			aan.Write(MustBase58Decode(sign.Value))
+		}
+		approvalsAfterNext.Write(aan.Bytes())
+	}
+	buf.Write(approvalsAfterNext.Bytes())
+
+	return buf.Bytes()
+}
+
+func borshInnerLite(inner *client.BlockHeaderInnerLiteView) []byte {
+	var (
+		innerLite    bytes.Buffer
+		littleEndian bytes.Buffer
+	)
+
+	MustToLittleEndian(&littleEndian, inner.Height)
 	innerLite.Write(littleEndian.Bytes())
 
-	innerLite.Write(MustBase58Decode(block.InnerLite.EpochID.String()))
-	innerLite.Write(MustBase58Decode(block.InnerLite.NextEpochId.String()))
-	innerLite.Write(MustBase58Decode(block.InnerLite.PrevStateRoot.String()))
-	innerLite.Write(MustBase58Decode(block.InnerLite.OutcomeRoot.String()))
+	innerLite.Write(MustBase58Decode(inner.EpochID.String()))
+	innerLite.Write(MustBase58Decode(inner.NextEpochId.String()))
+	innerLite.Write(MustBase58Decode(inner.PrevStateRoot.String()))
+	innerLite.Write(MustBase58Decode(inner.OutcomeRoot.String()))
 
 	littleEndian.Reset()
-	MustToLittleEndian(&littleEndian, block.InnerLite.Timestamp)
+	MustToLittleEndian(&littleEndian, inner.Timestamp)
 	innerLite.Write(littleEndian.Bytes())
 
-	innerLite.Write(MustBase58Decode(block.InnerLite.NextBpHash.String()))
-	innerLite.Write(MustBase58Decode(block.InnerLite.BlockMerkleRoot.String()))
-	buf.Write(innerLite.Bytes())
+	innerLite.Write(MustBase58Decode(inner.NextBpHash.String()))
+	innerLite.Write(MustBase58Decode(inner.BlockMerkleRoot.String()))
+	return innerLite.Bytes()
+}
 
-	buf.Write(MustBase58Decode(block.InnerRestHash.String()))
-	buf.Write([]byte{1})
+// borshNextBps is the borsh of the block producers with the length of them
+func borshNextBps(bps []client.ValidatorStakeWithVersion) []byte {
+	var (
+		nextBps      bytes.Buffer
+		littleEndian bytes.Buffer
+	)
 
-	littleEndian.Reset()
-	MustToLittleEndian(&littleEndian, int64(len(block.NextBps)))
-	buf.Write(littleEndian.Next(4))
+	MustToLittleEndian(&littleEndian, int64(len(bps)))
+	nextBps.Write(littleEndian.Next(4))
 
-	for _, bp := range block.NextBps {
+	for _, bp := range bps {
 		var nextBp bytes.Buffer
 		if bp.ValidatorStakeStructVersion == Version2 {
 			nextBp.Write([]byte{ValidatorStakeV2})
@@ -90,29 +125,7 @@ func Borshify(block client.LightClientBlockView) []byte {
 		nextBp.Write(reverse16(stake.Bytes()))
 		nextBps.Write(nextBp.Bytes())
 	}
-	buf.Write(nextBps.Bytes())
-
-	littleEndian.Reset()
-	MustToLittleEndian(&littleEndian, int64(len(block.ApprovalsAfterNext)))
-	buf.Write(littleEndian.Next(4))
-	for _, sign := range block.ApprovalsAfterNext {
-		var aan bytes.Buffer
-		if sign == nil {
-			aan.Write([]byte{0})
-		} else {
-			aan.Write([]byte{1})
-			if sign.Type == signature.SignatureTypeED25519 {
-				aan.Write([]byte{0})
-			} else {
-				aan.Write([]byte{1})
-			}
-			aan.Write(MustBase58Decode(sign.Value))
-		}
-		approvalsAfterNext.Write(aan.Bytes())
-	}
-	buf.Write(approvalsAfterNext.Bytes())
-
-	return buf.Bytes()
+	return nextBps.Bytes()
 }
 
 func BorshifyOutcomeProof(proof client.RpcLightClientExecutionProofResponse) ([]byte, error) {
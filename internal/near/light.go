@@ -0,0 +1,97 @@
+package near
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/types/hash"
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+	"github.com/mapprotocol/near-api-go/pkg/types/signature"
+	"github.com/mr-tron/base58"
+)
+
+// ErrInvalidLightBlock is returned when a light client block does not verify against the tracked block producers
+var ErrInvalidLightBlock = errors.New("invalid light client block")
+
+// LightBlockHash is the hash of the block of the light client block,
+// sha256(sha256(sha256(inner_lite) ++ inner_rest_hash) ++ prev_block_hash)
+func LightBlockHash(block *client.LightClientBlockView) hash.CryptoHash {
+	innerLite := sha256.Sum256(borshInnerLite(&block.InnerLite))
+	inner := sha256.Sum256(append(innerLite[:], block.InnerRestHash[:]...))
+	return sha256.Sum256(append(inner[:], block.PrevBlockHash[:]...))
+}
+
+// approvalMessage is the endorsement of the block after the next one, which the approvals after next sign
+func approvalMessage(block *client.LightClientBlockView) []byte {
+	current := LightBlockHash(block)
+	next := sha256.Sum256(append(block.NextBlockInnerHash[:], current[:]...))
+	var msg bytes.Buffer
+	msg.WriteByte(0) // ApprovalInner::Endorsement
+	msg.Write(next[:])
+	MustToLittleEndian(&msg, block.InnerLite.Height+2)
+	return msg.Bytes()
+}
+
+// ValidateLightBlock checks the approvals of the light client block are signed by the block producers of its epoch
+// holding more than two thirds of the stake, and the next block producers it carries are the ones of next_bp_hash
+func ValidateLightBlock(block *client.LightClientBlockView, bps []client.ValidatorStakeWithVersion) error {
+	height := block.InnerLite.Height
+	if len(bps) == 0 {
+		return fmt.Errorf("%w: no block producers of block %d", ErrInvalidLightBlock, height)
+	}
+	msg := approvalMessage(block)
+	total, approved := new(big.Int), new(big.Int)
+	for i, bp := range bps {
+		if i >= len(block.ApprovalsAfterNext) {
+			break
+		}
+		stake, ok := new(big.Int).SetString(bp.Stake.String(), 10)
+		if !ok {
+			return fmt.Errorf("%w: stake %s of %s", ErrInvalidLightBlock, bp.Stake.String(), bp.AccountID)
+		}
+		total.Add(total, stake)
+		sig := block.ApprovalsAfterNext[i]
+		if sig == nil {
+			continue
+		}
+		if err := verifyApproval(bp, sig, msg); err != nil {
+			return fmt.Errorf("%w: approval of %s to block %d: %v", ErrInvalidLightBlock, bp.AccountID, height, err)
+		}
+		approved.Add(approved, stake)
+	}
+	// approved > 2/3 total
+	if new(big.Int).Mul(approved, big.NewInt(3)).Cmp(new(big.Int).Mul(total, big.NewInt(2))) <= 0 {
+		return fmt.Errorf("%w: block %d is approved by %s of %s stake", ErrInvalidLightBlock, height, approved, total)
+	}
+
+	if len(block.NextBps) == 0 {
+		return fmt.Errorf("%w: block %d has no next block producers", ErrInvalidLightBlock, height)
+	}
+	if h := sha256.Sum256(borshNextBps(block.NextBps)); h != block.InnerLite.NextBpHash {
+		return fmt.Errorf("%w: next block producers of block %d are not of next_bp_hash", ErrInvalidLightBlock, height)
+	}
+	return nil
+}
+
+func verifyApproval(bp client.ValidatorStakeWithVersion, sig *signature.Base58Signature, msg []byte) error {
+	if bp.PublicKey.Type != key.KeyTypeED25519 || sig.Type != signature.SignatureTypeED25519 {
+		return fmt.Errorf("key %s and signature %s are not ed25519", bp.PublicKey.Type, sig.Type)
+	}
+	pk, err := base58.Decode(bp.PublicKey.Value)
+	if err != nil || len(pk) != ed25519.PublicKeySize {
+		return fmt.Errorf("invalid public key %s", bp.PublicKey.Value)
+	}
+	raw, err := base58.Decode(sig.Value)
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("invalid signature %s", sig.Value)
+	}
+	if !ed25519.Verify(pk, msg, raw) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
@@ -0,0 +1,213 @@
+package near
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/client/block"
+	"github.com/mapprotocol/near-api-go/pkg/types"
+	"github.com/mapprotocol/near-api-go/pkg/types/hash"
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+	"github.com/mapprotocol/near-api-go/pkg/types/signature"
+	"github.com/mr-tron/base58"
+)
+
+type testProducer struct {
+	bp client.ValidatorStakeWithVersion
+	sk ed25519.PrivateKey
+}
+
+func newTestProducers(t *testing.T, stakes ...int) []testProducer {
+	ret := make([]testProducer, 0, len(stakes))
+	for i, s := range stakes {
+		pk, sk, err := ed25519.GenerateKey(nil)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var stake types.Balance
+		if err = json.Unmarshal([]byte(fmt.Sprintf(`"%d"`, s)), &stake); err != nil {
+			t.Fatal(err)
+		}
+		ret = append(ret, testProducer{
+			bp: client.ValidatorStakeWithVersion{
+				AccountID:                   fmt.Sprintf("bp%d.near", i),
+				PublicKey:                   key.Base58PublicKey{Type: key.KeyTypeED25519, Value: base58.Encode(pk)},
+				Stake:                       stake,
+				ValidatorStakeStructVersion: "V1",
+			},
+			sk: sk,
+		})
+	}
+	return ret
+}
+
+func producers(ps []testProducer) []client.ValidatorStakeWithVersion {
+	ret := make([]client.ValidatorStakeWithVersion, 0, len(ps))
+	for _, p := range ps {
+		ret = append(ret, p.bp)
+	}
+	return ret
+}
+
+// newTestLightBlock is a light client block of the epoch approved by the signers of the producers
+func newTestLightBlock(height uint64, epoch, nextEpoch hash.CryptoHash, ps []testProducer, signers []int,
+	next []testProducer) *client.LightClientBlockView {
+	blk := &client.LightClientBlockView{
+		PrevBlockHash:      hash.NewCryptoHash([]byte(fmt.Sprintf("prev%d", height))),
+		NextBlockInnerHash: hash.NewCryptoHash([]byte(fmt.Sprintf("next%d", height))),
+		InnerLite: client.BlockHeaderInnerLiteView{
+			Height:          height,
+			EpochID:         epoch,
+			NextEpochId:     nextEpoch,
+			PrevStateRoot:   hash.NewCryptoHash([]byte("state")),
+			OutcomeRoot:     hash.NewCryptoHash([]byte("outcome")),
+			Timestamp:       1660000000000000000 + height,
+			BlockMerkleRoot: hash.NewCryptoHash([]byte("merkle")),
+		},
+		InnerRestHash: hash.NewCryptoHash([]byte("rest")),
+		NextBps:       producers(next),
+	}
+	blk.InnerLite.NextBpHash = sha256.Sum256(borshNextBps(blk.NextBps))
+	msg := approvalMessage(blk)
+	blk.ApprovalsAfterNext = make([]*signature.Base58Signature, len(ps))
+	for _, i := range signers {
+		blk.ApprovalsAfterNext[i] = &signature.Base58Signature{
+			Type:  signature.SignatureTypeED25519,
+			Value: base58.Encode(ed25519.Sign(ps[i].sk, msg)),
+		}
+	}
+	return blk
+}
+
+func TestApprovalMessage(t *testing.T) {
+	blk := newTestLightBlock(100, hash.NewCryptoHash([]byte("e")), hash.NewCryptoHash([]byte("n")), nil, nil, nil)
+	inner := make([]byte, 0, 208)
+	inner = append(inner, 100, 0, 0, 0, 0, 0, 0, 0)
+	inner = append(inner, blk.InnerLite.EpochID[:]...)
+	inner = append(inner, blk.InnerLite.NextEpochId[:]...)
+	inner = append(inner, blk.InnerLite.PrevStateRoot[:]...)
+	inner = append(inner, blk.InnerLite.OutcomeRoot[:]...)
+	inner = append(inner, make([]byte, 8)...)
+	binary.LittleEndian.PutUint64(inner[136:], blk.InnerLite.Timestamp)
+	inner = append(inner, blk.InnerLite.NextBpHash[:]...)
+	inner = append(inner, blk.InnerLite.BlockMerkleRoot[:]...)
+	if len(inner) != 208 {
+		t.Fatalf("inner lite is %d bytes", len(inner))
+	}
+
+	h1 := sha256.Sum256(inner)
+	h2 := sha256.Sum256(append(h1[:], blk.InnerRestHash[:]...))
+	current := sha256.Sum256(append(h2[:], blk.PrevBlockHash[:]...))
+	if LightBlockHash(blk) != current {
+		t.Fatal("light block hash mismatch")
+	}
+	next := sha256.Sum256(append(blk.NextBlockInnerHash[:], current[:]...))
+	want := append(append([]byte{0}, next[:]...), 102, 0, 0, 0, 0, 0, 0, 0)
+	if got := approvalMessage(blk); string(got) != string(want) {
+		t.Fatalf("approval message is %x, want %x", got, want)
+	}
+}
+
+func TestValidateLightBlock(t *testing.T) {
+	ps := newTestProducers(t, 10, 20, 30, 40)
+	epoch, nextEpoch := hash.NewCryptoHash([]byte("e1")), hash.NewCryptoHash([]byte("e2"))
+	blk := newTestLightBlock(100, epoch, nextEpoch, ps, []int{1, 2, 3}, ps)
+	if err := ValidateLightBlock(blk, producers(ps)); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := map[string]func(blk *client.LightClientBlockView){
+		// 60 of 100 is not more than two thirds
+		"stake": func(blk *client.LightClientBlockView) {
+			*blk = *newTestLightBlock(100, epoch, nextEpoch, ps, []int{0, 1, 2}, ps)
+		},
+		"signature": func(blk *client.LightClientBlockView) {
+			blk.ApprovalsAfterNext[1] = blk.ApprovalsAfterNext[2]
+		},
+		"height":     func(blk *client.LightClientBlockView) { blk.InnerLite.Height++ },
+		"next inner": func(blk *client.LightClientBlockView) { blk.NextBlockInnerHash[0] ^= 1 },
+		"next bps": func(blk *client.LightClientBlockView) {
+			blk.NextBps = producers(ps[1:])
+		},
+		"no next bps": func(blk *client.LightClientBlockView) { blk.NextBps = nil },
+	}
+	for name, tamper := range cases {
+		b := *newTestLightBlock(100, epoch, nextEpoch, ps, []int{1, 2, 3}, ps)
+		tamper(&b)
+		if err := ValidateLightBlock(&b, producers(ps)); !errors.Is(err, ErrInvalidLightBlock) {
+			t.Fatalf("%s: expected invalid light block, got %v", name, err)
+		}
+	}
+}
+
+// testLightClient serves the last light client blocks of the epochs e1 to e4, the producers change every epoch
+type testLightClient struct {
+	details map[uint64]client.BlockView
+	next    map[hash.CryptoHash]*client.LightClientBlockView
+}
+
+func (c *testLightClient) BlockDetails(_ context.Context, bc block.BlockCharacteristic) (client.BlockView, error) {
+	params := map[string]interface{}{}
+	bc(params)
+	if d, ok := c.details[uint64(params["block_id"].(types.BlockHeight))]; ok {
+		return d, nil
+	}
+	return client.BlockView{}, fmt.Errorf("no block %v", params["block_id"])
+}
+
+func (c *testLightClient) NextLightClientBlock(_ context.Context, last hash.CryptoHash) (client.LightClientBlockView, error) {
+	if blk, ok := c.next[last]; ok {
+		return *blk, nil
+	}
+	return client.LightClientBlockView{}, fmt.Errorf("no light client block after %s", last)
+}
+
+func newTestLightClient(t *testing.T) (*testLightClient, []*client.LightClientBlockView) {
+	epochs := []hash.CryptoHash{{}, {1}, {2}, {3}, {4}, {5}}
+	ps := [][]testProducer{nil, newTestProducers(t, 1, 1, 1), newTestProducers(t, 5, 5, 5), newTestProducers(t, 7, 7, 7),
+		newTestProducers(t, 9, 9, 9), newTestProducers(t, 3, 3, 3)}
+	blocks := make([]*client.LightClientBlockView, 5)
+	for i := 1; i <= 4; i++ {
+		blocks[i] = newTestLightBlock(uint64(i*100), epochs[i], epochs[i+1], ps[i], []int{0, 1, 2}, ps[i+1])
+	}
+
+	c := &testLightClient{details: map[uint64]client.BlockView{}, next: map[hash.CryptoHash]*client.LightClientBlockView{}}
+	c.details[200] = client.BlockView{Header: client.BlockHeaderView{Height: 200, EpochID: epochs[2], NextEpochID: epochs[3]}}
+	// the ids of the epochs are the hashes of the last blocks of the epochs two before them
+	c.next[epochs[2]] = blocks[1]
+	c.next[epochs[3]] = blocks[2]
+	c.next[LightBlockHash(blocks[2])] = blocks[3]
+	c.next[LightBlockHash(blocks[3])] = blocks[4]
+	return c, blocks
+}
+
+func TestBlockProducerTracker(t *testing.T) {
+	c, blocks := newTestLightClient(t)
+	tracker := NewBlockProducerTracker(c)
+	if err := tracker.Bootstrap(context.Background(), 200); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.head.InnerLite.Height != 200 {
+		t.Fatalf("bootstrapped at %d", tracker.head.InnerLite.Height)
+	}
+	// the block of e3 is followed from the node to verify the one of e4
+	if err := tracker.Verify(context.Background(), blocks[4]); err != nil {
+		t.Fatal(err)
+	}
+	if tracker.head.InnerLite.Height != 400 || len(tracker.bps) != 2 {
+		t.Fatalf("head %d with producers of %d epochs", tracker.head.InnerLite.Height, len(tracker.bps))
+	}
+
+	forged := *blocks[4]
+	forged.InnerLite.OutcomeRoot[0] ^= 1
+	if err := tracker.Verify(context.Background(), &forged); !errors.Is(err, ErrInvalidLightBlock) {
+		t.Fatalf("expected invalid light block, got %v", err)
+	}
+}
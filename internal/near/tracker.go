@@ -0,0 +1,130 @@
+package near
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/mapprotocol/near-api-go/pkg/client"
+	"github.com/mapprotocol/near-api-go/pkg/client/block"
+	"github.com/mapprotocol/near-api-go/pkg/types/hash"
+)
+
+// LightClient is what the tracker reads of a near node
+type LightClient interface {
+	BlockDetails(ctx context.Context, block block.BlockCharacteristic) (client.BlockView, error)
+	NextLightClientBlock(ctx context.Context, lastHash hash.CryptoHash) (client.LightClientBlockView, error)
+}
+
+// BlockProducerTracker keeps the block producers of the current and the next epoch, verified from a bootstrap on,
+// to check the light client blocks of the node before they are sent to the light client on map.
+type BlockProducerTracker struct {
+	client LightClient
+	head   *client.LightClientBlockView                           // the latest verified light client block
+	bps    map[hash.CryptoHash][]client.ValidatorStakeWithVersion // by epoch id
+}
+
+func NewBlockProducerTracker(c LightClient) *BlockProducerTracker {
+	return &BlockProducerTracker{
+		client: c,
+		bps:    make(map[hash.CryptoHash][]client.ValidatorStakeWithVersion),
+	}
+}
+
+func (t *BlockProducerTracker) Bootstrapped() bool {
+	return t.head != nil
+}
+
+// Bootstrap takes the block producers of the epoch of the block at the height, which should be one the light client
+// on map has already accepted. They come with the light client block of the epoch before it, which is trusted as the
+// node returns it, the light client block of the epoch of the block is verified by them and brings the next ones.
+func (t *BlockProducerTracker) Bootstrap(ctx context.Context, height uint64) error {
+	details, err := t.client.BlockDetails(ctx, block.BlockID(height))
+	if err != nil {
+		return err
+	}
+	epoch := details.Header.EpochID
+	// the id of an epoch is the hash of the last block of the epoch two before it, so the light client block
+	// after it is the last one of the epoch before, and the one after the next epoch id is of the epoch
+	prev, err := t.client.NextLightClientBlock(ctx, epoch)
+	if err != nil {
+		return err
+	}
+	if prev.InnerLite.NextEpochId != epoch {
+		return fmt.Errorf("%w: bootstrap block %d is not of the epoch before %s", ErrInvalidLightBlock,
+			prev.InnerLite.Height, epoch)
+	}
+	if len(prev.NextBps) == 0 || hash.NewCryptoHash(borshNextBps(prev.NextBps)) != prev.InnerLite.NextBpHash {
+		return fmt.Errorf("%w: block producers of bootstrap block %d are not of next_bp_hash", ErrInvalidLightBlock,
+			prev.InnerLite.Height)
+	}
+	current, err := t.client.NextLightClientBlock(ctx, details.Header.NextEpochID)
+	if err != nil {
+		return err
+	}
+	if current.InnerLite.EpochID != epoch {
+		return fmt.Errorf("%w: bootstrap block %d is not of the epoch %s", ErrInvalidLightBlock,
+			current.InnerLite.Height, epoch)
+	}
+	if err = ValidateLightBlock(&current, prev.NextBps); err != nil {
+		return err
+	}
+
+	t.bps = map[hash.CryptoHash][]client.ValidatorStakeWithVersion{epoch: prev.NextBps}
+	t.keep(&current)
+	return nil
+}
+
+// Verify checks the light client block with the block producers of its epoch, following the light client blocks of
+// the node up to it when they are not tracked yet, and keeps the next block producers the block carries.
+func (t *BlockProducerTracker) Verify(ctx context.Context, blk *client.LightClientBlockView) error {
+	if !t.Bootstrapped() {
+		return fmt.Errorf("block producer tracker is not bootstrapped")
+	}
+	bps, err := t.producers(ctx, blk.InnerLite.EpochID)
+	if err != nil {
+		return err
+	}
+	if err = ValidateLightBlock(blk, bps); err != nil {
+		return err
+	}
+	verified := *blk
+	t.keep(&verified)
+	return nil
+}
+
+func (t *BlockProducerTracker) producers(ctx context.Context, epoch hash.CryptoHash) ([]client.ValidatorStakeWithVersion, error) {
+	for {
+		if bps, ok := t.bps[epoch]; ok {
+			return bps, nil
+		}
+		next, err := t.client.NextLightClientBlock(ctx, LightBlockHash(t.head))
+		if err != nil {
+			return nil, err
+		}
+		if next.InnerLite.Height <= t.head.InnerLite.Height {
+			return nil, fmt.Errorf("block producers of epoch %s are not known by block %d", epoch, t.head.InnerLite.Height)
+		}
+		bps, ok := t.bps[next.InnerLite.EpochID]
+		if !ok {
+			return nil, fmt.Errorf("%w: block %d is of the untracked epoch %s", ErrInvalidLightBlock,
+				next.InnerLite.Height, next.InnerLite.EpochID)
+		}
+		if err = ValidateLightBlock(&next, bps); err != nil {
+			return nil, err
+		}
+		t.keep(&next)
+	}
+}
+
+// keep tracks the next block producers of the verified block, dropping the ones of the epochs before the head
+func (t *BlockProducerTracker) keep(blk *client.LightClientBlockView) {
+	t.bps[blk.InnerLite.NextEpochId] = blk.NextBps
+	if t.head == nil || blk.InnerLite.Height > t.head.InnerLite.Height {
+		t.head = blk
+	}
+	for epoch := range t.bps {
+		if epoch != t.head.InnerLite.EpochID && epoch != t.head.InnerLite.NextEpochId {
+			delete(t.bps, epoch)
+		}
+	}
+}
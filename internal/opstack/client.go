@@ -0,0 +1,89 @@
+package opstack
+
+import (
+	"context"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Client reads the blocks, receipts and state proofs of an op stack node, which the ethclient can not decode as
+// they have the deposit txs and the header fields after london
+type Client struct {
+	c *rpc.Client
+}
+
+func Dial(ctx context.Context, endpoint string) (*Client, error) {
+	c, err := rpc.DialContext(ctx, endpoint)
+	if err != nil {
+		return nil, err
+	}
+	return NewClient(c), nil
+}
+
+func NewClient(c *rpc.Client) *Client {
+	return &Client{c: c}
+}
+
+func (c *Client) Close() {
+	c.c.Close()
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number *big.Int) (*Header, error) {
+	var h *Header
+	err := c.c.CallContext(ctx, &h, "eth_getBlockByNumber", hexutil.EncodeBig(number), false)
+	if err == nil && h == nil {
+		err = ethereum.NotFound
+	}
+	return h, err
+}
+
+// BlockReceipts returns the receipts of all txs of the block, in the order of the receipt trie
+func (c *Client) BlockReceipts(ctx context.Context, number *big.Int) ([]*Receipt, error) {
+	var rs []*Receipt
+	err := c.c.CallContext(ctx, &rs, "eth_getBlockReceipts", hexutil.EncodeBig(number))
+	if err == nil && rs == nil {
+		err = ethereum.NotFound
+	}
+	return rs, err
+}
+
+// AccountResult is the result of eth_getProof
+type AccountResult struct {
+	Address      common.Address  `json:"address"`
+	AccountProof []hexutil.Bytes `json:"accountProof"`
+	Balance      *hexutil.Big    `json:"balance"`
+	CodeHash     common.Hash     `json:"codeHash"`
+	Nonce        hexutil.Uint64  `json:"nonce"`
+	StorageHash  common.Hash     `json:"storageHash"`
+	StorageProof []StorageResult `json:"storageProof"`
+}
+
+type StorageResult struct {
+	Key   string          `json:"key"`
+	Value *hexutil.Big    `json:"value"`
+	Proof []hexutil.Bytes `json:"proof"`
+}
+
+func (c *Client) GetProof(ctx context.Context, account common.Address, keys []common.Hash, number *big.Int) (*AccountResult, error) {
+	if keys == nil {
+		keys = []common.Hash{}
+	}
+	var res *AccountResult
+	err := c.c.CallContext(ctx, &res, "eth_getProof", account, keys, hexutil.EncodeBig(number))
+	if err == nil && res == nil {
+		err = ethereum.NotFound
+	}
+	return res, err
+}
+
+func bytesList(bs []hexutil.Bytes) [][]byte {
+	ret := make([][]byte, 0, len(bs))
+	for _, b := range bs {
+		ret = append(ret, b)
+	}
+	return ret
+}
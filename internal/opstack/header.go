@@ -0,0 +1,140 @@
+package opstack
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// Header is the header of an op stack block. The l2 blocks carry the fields added to the ethereum header after
+// london, which the geth of this module does not know, and the block hash is only right with all of them.
+type Header struct {
+	ParentHash       common.Hash
+	UncleHash        common.Hash
+	Coinbase         common.Address
+	Root             common.Hash
+	TxHash           common.Hash
+	ReceiptHash      common.Hash
+	Bloom            types.Bloom
+	Difficulty       *big.Int
+	Number           *big.Int
+	GasLimit         uint64
+	GasUsed          uint64
+	Time             uint64
+	Extra            []byte
+	MixDigest        common.Hash
+	Nonce            types.BlockNonce
+	BaseFee          *big.Int     `rlp:"optional"` // london
+	WithdrawalsHash  *common.Hash `rlp:"optional"` // canyon
+	BlobGasUsed      *uint64      `rlp:"optional"` // ecotone
+	ExcessBlobGas    *uint64      `rlp:"optional"` // ecotone
+	ParentBeaconRoot *common.Hash `rlp:"optional"` // ecotone
+	RequestsHash     *common.Hash `rlp:"optional"` // isthmus
+}
+
+// Hash is the keccak256 of the rlp of the header
+func (h *Header) Hash() common.Hash {
+	enc, _ := rlp.EncodeToBytes(h)
+	return crypto.Keccak256Hash(enc)
+}
+
+type rpcHeader struct {
+	Hash             common.Hash      `json:"hash"`
+	ParentHash       common.Hash      `json:"parentHash"`
+	UncleHash        common.Hash      `json:"sha3Uncles"`
+	Coinbase         common.Address   `json:"miner"`
+	Root             common.Hash      `json:"stateRoot"`
+	TxHash           common.Hash      `json:"transactionsRoot"`
+	ReceiptHash      common.Hash      `json:"receiptsRoot"`
+	Bloom            types.Bloom      `json:"logsBloom"`
+	Difficulty       *hexutil.Big     `json:"difficulty"`
+	Number           *hexutil.Big     `json:"number"`
+	GasLimit         hexutil.Uint64   `json:"gasLimit"`
+	GasUsed          hexutil.Uint64   `json:"gasUsed"`
+	Time             hexutil.Uint64   `json:"timestamp"`
+	Extra            hexutil.Bytes    `json:"extraData"`
+	MixDigest        common.Hash      `json:"mixHash"`
+	Nonce            types.BlockNonce `json:"nonce"`
+	BaseFee          *hexutil.Big     `json:"baseFeePerGas,omitempty"`
+	WithdrawalsHash  *common.Hash     `json:"withdrawalsRoot,omitempty"`
+	BlobGasUsed      *hexutil.Uint64  `json:"blobGasUsed,omitempty"`
+	ExcessBlobGas    *hexutil.Uint64  `json:"excessBlobGas,omitempty"`
+	ParentBeaconRoot *common.Hash     `json:"parentBeaconBlockRoot,omitempty"`
+	RequestsHash     *common.Hash     `json:"requestsHash,omitempty"`
+}
+
+// UnmarshalJSON decodes the header of eth_getBlockByNumber, and checks it hashes to the hash the node returns
+func (h *Header) UnmarshalJSON(input []byte) error {
+	var dec rpcHeader
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	if dec.Number == nil || dec.Difficulty == nil {
+		return errors.New("missing number or difficulty of header")
+	}
+	*h = Header{
+		ParentHash:       dec.ParentHash,
+		UncleHash:        dec.UncleHash,
+		Coinbase:         dec.Coinbase,
+		Root:             dec.Root,
+		TxHash:           dec.TxHash,
+		ReceiptHash:      dec.ReceiptHash,
+		Bloom:            dec.Bloom,
+		Difficulty:       dec.Difficulty.ToInt(),
+		Number:           dec.Number.ToInt(),
+		GasLimit:         uint64(dec.GasLimit),
+		GasUsed:          uint64(dec.GasUsed),
+		Time:             uint64(dec.Time),
+		Extra:            dec.Extra,
+		MixDigest:        dec.MixDigest,
+		Nonce:            dec.Nonce,
+		WithdrawalsHash:  dec.WithdrawalsHash,
+		BlobGasUsed:      (*uint64)(dec.BlobGasUsed),
+		ExcessBlobGas:    (*uint64)(dec.ExcessBlobGas),
+		ParentBeaconRoot: dec.ParentBeaconRoot,
+		RequestsHash:     dec.RequestsHash,
+	}
+	if dec.BaseFee != nil {
+		h.BaseFee = dec.BaseFee.ToInt()
+	}
+	if hash := h.Hash(); hash != dec.Hash {
+		return fmt.Errorf("header of block %s hashes to %s, not %s", h.Number, hash.Hex(), dec.Hash.Hex())
+	}
+	return nil
+}
+
+// MarshalJSON encodes the header like the node does
+func (h *Header) MarshalJSON() ([]byte, error) {
+	enc := rpcHeader{
+		Hash:             h.Hash(),
+		ParentHash:       h.ParentHash,
+		UncleHash:        h.UncleHash,
+		Coinbase:         h.Coinbase,
+		Root:             h.Root,
+		TxHash:           h.TxHash,
+		ReceiptHash:      h.ReceiptHash,
+		Bloom:            h.Bloom,
+		Difficulty:       (*hexutil.Big)(h.Difficulty),
+		Number:           (*hexutil.Big)(h.Number),
+		GasLimit:         hexutil.Uint64(h.GasLimit),
+		GasUsed:          hexutil.Uint64(h.GasUsed),
+		Time:             hexutil.Uint64(h.Time),
+		Extra:            h.Extra,
+		MixDigest:        h.MixDigest,
+		Nonce:            h.Nonce,
+		BaseFee:          (*hexutil.Big)(h.BaseFee),
+		WithdrawalsHash:  h.WithdrawalsHash,
+		BlobGasUsed:      (*hexutil.Uint64)(h.BlobGasUsed),
+		ExcessBlobGas:    (*hexutil.Uint64)(h.ExcessBlobGas),
+		ParentBeaconRoot: h.ParentBeaconRoot,
+		RequestsHash:     h.RequestsHash,
+	}
+	return json.Marshal(&enc)
+}
@@ -0,0 +1,189 @@
+package opstack
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/internal/proof/prooftest"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+type fixedSource struct {
+	output *Output
+}
+
+func (s *fixedSource) OutputAfter(context.Context, uint64) (*Output, error) {
+	return s.output, nil
+}
+
+func fixtureLog(t *testing.T, b *Builder, txIndex int) (*types.Log, []*types.Receipt) {
+	receipts, err := b.Receipts(context.Background(), big.NewInt(logBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(receipts) != 3 {
+		t.Fatalf("%d receipts, want 3", len(receipts))
+	}
+	if len(receipts[txIndex].Logs) == 0 {
+		return &types.Log{BlockNumber: logBlock, TxIndex: uint(txIndex), TxHash: receipts[txIndex].TxHash,
+			Topics: []common.Hash{prooftest.TransferOutTopic}}, receipts
+	}
+	return receipts[txIndex].Logs[0], receipts
+}
+
+func TestBuild(t *testing.T) {
+	c := replayServer(t)
+	for name, source := range map[string]OutputSource{
+		"oracle": NewOracleSource(c, oracleAddr),
+		"game":   NewGameSource(c, factoryAddr, nil),
+	} {
+		t.Run(name, func(t *testing.T) {
+			b := NewBuilder(NewClient(c), source, 0, 1)
+			log, receipts := fixtureLog(t, b, 1)
+			p, err := b.Build(context.Background(), log, receipts)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err = p.Verify(); err != nil {
+				t.Fatal(err)
+			}
+			if p.Method != mapprotocol.MethodOfTransferIn {
+				t.Fatalf("method %s", p.Method)
+			}
+		})
+	}
+}
+
+func TestBuildDepositLog(t *testing.T) {
+	c := replayServer(t)
+	b := NewBuilder(NewClient(c), NewOracleSource(c, oracleAddr), 0, 1)
+	log, receipts := fixtureLog(t, b, 0)
+	if _, err := b.Build(context.Background(), log, receipts); !errors.Is(err, iproof.ErrInvalidProof) {
+		t.Fatalf("got %v, want ErrInvalidProof", err)
+	}
+}
+
+func TestLink(t *testing.T) {
+	c := NewClient(replayServer(t))
+	ctx := context.Background()
+	header, err := c.HeaderByNumber(ctx, big.NewInt(logBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	source := NewOracleSource(c.c, oracleAddr)
+	output, err := source.OutputAfter(ctx, logBlock)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if output.Index.Uint64() != outputIndex || output.L2BlockNumber != outputBlock {
+		t.Fatalf("output %d at %d", output.Index, output.L2BlockNumber)
+	}
+
+	link, err := Link(ctx, c, source, header, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(link.AccountProof) == 0 || len(link.StorageProof) == 0 {
+		t.Fatal("no history storage proof")
+	}
+	// the block of the output is the latest block hash of the root
+	outHeader, err := c.HeaderByNumber(ctx, big.NewInt(outputBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	link, err = Link(ctx, c, &fixedSource{output: output}, outHeader, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if link.AccountProof != nil || link.StorageProof != nil {
+		t.Fatal("history storage proof for the output block")
+	}
+
+	// the node does not have the output proposed on l1
+	bad := *output
+	bad.Root = common.HexToHash("0x01")
+	if _, err = Link(ctx, c, &fixedSource{output: &bad}, header, 0); !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("got %v, want ErrInvalidOutput", err)
+	}
+	// the block is not in the history of the output block
+	other := *header
+	other.Extra = []byte("reorged")
+	if _, err = Link(ctx, c, &fixedSource{output: output}, &other, 0); !errors.Is(err, ErrInvalidOutput) {
+		t.Fatalf("got %v, want ErrInvalidOutput", err)
+	}
+	// the block is before isthmus
+	if _, err = Link(ctx, c, source, header, header.Time+1); !errors.Is(err, ErrNotInHistory) {
+		t.Fatalf("got %v, want ErrNotInHistory", err)
+	}
+}
+
+func TestInHistory(t *testing.T) {
+	header := &Header{Number: big.NewInt(logBlock), Time: 1000}
+	for _, c := range []struct {
+		outputBlock, isthmusTime uint64
+		ok                       bool
+	}{
+		{logBlock + 1, 1000, true},
+		{logBlock + HistoryServeWindow, 0, true},
+		{logBlock + HistoryServeWindow + 1, 0, false},
+		{logBlock - 1, 0, false},
+		{logBlock + 1, 1001, false},
+	} {
+		if err := InHistory(header, c.outputBlock, c.isthmusTime); (err == nil) != c.ok || (err != nil && !errors.Is(err, ErrNotInHistory)) {
+			t.Errorf("output block %d isthmus %d: %v", c.outputBlock, c.isthmusTime, err)
+		}
+	}
+}
+
+func TestOutputNotProposed(t *testing.T) {
+	c := replayServer(t)
+	if _, err := NewOracleSource(c, oracleAddr).OutputAfter(context.Background(), outputBlock+1); err != ethereum.NotFound {
+		t.Fatalf("got %v, want not found", err)
+	}
+	// the game at 120 is won by the challenger
+	if _, err := NewGameSource(c, factoryAddr, nil).OutputAfter(context.Background(), outputBlock+1); err != ethereum.NotFound {
+		t.Fatalf("got %v, want not found", err)
+	}
+	gameType := uint32(1)
+	if _, err := NewGameSource(c, factoryAddr, &gameType).OutputAfter(context.Background(), logBlock); err != ethereum.NotFound {
+		t.Fatalf("got %v, want not found", err)
+	}
+}
+
+func TestHeaderHash(t *testing.T) {
+	c := NewClient(replayServer(t))
+	header, err := c.HeaderByNumber(context.Background(), big.NewInt(logBlock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var h Header
+	enc := prooftest.CheckJSON(t, header, &h)
+	// a field the geth of this module does not know is dropped
+	prooftest.CheckDropped(t, enc, "requestsHash", "requestsRoot", &h)
+}
+
+func TestParseOpts(t *testing.T) {
+	for _, c := range []struct {
+		opts map[string]string
+		ok   bool
+	}{
+		{map[string]string{L1EndpointOpt: "http://l1", OutputOracleOpt: oracleAddr.Hex(), IsthmusTimeOpt: "0"}, true},
+		{map[string]string{L1EndpointOpt: "http://l1", DisputeGameFactoryOpt: factoryAddr.Hex(), GameTypeOpt: "0", IsthmusTimeOpt: "1746806401"}, true},
+		{map[string]string{L1EndpointOpt: "http://l1", OutputOracleOpt: oracleAddr.Hex()}, false},
+		{map[string]string{L1EndpointOpt: "http://l1", OutputOracleOpt: oracleAddr.Hex(), IsthmusTimeOpt: "x"}, false},
+		{map[string]string{OutputOracleOpt: oracleAddr.Hex()}, false},
+		{map[string]string{L1EndpointOpt: "http://l1"}, false},
+		{map[string]string{L1EndpointOpt: "http://l1", OutputOracleOpt: oracleAddr.Hex(), DisputeGameFactoryOpt: factoryAddr.Hex()}, false},
+		{map[string]string{L1EndpointOpt: "http://l1", DisputeGameFactoryOpt: factoryAddr.Hex(), GameTypeOpt: "x"}, false},
+	} {
+		if _, err := ParseOpts(c.opts); (err == nil) != c.ok {
+			t.Errorf("%v: %v", c.opts, err)
+		}
+	}
+}
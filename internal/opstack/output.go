@@ -0,0 +1,141 @@
+package opstack
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// HistoryServeWindow is the number of the block hashes kept by the history storage of eip-2935
+const HistoryServeWindow = 8191
+
+var (
+	// MessagePasser is the L2ToL1MessagePasser predeploy, its storage root is in the output root
+	MessagePasser = common.HexToAddress("0x4200000000000000000000000000000000000016")
+	// HistoryStorage keeps the hashes of the recent blocks in the state since isthmus, by eip-2935
+	HistoryStorage = common.HexToAddress("0x0000F90827F1C53a10cb7A02335B175320002935")
+)
+
+var (
+	// ErrInvalidOutput is returned when the blocks of the node do not match the output roots proposed on l1
+	ErrInvalidOutput = errors.New("invalid output root")
+	// ErrNotInHistory is returned for a block whose hash is not in the history storage of the output block, the
+	// blocks before isthmus and the ones more than HistoryServeWindow blocks before the output can not be proven
+	ErrNotInHistory = errors.New("block hash not in history storage")
+)
+
+// OutputRootProof is the preimage of an output root of version 0
+type OutputRootProof struct {
+	Version                  common.Hash
+	StateRoot                common.Hash
+	MessagePasserStorageRoot common.Hash
+	LatestBlockhash          common.Hash
+}
+
+// Root is keccak256(version ++ stateRoot ++ messagePasserStorageRoot ++ latestBlockhash)
+func (p *OutputRootProof) Root() common.Hash {
+	return crypto.Keccak256Hash(p.Version[:], p.StateRoot[:], p.MessagePasserStorageRoot[:], p.LatestBlockhash[:])
+}
+
+// Output is an output root proposed on l1, the index is the one of the output in the L2OutputOracle or the one
+// of the dispute game in the DisputeGameFactory
+type Output struct {
+	Index         *big.Int
+	Root          common.Hash
+	L2BlockNumber uint64
+}
+
+// OutputData is the output synced to the light client on map
+type OutputData struct {
+	OutputIndex     *big.Int
+	L2BlockNumber   *big.Int
+	OutputRootProof OutputRootProof
+}
+
+// NewOutputRootProof reads the preimage of the output root of the block from the node, and returns it with the
+// header of the block
+func NewOutputRootProof(ctx context.Context, c *Client, number uint64) (*OutputRootProof, *Header, error) {
+	n := new(big.Int).SetUint64(number)
+	header, err := c.HeaderByNumber(ctx, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	passer, err := c.GetProof(ctx, MessagePasser, nil, n)
+	if err != nil {
+		return nil, nil, err
+	}
+	return &OutputRootProof{
+		StateRoot:                header.Root,
+		MessagePasserStorageRoot: passer.StorageHash,
+		LatestBlockhash:          header.Hash(),
+	}, header, nil
+}
+
+// InHistory checks the hash of the block is kept in the history storage of the output block. The hash of a block is
+// written by the block after it, since isthmus, and is overwritten HistoryServeWindow blocks later.
+func InHistory(header *Header, outputBlock, isthmusTime uint64) error {
+	number := header.Number.Uint64()
+	if header.Time < isthmusTime {
+		return fmt.Errorf("%w: block %d at %d is before isthmus at %d", ErrNotInHistory, number, header.Time, isthmusTime)
+	}
+	if number > outputBlock || outputBlock-number > HistoryServeWindow {
+		return fmt.Errorf("%w: block %d is out of the %d blocks before output block %d", ErrNotInHistory, number,
+			HistoryServeWindow, outputBlock)
+	}
+	return nil
+}
+
+// HistorySlot is the slot of the history storage keeping the hash of the block
+func HistorySlot(number uint64) common.Hash {
+	return common.BigToHash(new(big.Int).SetUint64(number % HistoryServeWindow))
+}
+
+// VerifyBlockHash checks the hash of the block is kept in the history storage of the state, with the proofs of
+// eth_getProof for the history storage and its slot of the block
+func VerifyBlockHash(stateRoot common.Hash, number uint64, hash common.Hash, accountProof, storageProof [][]byte) error {
+	value, err := trie.VerifyProof(stateRoot, crypto.Keccak256(HistoryStorage[:]), proofDb(accountProof))
+	if err != nil {
+		return fmt.Errorf("%w: account proof of history storage: %v", ErrInvalidOutput, err)
+	}
+	if value == nil {
+		return fmt.Errorf("%w: no history storage in state %s, the l2 is not on isthmus", ErrNotInHistory, stateRoot)
+	}
+	var account types.StateAccount
+	if err = rlp.DecodeBytes(value, &account); err != nil {
+		return fmt.Errorf("%w: history storage account: %v", ErrInvalidOutput, err)
+	}
+
+	slot := HistorySlot(number)
+	value, err = trie.VerifyProof(account.Root, crypto.Keccak256(slot[:]), proofDb(storageProof))
+	if err != nil {
+		return fmt.Errorf("%w: storage proof of block %d: %v", ErrInvalidOutput, number, err)
+	}
+	if value == nil {
+		return fmt.Errorf("%w: slot of block %d is empty", ErrNotInHistory, number)
+	}
+	var stored []byte
+	if err = rlp.DecodeBytes(value, &stored); err != nil {
+		return fmt.Errorf("%w: storage of block %d: %v", ErrInvalidOutput, number, err)
+	}
+	if !bytes.Equal(common.LeftPadBytes(stored, common.HashLength), hash[:]) {
+		return fmt.Errorf("%w: history storage has %x for block %d, not %s", ErrInvalidOutput, stored, number, hash)
+	}
+	return nil
+}
+
+func proofDb(nodes [][]byte) *memorydb.Database {
+	db := memorydb.New()
+	for _, n := range nodes {
+		_ = db.Put(crypto.Keccak256(n), n)
+	}
+	return db
+}
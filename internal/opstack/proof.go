@@ -0,0 +1,236 @@
+package opstack
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+	utils "github.com/mapprotocol/compass/shared/ethereum"
+)
+
+// ProofData proves a receipt of an l2 block against an output root proposed on l1. The state of the output block
+// keeps the hash of the block of the receipt in the history storage, unless it is the output block itself, then
+// the account and storage proofs are empty and the header hashes to latestBlockhash.
+type ProofData struct {
+	OutputIndex     *big.Int
+	OutputRootProof OutputRootProof
+	AccountProof    [][]byte
+	StorageProof    [][]byte
+	Header          []byte // rlp of the header of the block of the receipt
+	ReceiptProof    ReceiptProof
+}
+
+type ReceiptProof struct {
+	TxReceipt mapprotocol.TxReceipt
+	KeyIndex  []byte
+	Proof     [][]byte
+}
+
+// BlockLink is how the block of a receipt is tied to an output
+type BlockLink struct {
+	Output       *Output
+	RootProof    *OutputRootProof
+	AccountProof [][]byte
+	StorageProof [][]byte
+}
+
+func init() {
+	iproof.Register(chains.Opstack, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		opts, err := ParseOpts(cfg.Opts)
+		if err != nil {
+			return nil, err
+		}
+		ctx := context.Background()
+		source, err := opts.NewOutputSource(ctx)
+		if err != nil {
+			return nil, err
+		}
+		c, err := Dial(ctx, cfg.Endpoint)
+		if err != nil {
+			return nil, err
+		}
+		return NewBuilder(c, source, opts.IsthmusTime, cfg.Id), nil
+	})
+}
+
+// Builder assembles the proof of a log with the output root proposed after its block
+type Builder struct {
+	client      *Client
+	source      OutputSource
+	isthmusTime uint64
+	fId         msg.ChainId
+
+	lock     sync.Mutex
+	number   uint64
+	receipts Receipts // the receipts of the last block fetched, with the deposit fields
+}
+
+func NewBuilder(c *Client, source OutputSource, isthmusTime uint64, fId msg.ChainId) *Builder {
+	return &Builder{client: c, source: source, isthmusTime: isthmusTime, fId: fId}
+}
+
+func (b *Builder) Receipts(ctx context.Context, number *big.Int) ([]*types.Receipt, error) {
+	rs, err := b.blockReceipts(ctx, number.Uint64())
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*types.Receipt, 0, len(rs))
+	for _, r := range rs {
+		ret = append(ret, r.EthReceipt())
+	}
+	return ret, nil
+}
+
+func (b *Builder) blockReceipts(ctx context.Context, number uint64) (Receipts, error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+	if b.receipts != nil && b.number == number {
+		return b.receipts, nil
+	}
+	rs, err := b.client.BlockReceipts(ctx, new(big.Int).SetUint64(number))
+	if err != nil {
+		return nil, err
+	}
+	b.number, b.receipts = number, rs
+	return rs, nil
+}
+
+func (b *Builder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	if int(log.TxIndex) >= len(receipts) {
+		return nil, fmt.Errorf("%w: tx index %d out of %d receipts", iproof.ErrInvalidProof, log.TxIndex, len(receipts))
+	}
+	if receipts[log.TxIndex].Type == DepositTxType {
+		// the deposits are relayed from l1, their events are not taken on l2
+		return nil, fmt.Errorf("%w: log of deposit tx %s", iproof.ErrInvalidProof, log.TxHash)
+	}
+	header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if header.Hash() != log.BlockHash {
+		return nil, fmt.Errorf("block %d is %s, not %s of the log, it is reorged", log.BlockNumber, header.Hash(), log.BlockHash)
+	}
+	rs, err := b.blockReceipts(ctx, log.BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	link, err := Link(ctx, b.client, b.source, header, b.isthmusTime)
+	if err != nil {
+		return nil, err
+	}
+	return AssembleProof(link, header, rs, *log, iproof.Method(log.Topics[0]), b.fId)
+}
+
+// Link finds the output at or after the block and proves the block is in its chain, a block before the output
+// block has to be in its history storage
+func Link(ctx context.Context, c *Client, source OutputSource, header *Header, isthmusTime uint64) (*BlockLink, error) {
+	number := header.Number.Uint64()
+	output, err := source.OutputAfter(ctx, number)
+	if err != nil {
+		return nil, err
+	}
+	if output.L2BlockNumber != number {
+		if err = InHistory(header, output.L2BlockNumber, isthmusTime); err != nil {
+			return nil, err
+		}
+	}
+	rootProof, outHeader, err := NewOutputRootProof(ctx, c, output.L2BlockNumber)
+	if err != nil {
+		return nil, err
+	}
+	if root := rootProof.Root(); root != output.Root {
+		return nil, fmt.Errorf("%w: output %d of block %d is %s on l1, the node has %s", ErrInvalidOutput,
+			output.Index, output.L2BlockNumber, output.Root, root)
+	}
+	link := &BlockLink{Output: output, RootProof: rootProof}
+	if output.L2BlockNumber == number {
+		if outHeader.Hash() != header.Hash() {
+			return nil, fmt.Errorf("%w: block %d is not the one of output %d", ErrInvalidOutput, number, output.Index)
+		}
+		return link, nil
+	}
+
+	res, err := c.GetProof(ctx, HistoryStorage, []common.Hash{HistorySlot(number)}, new(big.Int).SetUint64(output.L2BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	if len(res.StorageProof) != 1 {
+		return nil, fmt.Errorf("%w: %d storage proofs of the history storage", ErrInvalidOutput, len(res.StorageProof))
+	}
+	link.AccountProof, link.StorageProof = bytesList(res.AccountProof), bytesList(res.StorageProof[0].Proof)
+	if err = VerifyBlockHash(rootProof.StateRoot, number, header.Hash(), link.AccountProof, link.StorageProof); err != nil {
+		return nil, err
+	}
+	return link, nil
+}
+
+func AssembleProof(link *BlockLink, header *Header, receipts Receipts, log types.Log, method string, fId msg.ChainId) (*iproof.Proof, error) {
+	if rs := receipttrie.DeriveRoot(receipts); rs != header.ReceiptHash {
+		return nil, fmt.Errorf("receipts of block %d hash to %s, not %s", log.BlockNumber, rs, header.ReceiptHash)
+	}
+	txIndex := log.TxIndex
+	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex].EthReceipt())
+	if err != nil {
+		return nil, err
+	}
+	proof, err := receipttrie.Prove(receipts, txIndex)
+	if err != nil {
+		return nil, err
+	}
+	var key []byte
+	key = rlp.AppendUint64(key[:0], uint64(txIndex))
+	ek := utils.Key2Hex(key, len(proof))
+
+	enc, err := rlp.EncodeToBytes(header)
+	if err != nil {
+		return nil, err
+	}
+	pd := ProofData{
+		OutputIndex:     link.Output.Index,
+		OutputRootProof: *link.RootProof,
+		AccountProof:    nonNil(link.AccountProof),
+		StorageProof:    nonNil(link.StorageProof),
+		Header:          enc,
+		ReceiptProof: ReceiptProof{
+			TxReceipt: *receipt,
+			KeyIndex:  ek,
+			Proof:     proof,
+		},
+	}
+	input, err := mapprotocol.Opstack.Methods[mapprotocol.MethodOfGetBytes].Inputs.Pack(pd)
+	if err != nil {
+		return nil, err
+	}
+	pack, err := mapprotocol.PackInput(mapprotocol.Mcs, method, new(big.Int).SetUint64(uint64(fId)), input)
+	if err != nil {
+		return nil, err
+	}
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: header.ReceiptHash,
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
+}
+
+func nonNil(bs [][]byte) [][]byte {
+	if bs == nil {
+		return [][]byte{}
+	}
+	return bs
+}
@@ -0,0 +1,128 @@
+package opstack
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DepositTxType is the type of the txs derived from l1, the first tx of every l2 block is the deposit of the l1 info
+const DepositTxType = 0x7e
+
+// Receipt is an op stack receipt as the node returns it. Since regolith the receipts of the deposit txs also carry
+// the nonce of the deposit, and since canyon the version of the receipt, both are in the receipt trie.
+type Receipt struct {
+	Type                  uint8
+	PostState             []byte
+	Status                uint64
+	CumulativeGasUsed     uint64
+	Bloom                 types.Bloom
+	Logs                  []*types.Log
+	TxHash                common.Hash
+	TransactionIndex      uint
+	DepositNonce          *uint64
+	DepositReceiptVersion *uint64
+}
+
+type rpcReceipt struct {
+	Type                  hexutil.Uint64  `json:"type"`
+	PostState             hexutil.Bytes   `json:"root"`
+	Status                hexutil.Uint64  `json:"status"`
+	CumulativeGasUsed     hexutil.Uint64  `json:"cumulativeGasUsed"`
+	Bloom                 types.Bloom     `json:"logsBloom"`
+	Logs                  []*types.Log    `json:"logs"`
+	TxHash                common.Hash     `json:"transactionHash"`
+	TransactionIndex      hexutil.Uint    `json:"transactionIndex"`
+	DepositNonce          *hexutil.Uint64 `json:"depositNonce,omitempty"`
+	DepositReceiptVersion *hexutil.Uint64 `json:"depositReceiptVersion,omitempty"`
+}
+
+func (r *Receipt) UnmarshalJSON(input []byte) error {
+	var dec rpcReceipt
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	*r = Receipt{
+		Type:                  uint8(dec.Type),
+		PostState:             dec.PostState,
+		Status:                uint64(dec.Status),
+		CumulativeGasUsed:     uint64(dec.CumulativeGasUsed),
+		Bloom:                 dec.Bloom,
+		Logs:                  dec.Logs,
+		TxHash:                dec.TxHash,
+		TransactionIndex:      uint(dec.TransactionIndex),
+		DepositNonce:          (*uint64)(dec.DepositNonce),
+		DepositReceiptVersion: (*uint64)(dec.DepositReceiptVersion),
+	}
+	return nil
+}
+
+func (r *Receipt) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&rpcReceipt{
+		Type:                  hexutil.Uint64(r.Type),
+		PostState:             r.PostState,
+		Status:                hexutil.Uint64(r.Status),
+		CumulativeGasUsed:     hexutil.Uint64(r.CumulativeGasUsed),
+		Bloom:                 r.Bloom,
+		Logs:                  r.Logs,
+		TxHash:                r.TxHash,
+		TransactionIndex:      hexutil.Uint(r.TransactionIndex),
+		DepositNonce:          (*hexutil.Uint64)(r.DepositNonce),
+		DepositReceiptVersion: (*hexutil.Uint64)(r.DepositReceiptVersion),
+	})
+}
+
+// EthReceipt drops the deposit fields, it is what the proof builders pass around
+func (r *Receipt) EthReceipt() *types.Receipt {
+	return &types.Receipt{
+		Type:              r.Type,
+		PostState:         r.PostState,
+		Status:            r.Status,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+		TxHash:            r.TxHash,
+		TransactionIndex:  r.TransactionIndex,
+	}
+}
+
+type receiptRLP struct {
+	PostStateOrStatus     []byte
+	CumulativeGasUsed     uint64
+	Bloom                 types.Bloom
+	Logs                  []*types.Log
+	DepositNonce          *uint64 `rlp:"optional"`
+	DepositReceiptVersion *uint64 `rlp:"optional"`
+}
+
+// Receipts implements receipttrie.DerivableList with the deposit fields
+type Receipts []*Receipt
+
+func (rs Receipts) Len() int { return len(rs) }
+
+func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
+	r := rs[i]
+	if r.Type != types.LegacyTxType {
+		w.WriteByte(r.Type)
+	}
+	enc := &receiptRLP{
+		PostStateOrStatus: r.PostState,
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+	}
+	if len(r.PostState) == 0 {
+		enc.PostStateOrStatus = []byte{}
+		if r.Status == types.ReceiptStatusSuccessful {
+			enc.PostStateOrStatus = []byte{0x01}
+		}
+	}
+	if r.Type == DepositTxType {
+		enc.DepositNonce, enc.DepositReceiptVersion = r.DepositNonce, r.DepositReceiptVersion
+	}
+	_ = rlp.Encode(w, enc)
+}
@@ -0,0 +1,228 @@
+package opstack
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Chain specific options of the opstack chains
+const (
+	L1EndpointOpt         = "l1Endpoint"
+	OutputOracleOpt       = "l2OutputOracle"
+	DisputeGameFactoryOpt = "disputeGameFactory"
+	GameTypeOpt           = "gameType"
+	IsthmusTimeOpt        = "isthmusTime"
+)
+
+// MaxGamesScanned bounds the dispute games looked at from the latest one back when an output is searched
+const MaxGamesScanned = 256
+
+const (
+	gameStatusChallengerWins = 1
+)
+
+const sourceAbiJson = `[
+	{"inputs":[],"name":"latestBlockNumber","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"_l2BlockNumber","type":"uint256"}],"name":"getL2OutputIndexAfter","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"_l2OutputIndex","type":"uint256"}],"name":"getL2Output","outputs":[{"components":[{"name":"outputRoot","type":"bytes32"},{"name":"timestamp","type":"uint128"},{"name":"l2BlockNumber","type":"uint128"}],"name":"","type":"tuple"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"gameCount","outputs":[{"name":"gameCount_","type":"uint256"}],"stateMutability":"view","type":"function"},
+	{"inputs":[{"name":"_index","type":"uint256"}],"name":"gameAtIndex","outputs":[{"name":"gameType_","type":"uint32"},{"name":"timestamp_","type":"uint64"},{"name":"proxy_","type":"address"}],"stateMutability":"view","type":"function"},
+	{"inputs":[],"name":"rootClaim","outputs":[{"name":"rootClaim_","type":"bytes32"}],"stateMutability":"pure","type":"function"},
+	{"inputs":[],"name":"l2BlockNumber","outputs":[{"name":"l2BlockNumber_","type":"uint256"}],"stateMutability":"pure","type":"function"},
+	{"inputs":[],"name":"status","outputs":[{"name":"","type":"uint8"}],"stateMutability":"view","type":"function"}
+]`
+
+var sourceAbi, _ = abi.JSON(strings.NewReader(sourceAbiJson))
+
+// OutputSource finds the output roots proposed on l1
+type OutputSource interface {
+	// OutputAfter returns the first output at or after the l2 block, ethereum.NotFound when it is not proposed yet
+	OutputAfter(ctx context.Context, l2Block uint64) (*Output, error)
+}
+
+// Options are the options of an opstack chain in the config, the outputs are read either from the L2OutputOracle
+// or from the DisputeGameFactory since fault proofs
+type Options struct {
+	L1Endpoint         string
+	OutputOracle       common.Address
+	DisputeGameFactory common.Address
+	GameType           *uint32 // only the games of the type are taken when it is set, the respected game type on l1
+	IsthmusTime        uint64  // the activation time of isthmus on l2, the history storage keeps the block hashes since
+}
+
+func ParseOpts(opts map[string]string) (*Options, error) {
+	o := &Options{L1Endpoint: opts[L1EndpointOpt]}
+	if o.L1Endpoint == "" {
+		return nil, fmt.Errorf("must provide opts.%s field for opstack config", L1EndpointOpt)
+	}
+	oracle, factory := opts[OutputOracleOpt], opts[DisputeGameFactoryOpt]
+	if (oracle == "") == (factory == "") {
+		return nil, fmt.Errorf("must provide one of opts.%s and opts.%s for opstack config", OutputOracleOpt,
+			DisputeGameFactoryOpt)
+	}
+	if oracle != "" {
+		o.OutputOracle = common.HexToAddress(oracle)
+	} else {
+		o.DisputeGameFactory = common.HexToAddress(factory)
+	}
+	if v, ok := opts[GameTypeOpt]; ok && v != "" {
+		t, err := strconv.ParseUint(v, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("unable to parse %s: %w", GameTypeOpt, err)
+		}
+		gameType := uint32(t)
+		o.GameType = &gameType
+	}
+	v, ok := opts[IsthmusTimeOpt]
+	if !ok || v == "" {
+		return nil, fmt.Errorf("must provide opts.%s field for opstack config", IsthmusTimeOpt)
+	}
+	t, err := strconv.ParseUint(v, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("unable to parse %s: %w", IsthmusTimeOpt, err)
+	}
+	o.IsthmusTime = t
+	return o, nil
+}
+
+// NewOutputSource connects to l1 for the outputs
+func (o *Options) NewOutputSource(ctx context.Context) (OutputSource, error) {
+	c, err := rpc.DialContext(ctx, o.L1Endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if o.OutputOracle != (common.Address{}) {
+		return NewOracleSource(c, o.OutputOracle), nil
+	}
+	return NewGameSource(c, o.DisputeGameFactory, o.GameType), nil
+}
+
+// caller reads the contracts on l1 at the finalized block, the outputs of l1 blocks that may be reorged are not
+// taken as the light client on map can not follow them
+type caller struct {
+	c *rpc.Client
+}
+
+func (c *caller) call(ctx context.Context, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	input, err := sourceAbi.Pack(method, args...)
+	if err != nil {
+		return nil, err
+	}
+	var output hexutil.Bytes
+	arg := map[string]interface{}{"to": to, "data": hexutil.Bytes(input)}
+	if err = c.c.CallContext(ctx, &output, "eth_call", arg, "finalized"); err != nil {
+		return nil, fmt.Errorf("%s of %s: %w", method, to, err)
+	}
+	return sourceAbi.Unpack(method, output)
+}
+
+// outputProposal is Types.OutputProposal of the L2OutputOracle
+type outputProposal struct {
+	OutputRoot    [32]byte
+	Timestamp     *big.Int
+	L2BlockNumber *big.Int
+}
+
+// OracleSource reads the outputs of the L2OutputOracle
+type OracleSource struct {
+	caller
+	oracle common.Address
+}
+
+func NewOracleSource(c *rpc.Client, oracle common.Address) *OracleSource {
+	return &OracleSource{caller: caller{c: c}, oracle: oracle}
+}
+
+func (s *OracleSource) OutputAfter(ctx context.Context, l2Block uint64) (*Output, error) {
+	out, err := s.call(ctx, s.oracle, "latestBlockNumber")
+	if err != nil {
+		return nil, err
+	}
+	// getL2OutputIndexAfter reverts when the block is after the latest output
+	if latest := out[0].(*big.Int); latest.Cmp(new(big.Int).SetUint64(l2Block)) < 0 {
+		return nil, ethereum.NotFound
+	}
+	out, err = s.call(ctx, s.oracle, "getL2OutputIndexAfter", new(big.Int).SetUint64(l2Block))
+	if err != nil {
+		return nil, err
+	}
+	index := out[0].(*big.Int)
+	out, err = s.call(ctx, s.oracle, "getL2Output", index)
+	if err != nil {
+		return nil, err
+	}
+	proposal := abi.ConvertType(out[0], new(outputProposal)).(*outputProposal)
+	return &Output{Index: index, Root: proposal.OutputRoot, L2BlockNumber: proposal.L2BlockNumber.Uint64()}, nil
+}
+
+// GameSource reads the outputs claimed by the dispute games of the DisputeGameFactory. The games the challenger
+// has won are skipped, the others are taken before they are resolved, it is the light client on map that decides
+// whether an output it has not seen resolved is accepted.
+type GameSource struct {
+	caller
+	factory  common.Address
+	gameType *uint32
+}
+
+func NewGameSource(c *rpc.Client, factory common.Address, gameType *uint32) *GameSource {
+	return &GameSource{caller: caller{c: c}, factory: factory, gameType: gameType}
+}
+
+func (s *GameSource) OutputAfter(ctx context.Context, l2Block uint64) (*Output, error) {
+	out, err := s.call(ctx, s.factory, "gameCount")
+	if err != nil {
+		return nil, err
+	}
+	count := out[0].(*big.Int).Uint64()
+
+	var found *Output
+	// the games are created in the order of their blocks by the proposer, the one of the least block at or after
+	// l2Block is searched from the latest game back
+	for i := count; i > 0 && count-i < MaxGamesScanned; i-- {
+		index := new(big.Int).SetUint64(i - 1)
+		out, err = s.call(ctx, s.factory, "gameAtIndex", index)
+		if err != nil {
+			return nil, err
+		}
+		gameType, proxy := out[0].(uint32), out[2].(common.Address)
+		if s.gameType != nil && gameType != *s.gameType {
+			continue
+		}
+		out, err = s.call(ctx, proxy, "l2BlockNumber")
+		if err != nil {
+			return nil, err
+		}
+		number := out[0].(*big.Int).Uint64()
+		if number < l2Block {
+			break
+		}
+		out, err = s.call(ctx, proxy, "status")
+		if err != nil {
+			return nil, err
+		}
+		if out[0].(uint8) == gameStatusChallengerWins {
+			continue
+		}
+		if found == nil || number <= found.L2BlockNumber {
+			found = &Output{Index: index, L2BlockNumber: number}
+			out, err = s.call(ctx, proxy, "rootClaim")
+			if err != nil {
+				return nil, err
+			}
+			found.Root = out[0].([32]byte)
+		}
+	}
+	if found == nil {
+		return nil, ethereum.NotFound
+	}
+	return found, nil
+}
@@ -9,10 +9,12 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
 	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	utils "github.com/mapprotocol/compass/shared/ethereum"
 )
 
@@ -93,14 +95,38 @@ type ReceiptProof struct {
 	Proof     [][]byte
 }
 
-func AssembleProof(block *UpdateBlock, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) ([]byte, error) {
+func init() {
+	iproof.Register(chains.Platon, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		return &Builder{client: cfg.Client, fId: cfg.Id}, nil
+	})
+}
+
+// Builder assembles the proof with the header, validators and quorum cert of the block of the log
+type Builder struct {
+	client *ethclient.Client
+	fId    msg.ChainId
+}
+
+func (b *Builder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	return iproof.Receipts(b.client, number)
+}
+
+func (b *Builder) Build(_ context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	headerParam, err := GetHeaderParam(b.client, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	return AssembleProof(headerParam, *log, receipts, iproof.Method(log.Topics[0]), b.fId)
+}
+
+func AssembleProof(block *UpdateBlock, log types.Log, receipts []*types.Receipt, method string, fId msg.ChainId) (*iproof.Proof, error) {
 	txIndex := log.TxIndex
 	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
 	if err != nil {
 		return nil, err
 	}
 
-	proof, err := iproof.Get(receipts, txIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
 	if err != nil {
 		return nil, err
 	}
@@ -128,7 +154,18 @@ func AssembleProof(block *UpdateBlock, log types.Log, receipts []*types.Receipt,
 	if err != nil {
 		return nil, err
 	}
-	return pack, nil
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	return &iproof.Proof{
+		Data:         pack,
+		Method:       method,
+		ReceiptsRoot: common.BytesToHash(block.Header.ReceiptsRoot),
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}, nil
 }
 
 func GetHeaderParam(client *ethclient.Client, latestBlock *big.Int) (*UpdateBlock, error) {
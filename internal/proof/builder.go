@@ -0,0 +1,106 @@
+package proof
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/internal/tx"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
+)
+
+// Proof is the assembled proof of a log, packed as the input of the mcs method on the destination chain
+type Proof struct {
+	Data      []byte
+	Method    string
+	ToChainId msg.ChainId // destination read from the log, only set by builders whose destination is not map
+
+	// parts of the receipt proof packed in Data, they are checked by Verify before the proof is routed
+	ReceiptsRoot common.Hash
+	KeyIndex     []byte
+	Nodes        [][]byte
+	Receipt      []byte // encoded receipt, the value of the key in the receipt trie
+}
+
+// Verify checks the receipt proof against the receipts root of the header
+func (p *Proof) Verify() error {
+	return Verify(p.ReceiptsRoot, p.KeyIndex, p.Nodes, p.Receipt)
+}
+
+// ErrNotReady is returned by a builder when the block of the log can not be proven yet, the light client on map does
+// not cover it. The block is retried later, it is not an alarm.
+var ErrNotReady = errors.New("proof not ready")
+
+// ProofBuilder assembles the receipt proof of a log for one chain type
+type ProofBuilder interface {
+	// Receipts returns all receipts of the block, in the order of the receipt trie
+	Receipts(ctx context.Context, number *big.Int) ([]*types.Receipt, error)
+	// Build assembles the proof of the log with the receipts of its block
+	Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*Proof, error)
+}
+
+// Config is what a builder needs to know about the chain it builds proofs for
+type Config struct {
+	Id         msg.ChainId
+	MapChainID msg.ChainId
+	Endpoint   string
+	Mcs        common.Address
+	Client     *ethclient.Client
+	Opts       map[string]string // the chain specific options left in the config
+	Map        *mapprotocol.MapContext
+}
+
+type Factory func(cfg *Config) (ProofBuilder, error)
+
+var (
+	lock      sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes a builder available for the chain type, it is called in the init of the chain package
+func Register(chainType string, factory Factory) {
+	lock.Lock()
+	defer lock.Unlock()
+	if _, ok := factories[chainType]; ok {
+		panic("proof builder registered twice for " + chainType)
+	}
+	factories[chainType] = factory
+}
+
+// New creates the builder of the chain type
+func New(chainType string, cfg *Config) (ProofBuilder, error) {
+	lock.RLock()
+	factory, ok := factories[chainType]
+	lock.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("no proof builder for chain type (%s)", chainType)
+	}
+	return factory(cfg)
+}
+
+// Method returns the mcs method which consumes the proof of the event
+func Method(topic common.Hash) string {
+	if method, ok := mapprotocol.Event[topic]; ok {
+		return method
+	}
+	return mapprotocol.MethodOfTransferIn
+}
+
+// Receipts fetches the receipts of the block through eth_getTransactionReceipt, it is shared by the evm builders
+func Receipts(client *ethclient.Client, number *big.Int) ([]*types.Receipt, error) {
+	txsHash, err := tx.GetTxsHashByBlockNumber(client, number)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
+	}
+	receipts, err := tx.GetReceiptsByTxsHash(client, txsHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
+	}
+	return receipts, nil
+}
@@ -0,0 +1,59 @@
+package proof
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+type testBuilder struct {
+	fId msg.ChainId
+}
+
+func (b *testBuilder) Receipts(_ context.Context, _ *big.Int) ([]*types.Receipt, error) {
+	return nil, nil
+}
+
+func (b *testBuilder) Build(_ context.Context, log *types.Log, _ []*types.Receipt) (*Proof, error) {
+	return &Proof{Data: []byte{byte(b.fId)}, Method: Method(log.Topics[0])}, nil
+}
+
+func TestRegistry(t *testing.T) {
+	Register("test", func(cfg *Config) (ProofBuilder, error) {
+		return &testBuilder{fId: cfg.Id}, nil
+	})
+
+	pb, err := New("test", &Config{Id: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, err := pb.Build(context.Background(), &types.Log{Topics: []common.Hash{mapprotocol.HashOfSwapIn}}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Data) != 1 || p.Data[0] != 7 {
+		t.Fatalf("unexpected data %x", p.Data)
+	}
+	if p.Method != mapprotocol.MethodOfSwapIn {
+		t.Fatalf("unexpected method %s", p.Method)
+	}
+
+	if _, err = New("unknown", &Config{}); err == nil {
+		t.Fatal("expected error for unknown chain type")
+	}
+}
+
+func TestRegisterTwice(t *testing.T) {
+	Register("twice", func(cfg *Config) (ProofBuilder, error) { return &testBuilder{}, nil })
+	defer func() {
+		if recover() == nil {
+			t.Fatal("expected panic")
+		}
+	}()
+	Register("twice", func(cfg *Config) (ProofBuilder, error) { return &testBuilder{}, nil })
+}
@@ -0,0 +1,159 @@
+package proof
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+// File is a proof written out by `compass proof build`, it keeps the proof decoded next to what was fetched
+// to assemble it, so it can be inspected and checked again without the chain.
+type File struct {
+	ChainId     msg.ChainId `json:"chainId"`
+	ChainType   string      `json:"chainType"`
+	TxHash      common.Hash `json:"txHash"`
+	BlockNumber uint64      `json:"blockNumber"`
+	Method      string      `json:"method"`
+	ToChainId   msg.ChainId `json:"toChainId,omitempty"`
+
+	Header  json.RawMessage `json:"header"` // as returned by eth_getBlockByNumber
+	Receipt *types.Receipt  `json:"receipt"`
+	Log     *types.Log      `json:"log"`
+
+	ReceiptsRoot common.Hash     `json:"receiptsRoot"`
+	KeyIndex     hexutil.Bytes   `json:"keyIndex"`
+	Nodes        []hexutil.Bytes `json:"nodes"`
+	Value        hexutil.Bytes   `json:"value"` // encoded receipt, the value of the key in the receipt trie
+
+	Proof    hexutil.Bytes `json:"proof"`              // the proof argument of the mcs method
+	Calldata hexutil.Bytes `json:"calldata,omitempty"` // empty when the proof is not sent to an mcs contract
+}
+
+// NewFile decodes the proof of the log in the receipt, with the header of its block.
+func NewFile(chainId msg.ChainId, chainType string, header json.RawMessage, receipt *types.Receipt, log *types.Log, p *Proof) *File {
+	f := &File{
+		ChainId:      chainId,
+		ChainType:    chainType,
+		TxHash:       receipt.TxHash,
+		BlockNumber:  log.BlockNumber,
+		Method:       p.Method,
+		ToChainId:    p.ToChainId,
+		Header:       header,
+		Receipt:      receipt,
+		Log:          log,
+		ReceiptsRoot: p.ReceiptsRoot,
+		KeyIndex:     p.KeyIndex,
+		Value:        p.Receipt,
+		Proof:        p.Data,
+	}
+	for _, n := range p.Nodes {
+		f.Nodes = append(f.Nodes, n)
+	}
+	if arg, err := unpackProof(p.Method, p.Data); err == nil {
+		f.Proof = arg
+		f.Calldata = p.Data
+	}
+	return f
+}
+
+// Verify checks the receipt proof against the receipts root of the header, that the proven receipt
+// has the log, and that the calldata carries the proof.
+func (f *File) Verify() error {
+	if len(f.Header) != 0 {
+		header := struct {
+			ReceiptsRoot *common.Hash `json:"receiptsRoot"`
+		}{}
+		if err := json.Unmarshal(f.Header, &header); err != nil {
+			return fmt.Errorf("header: %w", err)
+		}
+		if header.ReceiptsRoot == nil {
+			return fmt.Errorf("%w: header has no receiptsRoot", ErrInvalidProof)
+		}
+		if *header.ReceiptsRoot != f.ReceiptsRoot {
+			return fmt.Errorf("%w: proof is against root %s, header has %s", ErrInvalidProof, f.ReceiptsRoot.Hex(), header.ReceiptsRoot.Hex())
+		}
+	}
+
+	nodes := make([][]byte, 0, len(f.Nodes))
+	for _, n := range f.Nodes {
+		nodes = append(nodes, n)
+	}
+	if err := Verify(f.ReceiptsRoot, f.KeyIndex, nodes, f.Value); err != nil {
+		return err
+	}
+
+	if f.Log != nil {
+		if err := hasLog(f.Value, f.Log); err != nil {
+			return err
+		}
+	}
+	if len(f.Calldata) != 0 {
+		arg, err := unpackProof(f.Method, f.Calldata)
+		if err != nil {
+			return fmt.Errorf("calldata: %w", err)
+		}
+		if !bytes.Equal(arg, f.Proof) {
+			return fmt.Errorf("calldata does not carry the proof")
+		}
+	}
+	return nil
+}
+
+// unpackProof returns the proof argument of the mcs method in the calldata
+func unpackProof(method string, calldata []byte) ([]byte, error) {
+	m, ok := mapprotocol.Mcs.Methods[method]
+	if !ok {
+		return nil, fmt.Errorf("no mcs method %s", method)
+	}
+	if len(calldata) < 4 || !bytes.Equal(calldata[:4], m.ID) {
+		return nil, fmt.Errorf("calldata is not a call of %s", method)
+	}
+	args, err := m.Inputs.Unpack(calldata[4:])
+	if err != nil {
+		return nil, err
+	}
+	arg, ok := args[len(args)-1].([]byte)
+	if !ok {
+		return nil, fmt.Errorf("last argument of %s is not bytes", method)
+	}
+	return arg, nil
+}
+
+// hasLog checks the log is in the encoded receipt, the logs are the last field of the receipt of any chain
+func hasLog(value []byte, log *types.Log) error {
+	if len(value) > 0 && value[0] <= 0x7f {
+		value = value[1:]
+	}
+	var fields []rlp.RawValue
+	if err := rlp.DecodeBytes(value, &fields); err != nil || len(fields) == 0 {
+		return fmt.Errorf("%w: value is not a receipt", ErrInvalidProof)
+	}
+	var logs []struct {
+		Address common.Address
+		Topics  []common.Hash
+		Data    []byte
+	}
+	if err := rlp.DecodeBytes(fields[len(fields)-1], &logs); err != nil {
+		return fmt.Errorf("%w: receipt logs: %v", ErrInvalidProof, err)
+	}
+	for _, l := range logs {
+		if l.Address != log.Address || !bytes.Equal(l.Data, log.Data) || len(l.Topics) != len(log.Topics) {
+			continue
+		}
+		match := true
+		for i := range l.Topics {
+			match = match && l.Topics[i] == log.Topics[i]
+		}
+		if match {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: log %d of tx %s is not in the proven receipt", ErrInvalidProof, log.Index, log.TxHash.Hex())
+}
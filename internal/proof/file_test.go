@@ -0,0 +1,71 @@
+package proof_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+)
+
+func testFile(t *testing.T) *iproof.File {
+	receipts := testReceipts(20)
+	p := assemble(t, receipts, 5)
+	p.Method = mapprotocol.MethodOfSwapIn
+	data, err := mapprotocol.PackInput(mapprotocol.Mcs, p.Method, big.NewInt(56), []byte{1, 2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	p.Data = data
+	header := []byte(fmt.Sprintf(`{"number":"0x1","receiptsRoot":"%s"}`, receipttrie.DeriveRoot(receipttrie.Receipts(receipts)).Hex()))
+
+	f := iproof.NewFile(56, "bsc", header, receipts[5], receipts[5].Logs[0], p)
+	// what verify reads is what build writes
+	enc, err := json.Marshal(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ret := &iproof.File{}
+	if err = json.Unmarshal(enc, ret); err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestFileVerify(t *testing.T) {
+	f := testFile(t)
+	if string(f.Proof) != string([]byte{1, 2, 3}) {
+		t.Fatalf("proof %x is not unpacked from the calldata", f.Proof)
+	}
+	if err := f.Verify(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileVerifyMismatch(t *testing.T) {
+	cases := map[string]func(f *iproof.File){
+		"header root": func(f *iproof.File) {
+			f.Header = []byte(`{"receiptsRoot":"0x0100000000000000000000000000000000000000000000000000000000000000"}`)
+		},
+		"log": func(f *iproof.File) {
+			f.Log.Topics[1] = common.Hash{1}
+		},
+		"calldata": func(f *iproof.File) {
+			f.Proof = []byte{1, 2}
+		},
+	}
+	for name, tamper := range cases {
+		f := testFile(t)
+		tamper(f)
+		if err := f.Verify(); err == nil {
+			t.Fatalf("%s: expected error", name)
+		} else if name != "calldata" && !errors.Is(err, iproof.ErrInvalidProof) {
+			t.Fatalf("%s: expected invalid proof, got %v", name, err)
+		}
+	}
+}
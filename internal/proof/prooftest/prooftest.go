@@ -0,0 +1,96 @@
+// Package prooftest has the helpers the tests of the proof builders of the chain types share
+package prooftest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+)
+
+var (
+	Mcs              = common.HexToAddress("0x0000317bec33af037b5fab2028f52d14658f6a56")
+	TransferOutTopic = common.HexToHash("0x44ff77018688dad4b245e8ab97358ed57ed92269952ece7ffd321366ce078622")
+)
+
+// TransferOut is a log of the mcs the messenger builds a proof for
+func TransferOut() *types.Log {
+	return &types.Log{
+		Address: Mcs,
+		Topics:  []common.Hash{TransferOutTopic},
+		Data:    bytes.Repeat([]byte{0xab}, 64),
+	}
+}
+
+// Receipts makes the receipts the ones of the txs of a block: the tx hashes and the indexes are set, in the logs
+// too, the gas used is the cumulative gas used, and the blooms are computed
+func Receipts(rs ...*types.Receipt) []*types.Receipt {
+	for i, r := range rs {
+		r.TxHash = crypto.Keccak256Hash([]byte{byte(i)})
+		r.TransactionIndex = uint(i)
+		r.GasUsed = r.CumulativeGasUsed
+		for _, l := range r.Logs {
+			l.TxIndex, l.TxHash = uint(i), r.TxHash
+		}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+	}
+	return rs
+}
+
+// Header is the header of a chain type, hashed the way the chain does
+type Header interface {
+	Hash() common.Hash
+}
+
+// CheckJSON encodes the header the way the node answers it and decodes it into dec, which has to hash the same.
+// It returns the encoding for CheckDropped.
+func CheckJSON(t *testing.T, h, dec Header) string {
+	t.Helper()
+	enc, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = json.Unmarshal(enc, dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.Hash() != h.Hash() {
+		t.Fatalf("hash %s, want %s", dec.Hash(), h.Hash())
+	}
+	return string(enc)
+}
+
+// CheckDropped renames the field in the encoding of a header, the header decoded without the field would not hash
+// to the hash of the node, so decoding it into dec has to fail
+func CheckDropped(t *testing.T, enc, field, renamed string, dec Header) {
+	t.Helper()
+	tampered := strings.Replace(enc, `"`+field+`"`, `"`+renamed+`"`, 1)
+	if tampered == enc {
+		t.Fatalf("no field %s in the header", field)
+	}
+	if err := json.Unmarshal([]byte(tampered), dec); err == nil {
+		t.Fatalf("header without %s decoded", field)
+	}
+}
+
+// CheckAssemble verifies the proof assembled for the log, and checks the log of a tx after the receipts of the
+// block is rejected
+func CheckAssemble(t *testing.T, assemble func(log types.Log) (*iproof.Proof, error), log types.Log, receipts int) {
+	t.Helper()
+	p, err := assemble(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = p.Verify(); err != nil {
+		t.Fatal(err)
+	}
+	log.TxIndex = uint(receipts)
+	if _, err = assemble(log); !errors.Is(err, iproof.ErrInvalidProof) {
+		t.Fatalf("got %v, want ErrInvalidProof", err)
+	}
+}
@@ -0,0 +1,170 @@
+package proof
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+var ErrInvalidProof = errors.New("invalid receipt proof")
+
+// Verify walks the proof nodes from the receipts root along the key index, and checks the value at the end
+// of the path is the encoded receipt. The key index is in the form sent to the mcs, it is the nibbles of
+// the trie key, except for a proof of a single node where it is the key itself (see utils.Key2Hex).
+func Verify(root common.Hash, keyIndex []byte, nodes [][]byte, receipt []byte) error {
+	if len(nodes) == 0 {
+		return fmt.Errorf("%w: empty proof", ErrInvalidProof)
+	}
+	path := keyIndex
+	if len(nodes) == 1 {
+		path = keyToNibbles(keyIndex)
+	}
+	for i, n := range path {
+		if n > 0xf {
+			return fmt.Errorf("%w: key index %x is not nibbles at %d", ErrInvalidProof, keyIndex, i)
+		}
+	}
+
+	var (
+		ref   = element{kind: rlp.String, content: root.Bytes()}
+		next  = 0
+		depth = 0
+		value []byte
+	)
+	for value == nil {
+		var node []byte
+		switch {
+		case ref.kind == rlp.List:
+			// nodes shorter than 32 bytes are embedded in their parent
+			node = ref.raw
+		case len(ref.content) == common.HashLength:
+			if next >= len(nodes) {
+				return fmt.Errorf("%w: proof ends at depth %d, node %x is missing", ErrInvalidProof, depth, ref.content)
+			}
+			node = nodes[next]
+			if got := crypto.Keccak256(node); !bytes.Equal(got, ref.content) {
+				return fmt.Errorf("%w: node %d hash is %x, want %x", ErrInvalidProof, next, got, ref.content)
+			}
+			next++
+		default:
+			return fmt.Errorf("%w: key index %x is not in the trie, reference %x at depth %d", ErrInvalidProof, keyIndex, ref.content, depth)
+		}
+
+		elems, err := splitNode(node)
+		if err != nil {
+			return fmt.Errorf("%w: depth %d: %v", ErrInvalidProof, depth, err)
+		}
+		switch len(elems) {
+		case 17:
+			if len(path) == 0 {
+				value = elems[16].content
+				break
+			}
+			ref = elems[path[0]]
+			path = path[1:]
+		case 2:
+			if elems[0].kind == rlp.List {
+				return fmt.Errorf("%w: depth %d: short node key is a list", ErrInvalidProof, depth)
+			}
+			nibbles, leaf := compactToNibbles(elems[0].content)
+			if !bytes.HasPrefix(path, nibbles) {
+				return fmt.Errorf("%w: depth %d: key index %x diverges from node path %x", ErrInvalidProof, depth, keyIndex, nibbles)
+			}
+			path = path[len(nibbles):]
+			if !leaf {
+				ref = elems[1]
+				break
+			}
+			if len(path) != 0 {
+				return fmt.Errorf("%w: depth %d: leaf reached with %d nibbles of key index left", ErrInvalidProof, depth, len(path))
+			}
+			value = elems[1].content
+		default:
+			return fmt.Errorf("%w: depth %d: node has %d elements", ErrInvalidProof, depth, len(elems))
+		}
+		depth++
+	}
+
+	if next != len(nodes) {
+		return fmt.Errorf("%w: %d of %d nodes are not on the path", ErrInvalidProof, len(nodes)-next, len(nodes))
+	}
+	if len(value) == 0 {
+		return fmt.Errorf("%w: key index %x has no value", ErrInvalidProof, keyIndex)
+	}
+	if !bytes.Equal(value, receipt) {
+		return fmt.Errorf("%w: value in trie is %x, receipt is %x", ErrInvalidProof, value, receipt)
+	}
+	return nil
+}
+
+// EncodeReceipt returns the consensus encoding of the receipt as sent to the mcs, which is the value in the
+// receipt trie: rlp([status, cumulativeGasUsed, bloom, logs]), prefixed with the type for typed receipts.
+func EncodeReceipt(r *mapprotocol.TxReceipt) ([]byte, error) {
+	enc, err := rlp.EncodeToBytes(struct {
+		PostStateOrStatus []byte
+		CumulativeGasUsed *big.Int
+		Bloom             []byte
+		Logs              []mapprotocol.TxLog
+	}{r.PostStateOrStatus, r.CumulativeGasUsed, r.Bloom, r.Logs})
+	if err != nil {
+		return nil, err
+	}
+	if r.ReceiptType == nil || r.ReceiptType.Sign() == 0 {
+		return enc, nil
+	}
+	return append([]byte{byte(r.ReceiptType.Uint64())}, enc...), nil
+}
+
+type element struct {
+	kind    rlp.Kind
+	content []byte
+	raw     []byte
+}
+
+func splitNode(node []byte) ([]element, error) {
+	list, rest, err := rlp.SplitList(node)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) != 0 {
+		return nil, fmt.Errorf("%d trailing bytes after node", len(rest))
+	}
+	elems := make([]element, 0, 17)
+	for len(list) > 0 {
+		kind, content, tail, err := rlp.Split(list)
+		if err != nil {
+			return nil, err
+		}
+		elems = append(elems, element{kind: kind, content: content, raw: list[:len(list)-len(tail)]})
+		list = tail
+	}
+	return elems, nil
+}
+
+// compactToNibbles decodes the hex-prefix encoded path of a short node
+func compactToNibbles(compact []byte) ([]byte, bool) {
+	if len(compact) == 0 {
+		return nil, false
+	}
+	flag := compact[0] >> 4
+	nibbles := make([]byte, 0, len(compact)*2)
+	if flag&1 == 1 {
+		nibbles = append(nibbles, compact[0]&0xf)
+	}
+	nibbles = append(nibbles, keyToNibbles(compact[1:])...)
+	return nibbles, flag >= 2
+}
+
+func keyToNibbles(key []byte) []byte {
+	nibbles := make([]byte, 0, len(key)*2)
+	for _, b := range key {
+		nibbles = append(nibbles, b/16, b%16)
+	}
+	return nibbles
+}
@@ -0,0 +1,100 @@
+package proof_test
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+	utils "github.com/mapprotocol/compass/shared/ethereum"
+)
+
+func testReceipts(n int) []*types.Receipt {
+	receipts := make([]*types.Receipt, 0, n)
+	for i := 0; i < n; i++ {
+		r := &types.Receipt{
+			Type:              uint8(i % 3),
+			Status:            uint64(i % 2),
+			CumulativeGasUsed: uint64(21000 * (i + 1)),
+			Logs: []*types.Log{{
+				Address: common.BigToAddress(big.NewInt(int64(i))),
+				Topics:  []common.Hash{mapprotocol.HashOfSwapIn, common.BigToHash(big.NewInt(int64(i)))},
+				Data:    []byte{byte(i)},
+			}},
+		}
+		r.Bloom = types.CreateBloom(types.Receipts{r})
+		receipts = append(receipts, r)
+	}
+	return receipts
+}
+
+func assemble(t *testing.T, receipts []*types.Receipt, txIndex uint) *iproof.Proof {
+	nodes, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
+	if err != nil {
+		t.Fatal(err)
+	}
+	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
+	if err != nil {
+		t.Fatal(err)
+	}
+	enc, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		t.Fatal(err)
+	}
+	key := rlp.AppendUint64(nil, uint64(txIndex))
+	return &iproof.Proof{
+		ReceiptsRoot: types.DeriveSha(types.Receipts(receipts), trie.NewStackTrie(nil)),
+		KeyIndex:     utils.Key2Hex(key, len(nodes)),
+		Nodes:        nodes,
+		Receipt:      enc,
+	}
+}
+
+func TestVerify(t *testing.T) {
+	for _, n := range []int{1, 2, 16, 200} {
+		receipts := testReceipts(n)
+		for _, idx := range []uint{0, uint(n / 2), uint(n - 1)} {
+			if err := assemble(t, receipts, idx).Verify(); err != nil {
+				t.Fatalf("receipts %d index %d: %v", n, idx, err)
+			}
+		}
+	}
+}
+
+func TestVerifyMismatch(t *testing.T) {
+	receipts := testReceipts(20)
+	cases := map[string]func(p *iproof.Proof){
+		"root": func(p *iproof.Proof) {
+			p.ReceiptsRoot = common.Hash{1}
+		},
+		"key index": func(p *iproof.Proof) {
+			p.KeyIndex = rlp.AppendUint64(nil, 5)
+		},
+		"other key": func(p *iproof.Proof) {
+			p.KeyIndex = utils.Key2Hex(rlp.AppendUint64(nil, 6), len(p.Nodes))
+		},
+		"node": func(p *iproof.Proof) {
+			p.Nodes[len(p.Nodes)-1] = append([]byte{}, p.Nodes[0]...)
+		},
+		"missing node": func(p *iproof.Proof) {
+			p.Nodes = p.Nodes[:len(p.Nodes)-1]
+		},
+		"receipt": func(p *iproof.Proof) {
+			p.Receipt = p.Receipt[1:]
+		},
+	}
+	for name, tamper := range cases {
+		p := assemble(t, receipts, 5)
+		tamper(p)
+		err := p.Verify()
+		if !errors.Is(err, iproof.ErrInvalidProof) {
+			t.Fatalf("%s: expected invalid proof, got %v", name, err)
+		}
+	}
+}
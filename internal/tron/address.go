@@ -0,0 +1,65 @@
+package tron
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mr-tron/base58"
+)
+
+// AddressPrefix is the first byte of the 21 bytes tron addresses, the other 20 bytes are the ethereum address
+const AddressPrefix = 0x41
+
+// EncodeAddress returns the base58check address starting with T
+func EncodeAddress(addr common.Address) string {
+	payload := append([]byte{AddressPrefix}, addr.Bytes()...)
+	return base58.Encode(append(payload, checksum(payload)...))
+}
+
+// HexAddress returns the 41 prefixed hex address the http api takes when visible is false
+func HexAddress(addr common.Address) string {
+	return hex.EncodeToString(append([]byte{AddressPrefix}, addr.Bytes()...))
+}
+
+// DecodeAddress parses the base58check, 41 prefixed hex or 0x prefixed hex address
+func DecodeAddress(s string) (common.Address, error) {
+	switch {
+	case strings.HasPrefix(s, "T"):
+		raw, err := base58.Decode(s)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("invalid tron address %s: %w", s, err)
+		}
+		if len(raw) != 25 || !bytes.Equal(checksum(raw[:21]), raw[21:]) {
+			return common.Address{}, fmt.Errorf("invalid checksum of tron address %s", s)
+		}
+		return decodeRaw(raw[:21], s)
+	case strings.HasPrefix(s, "0x"):
+		if !common.IsHexAddress(s) {
+			return common.Address{}, fmt.Errorf("invalid address %s", s)
+		}
+		return common.HexToAddress(s), nil
+	default:
+		raw, err := hex.DecodeString(s)
+		if err != nil {
+			return common.Address{}, fmt.Errorf("invalid tron address %s: %w", s, err)
+		}
+		return decodeRaw(raw, s)
+	}
+}
+
+func decodeRaw(raw []byte, s string) (common.Address, error) {
+	if len(raw) != 21 || raw[0] != AddressPrefix {
+		return common.Address{}, fmt.Errorf("invalid tron address %s", s)
+	}
+	return common.BytesToAddress(raw[1:]), nil
+}
+
+func checksum(payload []byte) []byte {
+	h := sha256.Sum256(payload)
+	h = sha256.Sum256(h[:])
+	return h[:4]
+}
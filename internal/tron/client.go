@@ -0,0 +1,180 @@
+package tron
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// Client calls the http api of a tron full node, the solidified blocks are read from its /walletsolidity api
+type Client struct {
+	endpoint string
+	http     *http.Client
+}
+
+func NewClient(endpoint string) *Client {
+	return &Client{endpoint: strings.TrimSuffix(endpoint, "/"), http: &http.Client{Timeout: 30 * time.Second}}
+}
+
+// apiError is the error of the api, the message is hex of the text in some of the methods
+type apiError struct {
+	Error   string `json:"Error"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+func (e *apiError) err() error {
+	if e.Error != "" {
+		return fmt.Errorf("tron api: %s", e.Error)
+	}
+	if e.Code == "" || e.Code == "SUCCESS" {
+		return nil
+	}
+	msg := e.Message
+	if dec, err := hex.DecodeString(msg); err == nil {
+		msg = string(dec)
+	}
+	return fmt.Errorf("tron api: %s %s", e.Code, msg)
+}
+
+func (c *Client) post(ctx context.Context, path string, args interface{}, result interface{}) error {
+	body, err := json.Marshal(args)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.endpoint+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s %s", path, resp.Status, data)
+	}
+	var e apiError
+	if json.Unmarshal(data, &e) == nil {
+		if err = e.err(); err != nil {
+			return fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	if bytes.Equal(bytes.TrimSpace(data), []byte("{}")) {
+		return ethereum.NotFound
+	}
+	return json.Unmarshal(data, result)
+}
+
+// NowBlock returns the header of the latest block
+func (c *Client) NowBlock(ctx context.Context) (*Header, error) {
+	var h Header
+	return &h, c.post(ctx, "/wallet/getnowblock", struct{}{}, &h)
+}
+
+// SolidBlock returns the header of the latest solidified block, it is confirmed by 2/3 of the witnesses and final
+func (c *Client) SolidBlock(ctx context.Context) (*Header, error) {
+	var h Header
+	return &h, c.post(ctx, "/walletsolidity/getnowblock", struct{}{}, &h)
+}
+
+func (c *Client) HeaderByNumber(ctx context.Context, number uint64) (*Header, error) {
+	var h Header
+	return &h, c.post(ctx, "/wallet/getblockbynum", map[string]interface{}{"num": number}, &h)
+}
+
+func (c *Client) TransactionInfo(ctx context.Context, id common.Hash) (*TransactionInfo, error) {
+	var info TransactionInfo
+	return &info, c.post(ctx, "/wallet/gettransactioninfobyid", map[string]interface{}{"value": hex.EncodeToString(id[:])}, &info)
+}
+
+// TriggerSmartContract builds the tx calling the contract with the data, the tx is signed and broadcast after
+func (c *Client) TriggerSmartContract(ctx context.Context, owner, contract common.Address, data []byte, feeLimit int64) (*Transaction, error) {
+	var resp struct {
+		Result      apiError    `json:"result"`
+		Transaction Transaction `json:"transaction"`
+	}
+	args := map[string]interface{}{
+		"owner_address":    HexAddress(owner),
+		"contract_address": HexAddress(contract),
+		"data":             hex.EncodeToString(data),
+		"fee_limit":        feeLimit,
+		"call_value":       0,
+		"visible":          false,
+	}
+	if err := c.post(ctx, "/wallet/triggersmartcontract", args, &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.Result.err(); err != nil {
+		return nil, err
+	}
+	tx := &resp.Transaction
+	if err := tx.CheckCall(owner, contract, data); err != nil {
+		return nil, err
+	}
+	return tx, nil
+}
+
+// TriggerConstantContract calls the view method of the contract with the data, and returns the output
+func (c *Client) TriggerConstantContract(ctx context.Context, owner, contract common.Address, data []byte) ([]byte, error) {
+	var resp struct {
+		Result         apiError `json:"result"`
+		ConstantResult []Bytes  `json:"constant_result"`
+	}
+	args := map[string]interface{}{
+		"owner_address":    HexAddress(owner),
+		"contract_address": HexAddress(contract),
+		"data":             hex.EncodeToString(data),
+		"visible":          false,
+	}
+	if err := c.post(ctx, "/wallet/triggerconstantcontract", args, &resp); err != nil {
+		return nil, err
+	}
+	if err := resp.Result.err(); err != nil {
+		return nil, err
+	}
+	if len(resp.ConstantResult) == 0 {
+		return nil, fmt.Errorf("no result of the call to %s", EncodeAddress(contract))
+	}
+	return resp.ConstantResult[0], nil
+}
+
+// BroadcastTransaction sends the signed tx
+func (c *Client) BroadcastTransaction(ctx context.Context, tx *Transaction) error {
+	var resp struct {
+		Result bool `json:"result"`
+	}
+	if err := c.post(ctx, "/wallet/broadcasttransaction", tx, &resp); err != nil {
+		return err
+	}
+	if !resp.Result {
+		return fmt.Errorf("tx %x is not accepted", []byte(tx.TxID))
+	}
+	return nil
+}
+
+// HasContract returns whether the address is a deployed contract
+func (c *Client) HasContract(ctx context.Context, addr common.Address) (bool, error) {
+	var resp struct {
+		Bytecode string `json:"bytecode"`
+	}
+	err := c.post(ctx, "/wallet/getcontract", map[string]interface{}{"value": HexAddress(addr)}, &resp)
+	if err == ethereum.NotFound {
+		return false, nil
+	}
+	return resp.Bytecode != "", err
+}
@@ -0,0 +1,180 @@
+package tron
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Bytes is the hex of the http api, without the 0x prefix
+type Bytes []byte
+
+func (b Bytes) MarshalText() ([]byte, error) {
+	return []byte(hex.EncodeToString(b)), nil
+}
+
+func (b *Bytes) UnmarshalText(input []byte) error {
+	dec, err := hex.DecodeString(strings.TrimPrefix(string(input), "0x"))
+	if err != nil {
+		return err
+	}
+	*b = dec
+	return nil
+}
+
+// Header is the raw data of a block header and the signature of the witness producing it. The block id is the
+// number followed by the sha256 of the protobuf of the raw data, the witness signs the same sha256.
+type Header struct {
+	Timestamp        int64
+	TxTrieRoot       []byte
+	ParentHash       []byte
+	Number           uint64
+	WitnessId        int64
+	WitnessAddress   common.Address
+	Version          int32
+	AccountStateRoot []byte
+	WitnessSignature []byte
+}
+
+// Raw is the protobuf of BlockHeader.raw, the fields are in the order of their numbers and the zero ones are left out
+func (h *Header) Raw() []byte {
+	var b []byte
+	b = appendVarint(b, 1, uint64(h.Timestamp))
+	b = appendBytes(b, 2, h.TxTrieRoot)
+	b = appendBytes(b, 3, h.ParentHash)
+	b = appendVarint(b, 7, h.Number)
+	b = appendVarint(b, 8, uint64(h.WitnessId))
+	b = appendBytes(b, 9, append([]byte{AddressPrefix}, h.WitnessAddress.Bytes()...))
+	b = appendVarint(b, 10, uint64(int64(h.Version)))
+	b = appendBytes(b, 11, h.AccountStateRoot)
+	return b
+}
+
+// SigningHash is the sha256 of the raw data the witness signs
+func (h *Header) SigningHash() common.Hash {
+	return sha256.Sum256(h.Raw())
+}
+
+// ID is the block id, the 8 bytes of the number replace the first bytes of the signing hash
+func (h *Header) ID() common.Hash {
+	id := h.SigningHash()
+	binary.BigEndian.PutUint64(id[:8], h.Number)
+	return id
+}
+
+// Signer recovers the address of the witness signature
+func (h *Header) Signer() (common.Address, error) {
+	if len(h.WitnessSignature) != crypto.SignatureLength {
+		return common.Address{}, fmt.Errorf("witness signature of block %d is %d bytes", h.Number, len(h.WitnessSignature))
+	}
+	sig := make([]byte, crypto.SignatureLength)
+	copy(sig, h.WitnessSignature)
+	if sig[64] >= 27 {
+		sig[64] -= 27
+	}
+	hash := h.SigningHash()
+	pub, err := crypto.SigToPub(hash[:], sig)
+	if err != nil {
+		return common.Address{}, err
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+type rpcRawHeader struct {
+	Timestamp        int64  `json:"timestamp,omitempty"`
+	TxTrieRoot       Bytes  `json:"txTrieRoot,omitempty"`
+	ParentHash       Bytes  `json:"parentHash,omitempty"`
+	Number           uint64 `json:"number,omitempty"`
+	WitnessId        int64  `json:"witness_id,omitempty"`
+	WitnessAddress   Bytes  `json:"witness_address,omitempty"`
+	Version          int32  `json:"version,omitempty"`
+	AccountStateRoot Bytes  `json:"accountStateRoot,omitempty"`
+}
+
+type rpcBlock struct {
+	BlockID     Bytes `json:"blockID"`
+	BlockHeader struct {
+		RawData          rpcRawHeader `json:"raw_data"`
+		WitnessSignature Bytes        `json:"witness_signature"`
+	} `json:"block_header"`
+}
+
+// UnmarshalJSON decodes the block of getblockbynum and getnowblock, it checks the header hashes to the block id the
+// node returns
+func (h *Header) UnmarshalJSON(input []byte) error {
+	var dec rpcBlock
+	if err := json.Unmarshal(input, &dec); err != nil {
+		return err
+	}
+	raw := dec.BlockHeader.RawData
+	if len(dec.BlockID) == 0 {
+		return errors.New("missing block id")
+	}
+	witness, err := decodeRaw(raw.WitnessAddress, hex.EncodeToString(raw.WitnessAddress))
+	if err != nil {
+		return err
+	}
+	*h = Header{
+		Timestamp:        raw.Timestamp,
+		TxTrieRoot:       raw.TxTrieRoot,
+		ParentHash:       raw.ParentHash,
+		Number:           raw.Number,
+		WitnessId:        raw.WitnessId,
+		WitnessAddress:   witness,
+		Version:          raw.Version,
+		AccountStateRoot: raw.AccountStateRoot,
+		WitnessSignature: dec.BlockHeader.WitnessSignature,
+	}
+	if id := h.ID(); id != common.BytesToHash(dec.BlockID) {
+		return fmt.Errorf("header of block %d hashes to %s, not %x", h.Number, hex.EncodeToString(id[:]), []byte(dec.BlockID))
+	}
+	return nil
+}
+
+// MarshalJSON encodes the header like the node does
+func (h *Header) MarshalJSON() ([]byte, error) {
+	var enc rpcBlock
+	id := h.ID()
+	enc.BlockID = id[:]
+	enc.BlockHeader.RawData = rpcRawHeader{
+		Timestamp:        h.Timestamp,
+		TxTrieRoot:       h.TxTrieRoot,
+		ParentHash:       h.ParentHash,
+		Number:           h.Number,
+		WitnessId:        h.WitnessId,
+		WitnessAddress:   append([]byte{AddressPrefix}, h.WitnessAddress.Bytes()...),
+		Version:          h.Version,
+		AccountStateRoot: h.AccountStateRoot,
+	}
+	enc.BlockHeader.WitnessSignature = h.WitnessSignature
+	return json.Marshal(&enc)
+}
+
+func appendVarint(b []byte, field int, v uint64) []byte {
+	if v == 0 {
+		return b
+	}
+	b = appendUvarint(b, uint64(field)<<3)
+	return appendUvarint(b, v)
+}
+
+func appendBytes(b []byte, field int, v []byte) []byte {
+	if len(v) == 0 {
+		return b
+	}
+	b = appendUvarint(b, uint64(field)<<3|2)
+	b = appendUvarint(b, uint64(len(v)))
+	return append(b, v...)
+}
+
+func appendUvarint(b []byte, v uint64) []byte {
+	var buf [binary.MaxVarintLen64]byte
+	return append(b, buf[:binary.PutUvarint(buf[:], v)]...)
+}
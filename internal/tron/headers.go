@@ -0,0 +1,53 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+// SignedHeader is the protobuf of the raw data of a header and the signature of its witness
+type SignedHeader struct {
+	Raw       []byte
+	Signature []byte
+}
+
+func NewSignedHeader(h *Header) SignedHeader {
+	return SignedHeader{Raw: h.Raw(), Signature: h.WitnessSignature}
+}
+
+// Headers returns count headers from the block, each one has to be signed by its witness and be the child of
+// the one before
+func (c *Client) Headers(ctx context.Context, number uint64, count int) ([]*Header, error) {
+	headers := make([]*Header, 0, count)
+	for i := 0; i < count; i++ {
+		h, err := c.HeaderByNumber(ctx, number+uint64(i))
+		if err != nil {
+			return nil, err
+		}
+		if signer, err := h.Signer(); err != nil {
+			return nil, err
+		} else if signer != h.WitnessAddress {
+			return nil, fmt.Errorf("block %d is signed by %s, not the witness %s", h.Number, EncodeAddress(signer),
+				EncodeAddress(h.WitnessAddress))
+		}
+		if i > 0 {
+			if parent := headers[i-1].ID(); common.BytesToHash(h.ParentHash) != parent {
+				return nil, fmt.Errorf("block %d is not the child of %x", h.Number, parent)
+			}
+		}
+		headers = append(headers, h)
+	}
+	return headers, nil
+}
+
+// PackHeaders packs the headers synced to the light client on map
+func PackHeaders(headers []*Header) ([]byte, error) {
+	signed := make([]SignedHeader, 0, len(headers))
+	for _, h := range headers {
+		signed = append(signed, NewSignedHeader(h))
+	}
+	return mapprotocol.Tron.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Pack(signed)
+}
@@ -0,0 +1,57 @@
+package tron
+
+import (
+	"context"
+	"fmt"
+)
+
+// Maintenance is the schedule of the maintenance periods of tron. The votes are counted at the first block at or
+// after a maintenance time, the witnesses elected produce the blocks after it. The maintenance times are on a grid
+// of the interval, a maintenance time passes without a block are skipped.
+type Maintenance struct {
+	Next     int64 // a maintenance time, in ms
+	Interval int64 // in ms
+}
+
+// Period is the number of the maintenance period of the timestamp, counted from the one starting at Next
+func (m *Maintenance) Period(timestamp int64) int64 {
+	d := timestamp - m.Next
+	p := d / m.Interval
+	if d%m.Interval < 0 {
+		p--
+	}
+	return p
+}
+
+// Maintained is whether the witnesses are elected at the block, it is the first one of a period
+func (m *Maintenance) Maintained(h, parent *Header) bool {
+	return m.Period(h.Timestamp) != m.Period(parent.Timestamp)
+}
+
+// Maintenance reads the next maintenance time and the interval of the chain parameters
+func (c *Client) Maintenance(ctx context.Context) (*Maintenance, error) {
+	var next struct {
+		Num int64 `json:"num"`
+	}
+	if err := c.post(ctx, "/wallet/getnextmaintenancetime", struct{}{}, &next); err != nil {
+		return nil, err
+	}
+	var params struct {
+		ChainParameter []struct {
+			Key   string `json:"key"`
+			Value int64  `json:"value"`
+		} `json:"chainParameter"`
+	}
+	if err := c.post(ctx, "/wallet/getchainparameters", struct{}{}, &params); err != nil {
+		return nil, err
+	}
+	for _, p := range params.ChainParameter {
+		if p.Key == "getMaintenanceTimeInterval" {
+			if p.Value <= 0 {
+				return nil, fmt.Errorf("maintenance interval is %d", p.Value)
+			}
+			return &Maintenance{Next: next.Num, Interval: p.Value}, nil
+		}
+	}
+	return nil, fmt.Errorf("no getMaintenanceTimeInterval in the chain parameters")
+}
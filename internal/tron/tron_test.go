@@ -0,0 +1,365 @@
+package tron
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/mapprotocol/compass/mapprotocol"
+)
+
+var (
+	mcsAddr    = common.HexToAddress("0x0000317bec33af037b5fab2028f52d14658f6a56")
+	eventTopic = common.HexToHash("0x44ff77018688dad4b245e8ab97358ed57ed92269952ece7ffd321366ce078622")
+)
+
+// mockNode stands in for the http api of a full node, it serves a chain of signed headers and the infos of
+// their txs, and builds and accepts the txs calling contracts
+type mockNode struct {
+	t       *testing.T
+	witness *ecdsa.PrivateKey
+	headers []*Header
+	infos   map[uint64][]*TransactionInfo
+	sent    []*Transaction
+	output  []byte // output of the constant calls
+}
+
+func newMockNode(t *testing.T, start uint64, count int) *mockNode {
+	key, _ := crypto.GenerateKey()
+	n := &mockNode{t: t, witness: key, infos: make(map[uint64][]*TransactionInfo)}
+	parent := crypto.Keccak256([]byte("parent"))
+	for i := 0; i < count; i++ {
+		h := &Header{
+			Timestamp:      1700000000000 + int64(i)*3000,
+			TxTrieRoot:     crypto.Keccak256([]byte{byte(i)}),
+			ParentHash:     parent,
+			Number:         start + uint64(i),
+			WitnessAddress: crypto.PubkeyToAddress(key.PublicKey),
+			Version:        30,
+		}
+		hash := h.SigningHash()
+		h.WitnessSignature, _ = crypto.Sign(hash[:], key)
+		id := h.ID()
+		parent = id[:]
+		n.headers = append(n.headers, h)
+	}
+	return n
+}
+
+func (n *mockNode) header(number uint64) *Header {
+	for _, h := range n.headers {
+		if h.Number == number {
+			return h
+		}
+	}
+	return nil
+}
+
+func (n *mockNode) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	var args map[string]interface{}
+	if err := json.NewDecoder(r.Body).Decode(&args); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	var resp interface{} = struct{}{}
+	switch r.URL.Path {
+	case "/wallet/getnowblock":
+		resp = n.headers[len(n.headers)-1]
+	case "/walletsolidity/getnowblock":
+		resp = n.headers[len(n.headers)-2]
+	case "/wallet/getblockbynum":
+		if h := n.header(uint64(args["num"].(float64))); h != nil {
+			resp = h
+		}
+	case "/wallet/getnextmaintenancetime":
+		resp = map[string]interface{}{"num": n.headers[0].Timestamp + 4500}
+	case "/wallet/getchainparameters":
+		resp = map[string]interface{}{"chainParameter": []map[string]interface{}{
+			{"key": "getMaintenanceTimeInterval", "value": 21600000},
+			{"key": "getCreateAccountFee", "value": 100000},
+		}}
+	case "/wallet/gettransactioninfobyid":
+		for _, infos := range n.infos {
+			for _, info := range infos {
+				if hex.EncodeToString(info.Id) == args["value"] {
+					resp = info
+				}
+			}
+		}
+	case "/wallet/triggersmartcontract":
+		raw := []byte("raw-")
+		for _, k := range []string{"owner_address", "contract_address", "data"} {
+			v, _ := hex.DecodeString(args[k].(string))
+			raw = append(raw, v...)
+		}
+		id := sha256.Sum256(raw)
+		resp = map[string]interface{}{
+			"result":      map[string]interface{}{"result": true},
+			"transaction": &Transaction{TxID: id[:], RawData: json.RawMessage(`{}`), RawDataHex: raw},
+		}
+	case "/wallet/broadcasttransaction":
+		var tx Transaction
+		data, _ := json.Marshal(args)
+		if err := json.Unmarshal(data, &tx); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		n.sent = append(n.sent, &tx)
+		resp = map[string]interface{}{"result": true, "txid": hex.EncodeToString(tx.TxID)}
+	case "/wallet/triggerconstantcontract":
+		resp = map[string]interface{}{
+			"result":          map[string]interface{}{"result": true},
+			"constant_result": []string{hex.EncodeToString(n.output)},
+		}
+	case "/wallet/getcontract":
+		if args["value"] == HexAddress(mcsAddr) {
+			resp = map[string]interface{}{"bytecode": "6080"}
+		}
+	default:
+		http.NotFound(w, r)
+		return
+	}
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		n.t.Error(err)
+	}
+}
+
+func testInfos(number uint64) []*TransactionInfo {
+	var infos []*TransactionInfo
+	for i, body := range []string{
+		`{"id":"%s","blockNumber":%d,"receipt":{"energy_usage_total":21000}}`,
+		`{"id":"%s","blockNumber":%d,"receipt":{"energy_usage_total":99000,"result":"SUCCESS"},"log":[{"address":"0000317bec33af037b5fab2028f52d14658f6a56","topics":["44ff77018688dad4b245e8ab97358ed57ed92269952ece7ffd321366ce078622"],"data":"` + strings.Repeat("ab", 64) + `"}]}`,
+		`{"id":"%s","blockNumber":%d,"result":"FAILED","resMessage":"5245564552540a","receipt":{"energy_usage_total":32000,"result":"REVERT"}}`,
+	} {
+		id := crypto.Keccak256([]byte{byte(i)})
+		body = strings.Replace(body, "%s", hex.EncodeToString(id), 1)
+		body = strings.Replace(body, "%d", new(big.Int).SetUint64(number).String(), 1)
+		var info TransactionInfo
+		if err := json.Unmarshal([]byte(body), &info); err != nil {
+			panic(err)
+		}
+		infos = append(infos, &info)
+	}
+	return infos
+}
+
+func TestAddress(t *testing.T) {
+	addr := common.HexToAddress("0xa614f803b6fd780986a42c78ec9c7f77e6ded13c")
+	if s := EncodeAddress(addr); s != "TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t" {
+		t.Fatalf("address %s", s)
+	}
+	for _, s := range []string{"TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6t", "41a614f803b6fd780986a42c78ec9c7f77e6ded13c",
+		"0xa614f803b6fd780986a42c78ec9c7f77e6ded13c"} {
+		dec, err := DecodeAddress(s)
+		if err != nil || dec != addr {
+			t.Fatalf("%s decoded to %s, %v", s, dec, err)
+		}
+	}
+	for _, s := range []string{"TR7NHqjeKQxGTCi8q8ZY4pL8otSzgjLj6u", "42a614f803b6fd780986a42c78ec9c7f77e6ded13c"} {
+		if _, err := DecodeAddress(s); err == nil {
+			t.Fatalf("%s decoded", s)
+		}
+	}
+}
+
+func TestHeaderJSON(t *testing.T) {
+	n := newMockNode(t, 50000000, 1)
+	h := n.headers[0]
+	enc, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var dec Header
+	if err = json.Unmarshal(enc, &dec); err != nil {
+		t.Fatal(err)
+	}
+	if dec.ID() != h.ID() || !bytes.Equal(dec.Raw(), h.Raw()) {
+		t.Fatalf("id %s, want %s", dec.ID(), h.ID())
+	}
+	if id := dec.ID(); new(big.Int).SetBytes(id[:8]).Uint64() != h.Number {
+		t.Fatalf("id %s does not start with the number", id)
+	}
+	if signer, err := dec.Signer(); err != nil || signer != h.WitnessAddress {
+		t.Fatalf("signer %s, %v", signer, err)
+	}
+
+	tampered := strings.Replace(string(enc), `"version":30`, `"version":29`, 1)
+	if err = json.Unmarshal([]byte(tampered), &dec); err == nil {
+		t.Fatal("tampered header decoded")
+	}
+}
+
+func TestClient(t *testing.T) {
+	n := newMockNode(t, 50000000, 3)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	ctx := context.Background()
+
+	solid, err := c.SolidBlock(ctx)
+	if err != nil || solid.Number != 50000001 {
+		t.Fatalf("solid block %d, %v", solid.Number, err)
+	}
+	if _, err = c.HeaderByNumber(ctx, 1); err != ethereum.NotFound {
+		t.Fatalf("missing block: %v", err)
+	}
+	if ok, err := c.HasContract(ctx, mcsAddr); err != nil || !ok {
+		t.Fatalf("mcs has no contract, %v", err)
+	}
+	if ok, err := c.HasContract(ctx, common.Address{1}); err != nil || ok {
+		t.Fatalf("account has contract, %v", err)
+	}
+
+	key, _ := crypto.GenerateKey()
+	owner := crypto.PubkeyToAddress(key.PublicKey)
+	data := []byte{0xde, 0xad, 0xbe, 0xef}
+	tx, err := c.TriggerSmartContract(ctx, owner, mcsAddr, data, 1000000000)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tx.Sign(key); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.BroadcastTransaction(ctx, tx); err != nil {
+		t.Fatal(err)
+	}
+	sent := n.sent[0]
+	pub, err := crypto.SigToPub(sent.TxID, sent.Signature[0])
+	if err != nil || crypto.PubkeyToAddress(*pub) != owner {
+		t.Fatalf("tx is not signed by the owner, %v", err)
+	}
+
+	// the node builds a tx of another call
+	if err = tx.CheckCall(owner, mcsAddr, []byte{0xca, 0xfe}); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("other call: %v", err)
+	}
+	tx.RawDataHex = append(tx.RawDataHex, 0x00)
+	if err = tx.CheckCall(owner, mcsAddr, data); !errors.Is(err, ErrInvalidTransaction) {
+		t.Fatalf("raw data not hashing to id: %v", err)
+	}
+
+	n.output = common.LeftPadBytes(big.NewInt(1234).Bytes(), 32)
+	out, err := c.TriggerConstantContract(ctx, owner, mcsAddr, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if height, err := mapprotocol.UnpackHeaderHeightOutput(out); err != nil || height.Int64() != 1234 {
+		t.Fatalf("height %s, %v", height, err)
+	}
+}
+
+func TestTransactionInfo(t *testing.T) {
+	n := newMockNode(t, 50000000, 1)
+	n.infos[50000000] = testInfos(50000000)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+
+	for i, success := range []bool{true, true, false} {
+		info, err := c.TransactionInfo(context.Background(), common.BytesToHash(n.infos[50000000][i].Id))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if info.Success() != success {
+			t.Fatalf("tx %d success %v", i, info.Success())
+		}
+	}
+	info := n.infos[50000000][1]
+	if len(info.Log) != 1 || common.BytesToAddress(info.Log[0].Address) != mcsAddr ||
+		common.BytesToHash(info.Log[0].Topics[0]) != eventTopic {
+		t.Fatalf("log %+v", info.Log)
+	}
+}
+
+func TestHeaders(t *testing.T) {
+	n := newMockNode(t, 50000000, mapprotocol.HeaderCountOfTron+1)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+	c := NewClient(srv.URL)
+	ctx := context.Background()
+
+	headers, err := c.Headers(ctx, 50000000, mapprotocol.HeaderCountOfTron)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(headers) != mapprotocol.HeaderCountOfTron || headers[len(headers)-1].Number != 50000000+mapprotocol.HeaderCountOfTron-1 {
+		t.Fatalf("%d headers", len(headers))
+	}
+
+	// a header not signed by its witness
+	n.headers[3].WitnessSignature[10] ^= 0xff
+	if _, err = c.Headers(ctx, 50000000, mapprotocol.HeaderCountOfTron); err == nil {
+		t.Fatal("header with bad signature accepted")
+	}
+	n.headers[3].WitnessSignature[10] ^= 0xff
+	// a header not the child of the one before
+	n.headers[5].ParentHash = crypto.Keccak256([]byte("other"))
+	hash := n.headers[5].SigningHash()
+	n.headers[5].WitnessSignature, _ = crypto.Sign(hash[:], n.witness)
+	if _, err = c.Headers(ctx, 50000000, mapprotocol.HeaderCountOfTron); err == nil {
+		t.Fatal("headers not linked accepted")
+	}
+}
+
+func TestMaintenance(t *testing.T) {
+	n := newMockNode(t, 50000000, 3)
+	srv := httptest.NewServer(n)
+	defer srv.Close()
+
+	m, err := NewClient(srv.URL).Maintenance(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.Next != n.headers[0].Timestamp+4500 || m.Interval != 21600000 {
+		t.Fatalf("maintenance %+v", m)
+	}
+	// the maintenance time is between the blocks 1 and 2, block 2 is the first one at or after it
+	if !m.Maintained(n.headers[2], n.headers[1]) || m.Maintained(n.headers[1], n.headers[0]) {
+		t.Fatal("block 2 is not the only maintained one")
+	}
+	for _, c := range []struct {
+		timestamp, period int64
+	}{
+		{m.Next, 0},
+		{m.Next - 1, -1},
+		{m.Next - m.Interval, -1},
+		{m.Next - m.Interval - 1, -2},
+		{m.Next + 3*m.Interval + 1, 3},
+	} {
+		if p := m.Period(c.timestamp); p != c.period {
+			t.Errorf("period of %d is %d, want %d", c.timestamp, p, c.period)
+		}
+	}
+}
+
+func TestPackHeaders(t *testing.T) {
+	n := newMockNode(t, 50000000, 3)
+	input, err := PackHeaders(n.headers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	out, err := mapprotocol.Tron.Methods[mapprotocol.MethodOfGetHeadersBytes].Inputs.Unpack(input)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signed := out[0].([]struct {
+		Raw       []byte `json:"raw"`
+		Signature []byte `json:"signature"`
+	})
+	if len(signed) != 3 || !bytes.Equal(signed[2].Raw, n.headers[2].Raw()) ||
+		!bytes.Equal(signed[2].Signature, n.headers[2].WitnessSignature) {
+		t.Fatalf("%d headers", len(signed))
+	}
+}
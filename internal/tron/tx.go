@@ -0,0 +1,80 @@
+package tron
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ErrInvalidTransaction is returned when the transaction built by the node is not the one asked for
+var ErrInvalidTransaction = errors.New("invalid transaction")
+
+// Transaction is a transaction built by the node, the raw data is signed by the sender
+type Transaction struct {
+	Visible    bool            `json:"visible"`
+	TxID       Bytes           `json:"txID"`
+	RawData    json.RawMessage `json:"raw_data"`
+	RawDataHex Bytes           `json:"raw_data_hex"`
+	Signature  []Bytes         `json:"signature,omitempty"`
+}
+
+func (tx *Transaction) Hash() common.Hash {
+	return common.BytesToHash(tx.TxID)
+}
+
+// CheckCall checks the transaction is the call of the contract with the data from the owner, the node builds
+// the raw data so it is not signed blindly
+func (tx *Transaction) CheckCall(owner, contract common.Address, data []byte) error {
+	if id := sha256.Sum256(tx.RawDataHex); !bytes.Equal(id[:], tx.TxID) {
+		return fmt.Errorf("%w: raw data hashes to %x, not the tx id %x", ErrInvalidTransaction, id, []byte(tx.TxID))
+	}
+	for _, want := range [][]byte{
+		append([]byte{AddressPrefix}, owner.Bytes()...),
+		append([]byte{AddressPrefix}, contract.Bytes()...),
+		data,
+	} {
+		if !bytes.Contains(tx.RawDataHex, want) {
+			return fmt.Errorf("%w: raw data of %x does not have %x", ErrInvalidTransaction, []byte(tx.TxID), want)
+		}
+	}
+	return nil
+}
+
+// Sign appends the signature of the tx id
+func (tx *Transaction) Sign(key *ecdsa.PrivateKey) error {
+	sig, err := crypto.Sign(tx.TxID, key)
+	if err != nil {
+		return err
+	}
+	tx.Signature = append(tx.Signature, sig)
+	return nil
+}
+
+// TransactionInfo is the result of a transaction, tron keeps the logs here instead of a receipt in the block
+type TransactionInfo struct {
+	Id             Bytes  `json:"id"`
+	BlockNumber    uint64 `json:"blockNumber"`
+	BlockTimeStamp int64  `json:"blockTimeStamp"`
+	Result         string `json:"result,omitempty"`     // FAILED, empty when the tx succeeded
+	ResMessage     Bytes  `json:"resMessage,omitempty"` // the reason of the failure
+	Receipt        struct {
+		EnergyUsageTotal uint64 `json:"energy_usage_total,omitempty"`
+		Result           string `json:"result,omitempty"` // the result of the contract, SUCCESS or REVERT etc.
+	} `json:"receipt"`
+	Log []struct {
+		Address Bytes   `json:"address"`
+		Topics  []Bytes `json:"topics"`
+		Data    Bytes   `json:"data"`
+	} `json:"log,omitempty"`
+}
+
+// Success is whether the tx and the contract it calls succeeded
+func (i *TransactionInfo) Success() bool {
+	return i.Result != "FAILED" && (i.Receipt.Result == "" || i.Receipt.Result == "SUCCESS")
+}
@@ -0,0 +1,245 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package journal
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+const (
+	PathPostfix = ".compass/journal"
+	FileExt     = ".journal"
+)
+
+type Status string
+
+const (
+	StatusPending   Status = "pending"   // signed, about to be broadcast
+	StatusSubmitted Status = "submitted" // accepted by the node, waiting for receipt
+	StatusSuccess   Status = "success"
+	StatusFailed    Status = "failed"
+	StatusDropped   Status = "dropped" // never mined and its nonce has been consumed by another tx
+)
+
+// Finished reports whether the entry has reached a final state
+func (s Status) Finished() bool {
+	return s == StatusSuccess || s == StatusFailed || s == StatusDropped
+}
+
+// Entry is one record of a destination transaction. The journal is append-only,
+// the latest record with the same Id wins.
+type Entry struct {
+	Id          string           `json:"id"`
+	Source      msg.ChainId      `json:"source"`
+	Destination msg.ChainId      `json:"destination"`
+	Type        msg.TransferType `json:"type"`
+	SrcHash     string           `json:"srcHash,omitempty"`
+	OrderId     string           `json:"orderId,omitempty"`
+	To          string           `json:"to"`
+	Method      string           `json:"method,omitempty"`
+	Nonce       uint64           `json:"nonce"`
+	GasLimit    uint64           `json:"gasLimit,omitempty"`
+	GasPrice    string           `json:"gasPrice,omitempty"`
+	GasTipCap   string           `json:"gasTipCap,omitempty"`
+	GasFeeCap   string           `json:"gasFeeCap,omitempty"`
+	TxHash      string           `json:"txHash,omitempty"`
+	Status      Status           `json:"status"`
+	Error       string           `json:"error,omitempty"`
+	Time        time.Time        `json:"time"`
+}
+
+type Journaler interface {
+	Append(*Entry) error
+	Unfinished() ([]*Entry, error)
+}
+
+var _ Journaler = &EmptyJournal{}
+var _ Journaler = &Journal{}
+
+// Dummy journal for testing only
+type EmptyJournal struct{}
+
+func (j *EmptyJournal) Append(_ *Entry) error { return nil }
+
+func (j *EmptyJournal) Unfinished() ([]*Entry, error) { return nil, nil }
+
+// Journal implements Journaler with a json-lines file per chain/relayer/role.
+type Journal struct {
+	path     string // Path excluding filename
+	fullPath string
+	lock     sync.Mutex
+}
+
+func NewJournal(path string, chain msg.ChainId, relayer string, role mapprotocol.Role) (*Journal, error) {
+	if path == "" {
+		def, err := getDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = def
+	}
+
+	return &Journal{
+		path:     path,
+		fullPath: filepath.Join(path, getFileName(chain, relayer, role)),
+	}, nil
+}
+
+// Append writes the entry to the end of the journal file.
+func (j *Journal) Append(e *Entry) error {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	if e.Time.IsZero() {
+		e.Time = time.Now()
+	}
+	return AppendLine(j.path, j.fullPath, e)
+}
+
+// AppendLine writes v as a json line to the end of the file in the directory, creating both if missing, and syncs
+// the file before it returns. The caller serializes the writes to the file.
+func AppendLine(dir, file string, v interface{}) error {
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		errr := os.MkdirAll(dir, os.ModePerm)
+		if errr != nil {
+			return errr
+		}
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	f, err := os.OpenFile(file, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	if _, err = f.Write(append(data, '\n')); err != nil {
+		return err
+	}
+	return f.Sync()
+}
+
+// Unfinished returns the entries which have not reached a final state.
+func (j *Journal) Unfinished() ([]*Entry, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	entries, err := Load(j.fullPath)
+	if err != nil {
+		return nil, err
+	}
+	ret := make([]*Entry, 0)
+	for _, e := range entries {
+		if !e.Status.Finished() {
+			ret = append(ret, e)
+		}
+	}
+	return ret, nil
+}
+
+// Load reads a journal file and returns the latest state of every entry, in order of first appearance.
+// A missing file is not an error. The last line may be cut by a crash while it was written, it is skipped
+// with a warning, a line which can not be decoded before it is an error.
+func Load(file string) ([]*Entry, error) {
+	f, err := os.Open(filepath.Clean(file))
+	if os.IsNotExist(err) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var (
+		ret   = make([]*Entry, 0)
+		index = make(map[string]int)
+		line  = 0
+		torn  error // the decode error of the previous line, which is only fine for the last line
+	)
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line++
+		if len(strings.TrimSpace(scanner.Text())) == 0 {
+			continue
+		}
+		if torn != nil {
+			return nil, torn
+		}
+		e := &Entry{}
+		if err = json.Unmarshal(scanner.Bytes(), e); err != nil {
+			torn = fmt.Errorf("%s line %d: %w", file, line, err)
+			continue
+		}
+		if idx, ok := index[e.Id]; ok {
+			ret[idx] = e
+			continue
+		}
+		index[e.Id] = len(ret)
+		ret = append(ret, e)
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+	if torn != nil {
+		log.Warn("Skip the torn last line of the journal", "err", torn)
+	}
+	return ret, nil
+}
+
+// LoadDir reads every journal file in the directory, an empty path will use the home directory.
+func LoadDir(path string) ([]*Entry, error) {
+	if path == "" {
+		def, err := getDefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = def
+	}
+	files, err := ioutil.ReadDir(path)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := make([]*Entry, 0)
+	for _, f := range files {
+		if f.IsDir() || filepath.Ext(f.Name()) != FileExt {
+			continue
+		}
+		entries, err := Load(filepath.Join(path, f.Name()))
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, entries...)
+	}
+	return ret, nil
+}
+
+func getFileName(chain msg.ChainId, relayer string, role mapprotocol.Role) string {
+	return fmt.Sprintf("%s-%d-%s%s", relayer, chain, role, FileExt)
+}
+
+// getDefaultPath returns the home directory joined with PathPostfix
+func getDefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(home, PathPostfix), nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package journal
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func TestAppendAndLoad(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, msg.ChainId(56), "0x01", mapprotocol.RoleOfMessenger)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// nothing written yet
+	entries, err := j.Unfinished()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("Expected: %d got: %d", 0, len(entries))
+	}
+
+	first := &Entry{Id: "0xaa", Source: 56, Destination: 22776, Type: msg.SwapWithProof, OrderId: "0x01", TxHash: "0xaa", Nonce: 1}
+	second := &Entry{Id: "0xbb", Source: 56, Destination: 22776, Type: msg.SwapWithProof, OrderId: "0x02", TxHash: "0xbb", Nonce: 2}
+	for _, e := range []*Entry{first, second} {
+		e.Status = StatusPending
+		if err = j.Append(e); err != nil {
+			t.Fatal(err)
+		}
+		e.Status = StatusSubmitted
+		if err = j.Append(e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	first.Status = StatusSuccess
+	if err = j.Append(first); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err = j.Unfinished()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Id != "0xbb" || entries[0].Status != StatusSubmitted {
+		t.Fatalf("unexpected unfinished entries %+v", entries)
+	}
+
+	all, err := LoadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("Expected: %d got: %d", 2, len(all))
+	}
+	if all[0].Id != "0xaa" || all[0].Status != StatusSuccess {
+		t.Fatalf("unexpected entry %+v", all[0])
+	}
+}
+
+func TestLoadTornLine(t *testing.T) {
+	dir, err := ioutil.TempDir(os.TempDir(), "journal")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	j, err := NewJournal(dir, msg.ChainId(56), "0x01", mapprotocol.RoleOfMessenger)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"0xaa", "0xbb"} {
+		if err = j.Append(&Entry{Id: id, Status: StatusSubmitted}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	data, err := ioutil.ReadFile(j.fullPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a crash while the last line was written
+	if err = ioutil.WriteFile(j.fullPath, data[:len(data)-10], 0600); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := j.Unfinished()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 1 || entries[0].Id != "0xaa" {
+		t.Fatalf("unexpected entries of torn journal %+v", entries)
+	}
+
+	// a broken line before the end is corruption
+	if err = ioutil.WriteFile(j.fullPath, append([]byte("{\"id\":\n"), data...), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(j.fullPath); err == nil {
+		t.Fatal("corrupted journal is loaded")
+	}
+}
@@ -60,6 +60,16 @@ func KeypairFromAddress(addr, chainType, path string, insecure bool) (crypto.Key
 		return nil, fmt.Errorf("key file not found: %s", path)
 	}
 
+	kp, err := ReadFromFileAndDecrypt(path, password(addr, path), keyMapping[chainType])
+	if err != nil {
+		return nil, err
+	}
+
+	return kp, nil
+}
+
+// password returns the password of the key file of the address, from the cache, the env or the terminal
+func password(addr, path string) []byte {
 	var pswd []byte
 	// find pswd in cache first;
 	// if using one account for several chains, u dont need to input the pswd repetitive
@@ -74,22 +84,10 @@ func KeypairFromAddress(addr, chainType, path string, insecure bool) (crypto.Key
 		// cache inputed pswd
 		keyPassCache[addr] = pswd
 	}
-
-	kp, err := ReadFromFileAndDecrypt(path, pswd, keyMapping[chainType])
-	if err != nil {
-		return nil, err
-	}
-
-	return kp, nil
+	return pswd
 }
 
 func NearKeyPairFrom(networkName, path string, id types.AccountID) (kp key.KeyPair, err error) {
-	var creds struct {
-		AccountID  types.AccountID     `json:"account_id"`
-		PublicKey  key.Base58PublicKey `json:"public_key"`
-		PrivateKey key.KeyPair         `json:"private_key"`
-	}
-
 	home := path
 	if home == "" {
 		home, err = os.UserHomeDir()
@@ -100,6 +98,17 @@ func NearKeyPairFrom(networkName, path string, id types.AccountID) (kp key.KeyPa
 	}
 
 	credsFile := filepath.Join(home, ".near-credentials", networkName, fmt.Sprintf("%s.json", id))
+	return NearKeyPairFromFile(credsFile, "")
+}
+
+// NearKeyPairFromFile loads the key pair from a credentials file in the format of near-cli, the account of the
+// credentials is checked when id is not empty
+func NearKeyPairFromFile(credsFile string, id types.AccountID) (kp key.KeyPair, err error) {
+	var creds struct {
+		AccountID  types.AccountID     `json:"account_id"`
+		PublicKey  key.Base58PublicKey `json:"public_key"`
+		PrivateKey key.KeyPair         `json:"private_key"`
+	}
 
 	var cf *os.File
 	if cf, err = os.Open(credsFile); err != nil {
@@ -111,6 +120,10 @@ func NearKeyPairFrom(networkName, path string, id types.AccountID) (kp key.KeyPa
 		return
 	}
 
+	if id != "" && creds.AccountID != id {
+		err = fmt.Errorf("credentials of %s, expect %s", creds.AccountID, id)
+		return
+	}
 	if creds.PublicKey.String() != creds.PrivateKey.PublicKey.String() {
 		err = fmt.Errorf("inconsistent public key, %s != %s", creds.PublicKey.String(), creds.PrivateKey.PublicKey.String())
 		return
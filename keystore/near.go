@@ -0,0 +1,162 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/mapprotocol/near-api-go/pkg/types"
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+)
+
+// NearKeyType is the type of the encrypted keystore of the near keys, the address of the keystore is the account
+const NearKeyType = string(key.KeyTypeED25519)
+
+// NearKeyFile returns the path of the encrypted keystore of the key of the near account, an account may have several
+// keys imported
+func NearKeyFile(path string, id types.AccountID, publicKey string) string {
+	return filepath.Join(path, fmt.Sprintf("%s.%s.key", id, strings.TrimPrefix(publicKey, NearKeyType+":")))
+}
+
+// nearLegacyKeyFile is the keystore of the only key of the account imported before the keys had their own files
+func nearLegacyKeyFile(path string, id types.AccountID) string {
+	return filepath.Join(path, fmt.Sprintf("%s.key", id))
+}
+
+// EncryptNearKeyPair encrypts the near key pair of the account using the password and saves it to the file
+func EncryptNearKeyPair(file *os.File, id types.AccountID, kp key.KeyPair, password []byte) error {
+	if kp.Type != key.KeyTypeED25519 {
+		return fmt.Errorf("cannot write near key not of type %s", key.KeyTypeED25519)
+	}
+	ciphertext, err := Encrypt([]byte(kp.PrivateEncoded()), password)
+	if err != nil {
+		return err
+	}
+
+	data, err := json.MarshalIndent(&EncryptedKeystore{
+		Type:       NearKeyType,
+		PublicKey:  kp.PublicKey.String(),
+		Address:    id,
+		Ciphertext: ciphertext,
+	}, "", "\t")
+	if err != nil {
+		return err
+	}
+
+	_, err = file.Write(append(data, byte('\n')))
+	return err
+}
+
+// DecryptNearKeyPair decrypts the near key pair from the encrypted keystore using the password
+func DecryptNearKeyPair(data, password []byte) (key.KeyPair, error) {
+	ks := new(EncryptedKeystore)
+	if err := json.Unmarshal(data, ks); err != nil {
+		return key.KeyPair{}, err
+	}
+	if ks.Type != NearKeyType {
+		return key.KeyPair{}, fmt.Errorf("keystore of %s is not a near key", ks.Address)
+	}
+
+	pk, err := Decrypt(ks.Ciphertext, password)
+	if err != nil {
+		return key.KeyPair{}, err
+	}
+	kp, err := key.NewBase58KeyPair(string(pk))
+	if err != nil {
+		return key.KeyPair{}, err
+	}
+
+	// Check that the decoding matches what was expected
+	if kp.PublicKey.String() != ks.PublicKey {
+		return key.KeyPair{}, errors.New("unexpected key file data, file may be corrupt or have been tampered with")
+	}
+	return kp, nil
+}
+
+// NearKeyPair loads the key pair of the account with the public key from the encrypted keystore in keystorePath,
+// prompting the user for the password as KeypairFromAddress does. An empty public key loads the only key of the
+// account imported apart from the ones in others. The plaintext credentials of near-cli in nearPath are read when
+// the key is not imported to the keystore.
+func NearKeyPair(networkName, keystorePath, nearPath string, id types.AccountID, publicKey string, others ...string) (key.KeyPair, error) {
+	path, err := nearKeyFileOf(keystorePath, id, publicKey, others)
+	if err != nil {
+		return key.KeyPair{}, err
+	}
+	if path == "" {
+		kp, err := NearKeyPairFrom(networkName, nearPath, id)
+		if err != nil {
+			return key.KeyPair{}, err
+		}
+		if publicKey != "" && kp.PublicKey.String() != publicKey {
+			return key.KeyPair{}, fmt.Errorf("key %s of %s is not imported to the keystore", publicKey, id)
+		}
+		return kp, nil
+	}
+	data, err := ioutil.ReadFile(filepath.Clean(path))
+	if err != nil {
+		return key.KeyPair{}, err
+	}
+
+	kp, err := DecryptNearKeyPair(data, password(path, path))
+	if err != nil {
+		return key.KeyPair{}, err
+	}
+	if publicKey != "" && kp.PublicKey.String() != publicKey {
+		return key.KeyPair{}, fmt.Errorf("keystore %s has the key %s, expect %s", path, kp.PublicKey.String(), publicKey)
+	}
+	return kp, nil
+}
+
+// nearKeyFileOf returns the keystore of the key of the account, or empty when it is not imported
+func nearKeyFileOf(keystorePath string, id types.AccountID, publicKey string, others []string) (string, error) {
+	if publicKey != "" {
+		path := NearKeyFile(keystorePath, id, publicKey)
+		if _, err := os.Stat(path); os.IsNotExist(err) {
+			return "", nil
+		} else if err != nil {
+			return "", err
+		}
+		return path, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(keystorePath, fmt.Sprintf("%s.*.key", id)))
+	if err != nil {
+		return "", err
+	}
+	if _, err = os.Stat(nearLegacyKeyFile(keystorePath, id)); err == nil {
+		files = append(files, nearLegacyKeyFile(keystorePath, id))
+	}
+	excluded := make(map[string]bool, len(others))
+	for _, o := range others {
+		excluded[NearKeyFile(keystorePath, id, o)] = true
+	}
+	found := make([]string, 0, len(files))
+	for _, f := range files {
+		// the glob matches the legacy files of the accounts under this one too, which have no public key
+		name := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(f), string(id)+"."), ".key")
+		if excluded[f] {
+			continue
+		}
+		if f != nearLegacyKeyFile(keystorePath, id) {
+			if _, err = key.NewBase58PublicKey(NearKeyType + ":" + name); err != nil {
+				continue
+			}
+		}
+		found = append(found, f)
+	}
+	switch len(found) {
+	case 0:
+		return "", nil
+	case 1:
+		return found[0], nil
+	default:
+		return "", fmt.Errorf("several keys of %s are imported to the keystore: %s", id, strings.Join(found, ", "))
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package keystore
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/mapprotocol/near-api-go/pkg/types/key"
+)
+
+func TestNearKeyPair(t *testing.T) {
+	dir, err := ioutil.TempDir("", "near-keystore")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	kp, err := key.GenerateKeyPair(key.KeyTypeED25519, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	account := "relayer.testnet"
+
+	// the plaintext credentials are read when the account is not in the keystore
+	credsDir := filepath.Join(dir, ".near-credentials", "testnet")
+	if err = os.MkdirAll(credsDir, 0700); err != nil {
+		t.Fatal(err)
+	}
+	creds, _ := json.Marshal(map[string]string{
+		"account_id":  account,
+		"public_key":  kp.PublicKey.String(),
+		"private_key": kp.PrivateEncoded(),
+	})
+	if err = ioutil.WriteFile(filepath.Join(credsDir, account+".json"), creds, 0600); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := NearKeyPair("testnet", filepath.Join(dir, "keys"), dir, account, "")
+	if err != nil || loaded.PrivateEncoded() != kp.PrivateEncoded() {
+		t.Fatalf("plaintext key %v, %v", loaded.PublicKey, err)
+	}
+
+	if _, err = NearKeyPair("testnet", filepath.Join(dir, "keys"), dir, account, "ed25519:11111111111111111111111111111111"); err == nil {
+		t.Fatal("plaintext key of another public key is loaded")
+	}
+
+	writeKey := func(kp key.KeyPair) {
+		file, err := os.Create(NearKeyFile(dir, account, kp.PublicKey.String()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer file.Close()
+		if err = EncryptNearKeyPair(file, account, kp, []byte("1234")); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeKey(kp)
+	data, err := ioutil.ReadFile(NearKeyFile(dir, account, kp.PublicKey.String()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = DecryptNearKeyPair(data, []byte("4321")); err == nil {
+		t.Fatal("decrypted with an incorrect password")
+	}
+	os.Setenv(EnvPassword, "1234")
+	defer os.Unsetenv(EnvPassword)
+	defer func() { keyPassCache = map[string][]byte{} }()
+	loaded, err = NearKeyPair("testnet", dir, "", account, "")
+	if err != nil || loaded.PrivateEncoded() != kp.PrivateEncoded() {
+		t.Fatalf("encrypted key %v, %v", loaded.PublicKey, err)
+	}
+
+	// the access key of the account has its own keystore, the key of the account is the other one
+	access, err := key.GenerateKeyPair(key.KeyTypeED25519, rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeKey(access)
+	if _, err = NearKeyPair("testnet", dir, "", account, ""); err == nil {
+		t.Fatal("one of several keys of the account is loaded")
+	}
+	loaded, err = NearKeyPair("testnet", dir, "", account, "", access.PublicKey.String())
+	if err != nil || loaded.PrivateEncoded() != kp.PrivateEncoded() {
+		t.Fatalf("key of the account %v, %v", loaded.PublicKey, err)
+	}
+	loaded, err = NearKeyPair("testnet", dir, "", account, access.PublicKey.String())
+	if err != nil || loaded.PrivateEncoded() != access.PrivateEncoded() {
+		t.Fatalf("access key %v, %v", loaded.PublicKey, err)
+	}
+}
@@ -2893,4 +2893,294 @@ const (
 		  "type": "function"
 		}
 	]`
+
+	OpstackAbiJson = `[
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "uint256", "name": "outputIndex", "type": "uint256"},
+				{
+					"components": [
+						{"internalType": "bytes32", "name": "version", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "stateRoot", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "messagePasserStorageRoot", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "latestBlockhash", "type": "bytes32"}
+					],
+					"internalType": "struct Verify.OutputRootProof",
+					"name": "outputRootProof",
+					"type": "tuple"
+				},
+				{"internalType": "bytes[]", "name": "accountProof", "type": "bytes[]"},
+				{"internalType": "bytes[]", "name": "storageProof", "type": "bytes[]"},
+				{"internalType": "bytes", "name": "header", "type": "bytes"},
+				{
+				  "components": [
+						{
+							"components": [
+								{"internalType": "uint256", "name": "receiptType", "type": "uint256"},
+								{"internalType": "bytes", "name": "postStateOrStatus", "type": "bytes"},
+								{"internalType": "uint256", "name": "cumulativeGasUsed", "type": "uint256"},
+								{"internalType": "bytes", "name": "bloom", "type": "bytes"},
+								{
+									"components": [
+										{"internalType": "address", "name": "addr", "type": "address"},
+										{"internalType": "bytes[]", "name": "topics", "type": "bytes[]"},
+										{"internalType": "bytes", "name": "data", "type": "bytes"}
+									],
+									"internalType": "struct TxLog[]",
+									"name": "logs",
+									"type": "tuple[]"
+								}
+							],
+							"internalType": "struct TxReceipt",
+							"name": "txReceipt",
+							"type": "tuple"
+						},
+						{"internalType": "bytes", "name": "keyIndex", "type": "bytes"},
+						{"internalType": "bytes[]", "name": "proof", "type": "bytes[]"}
+				  ],
+				  "internalType": "struct Verify.ReceiptProof",
+				  "name": "receiptProof",
+				  "type": "tuple"
+				}
+			  ],
+			  "internalType": "struct Verify.ProofData",
+			  "name": "_proof",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		},
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "uint256", "name": "outputIndex", "type": "uint256"},
+				{"internalType": "uint256", "name": "l2BlockNumber", "type": "uint256"},
+				{
+					"components": [
+						{"internalType": "bytes32", "name": "version", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "stateRoot", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "messagePasserStorageRoot", "type": "bytes32"},
+						{"internalType": "bytes32", "name": "latestBlockhash", "type": "bytes32"}
+					],
+					"internalType": "struct Verify.OutputRootProof",
+					"name": "outputRootProof",
+					"type": "tuple"
+				}
+			  ],
+			  "internalType": "struct Verify.Output",
+			  "name": "_output",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getHeadersBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		}
+	]`
+
+	ArbitrumAbiJson = `[
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "bytes", "name": "header", "type": "bytes"},
+				{
+				  "components": [
+						{
+							"components": [
+								{"internalType": "uint256", "name": "receiptType", "type": "uint256"},
+								{"internalType": "bytes", "name": "postStateOrStatus", "type": "bytes"},
+								{"internalType": "uint256", "name": "cumulativeGasUsed", "type": "uint256"},
+								{"internalType": "bytes", "name": "bloom", "type": "bytes"},
+								{
+									"components": [
+										{"internalType": "address", "name": "addr", "type": "address"},
+										{"internalType": "bytes[]", "name": "topics", "type": "bytes[]"},
+										{"internalType": "bytes", "name": "data", "type": "bytes"}
+									],
+									"internalType": "struct TxLog[]",
+									"name": "logs",
+									"type": "tuple[]"
+								}
+							],
+							"internalType": "struct TxReceipt",
+							"name": "txReceipt",
+							"type": "tuple"
+						},
+						{"internalType": "bytes", "name": "keyIndex", "type": "bytes"},
+						{"internalType": "bytes[]", "name": "proof", "type": "bytes[]"}
+				  ],
+				  "internalType": "struct Verify.ReceiptProof",
+				  "name": "receiptProof",
+				  "type": "tuple"
+				}
+			  ],
+			  "internalType": "struct Verify.ProofData",
+			  "name": "_proof",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		},
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "uint64", "name": "nodeNum", "type": "uint64"},
+				{"internalType": "bytes32", "name": "assertionHash", "type": "bytes32"},
+				{"internalType": "bytes32", "name": "blockHash", "type": "bytes32"},
+				{"internalType": "bytes32", "name": "sendRoot", "type": "bytes32"},
+				{"internalType": "bytes", "name": "header", "type": "bytes"}
+			  ],
+			  "internalType": "struct Verify.Confirmed",
+			  "name": "_confirmed",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getHeadersBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		}
+	]`
+
+	AvalancheAbiJson = `[
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "bytes", "name": "header", "type": "bytes"},
+				{
+				  "components": [
+						{
+							"components": [
+								{"internalType": "uint256", "name": "receiptType", "type": "uint256"},
+								{"internalType": "bytes", "name": "postStateOrStatus", "type": "bytes"},
+								{"internalType": "uint256", "name": "cumulativeGasUsed", "type": "uint256"},
+								{"internalType": "bytes", "name": "bloom", "type": "bytes"},
+								{
+									"components": [
+										{"internalType": "address", "name": "addr", "type": "address"},
+										{"internalType": "bytes[]", "name": "topics", "type": "bytes[]"},
+										{"internalType": "bytes", "name": "data", "type": "bytes"}
+									],
+									"internalType": "struct TxLog[]",
+									"name": "logs",
+									"type": "tuple[]"
+								}
+							],
+							"internalType": "struct TxReceipt",
+							"name": "txReceipt",
+							"type": "tuple"
+						},
+						{"internalType": "bytes", "name": "keyIndex", "type": "bytes"},
+						{"internalType": "bytes[]", "name": "proof", "type": "bytes[]"}
+				  ],
+				  "internalType": "struct Verify.ReceiptProof",
+				  "name": "receiptProof",
+				  "type": "tuple"
+				}
+			  ],
+			  "internalType": "struct Verify.ProofData",
+			  "name": "_proof",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		},
+		{
+		  "inputs": [{"internalType": "bytes[]", "name": "_headers", "type": "bytes[]"}],
+		  "name": "getHeadersBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		}
+	]`
+
+	TronAbiJson = `[
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{
+				  "components": [
+					{"internalType": "bytes", "name": "raw", "type": "bytes"},
+					{"internalType": "bytes", "name": "signature", "type": "bytes"}
+				  ],
+				  "internalType": "struct Verify.SignedHeader[]",
+				  "name": "headers",
+				  "type": "tuple[]"
+				},
+				{
+				  "components": [
+						{
+							"components": [
+								{"internalType": "uint256", "name": "receiptType", "type": "uint256"},
+								{"internalType": "bytes", "name": "postStateOrStatus", "type": "bytes"},
+								{"internalType": "uint256", "name": "cumulativeGasUsed", "type": "uint256"},
+								{"internalType": "bytes", "name": "bloom", "type": "bytes"},
+								{
+									"components": [
+										{"internalType": "address", "name": "addr", "type": "address"},
+										{"internalType": "bytes[]", "name": "topics", "type": "bytes[]"},
+										{"internalType": "bytes", "name": "data", "type": "bytes"}
+									],
+									"internalType": "struct TxLog[]",
+									"name": "logs",
+									"type": "tuple[]"
+								}
+							],
+							"internalType": "struct TxReceipt",
+							"name": "txReceipt",
+							"type": "tuple"
+						},
+						{"internalType": "bytes", "name": "keyIndex", "type": "bytes"},
+						{"internalType": "bytes[]", "name": "proof", "type": "bytes[]"}
+				  ],
+				  "internalType": "struct Verify.ReceiptProof",
+				  "name": "receiptProof",
+				  "type": "tuple"
+				}
+			  ],
+			  "internalType": "struct Verify.ProofData",
+			  "name": "_proof",
+			  "type": "tuple"
+			}
+		  ],
+		  "name": "getBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		},
+		{
+		  "inputs": [
+			{
+			  "components": [
+				{"internalType": "bytes", "name": "raw", "type": "bytes"},
+				{"internalType": "bytes", "name": "signature", "type": "bytes"}
+			  ],
+			  "internalType": "struct Verify.SignedHeader[]",
+			  "name": "_headers",
+			  "type": "tuple[]"
+			}
+		  ],
+		  "name": "getHeadersBytes",
+		  "outputs": [{"internalType": "bytes", "name": "", "type": "bytes"}],
+		  "stateMutability": "pure",
+		  "type": "function"
+		}
+	]`
 )
@@ -0,0 +1,147 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package mapprotocol
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+
+	goeth "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
+)
+
+// ErrMapNotConnected is returned by the queries of the light clients on map before the map chain is set
+var ErrMapNotConnected = errors.New("map chain is not connected")
+
+// MapContext is the view of a relayer on the map chain and the chains it relays. It holds the map connection with
+// the light client manager on map, and what the chains register for the light clients of map on them. It is created
+// once for a relayer and injected into its chains, so several relayers can run in one process.
+type MapContext struct {
+	lock         sync.RWMutex
+	conn         *ethclient.Client
+	lightManager common.Address
+	chains       map[msg.ChainId]*core.ChainConfig
+	syncedHeight map[msg.ChainId]*big.Int       // map to other chain init height
+	heightFns    map[msg.ChainId]GetHeight      // get map to other height
+	rangeFns     map[msg.ChainId]GetVerifyRange // get map to other verify range
+}
+
+func NewMapContext() *MapContext {
+	return &MapContext{
+		chains:       make(map[msg.ChainId]*core.ChainConfig),
+		syncedHeight: make(map[msg.ChainId]*big.Int),
+		heightFns:    make(map[msg.ChainId]GetHeight),
+		rangeFns:     make(map[msg.ChainId]GetVerifyRange),
+	}
+}
+
+// SetMapConn sets the connection of the map chain and the light client manager the heights are queried from
+func (c *MapContext) SetMapConn(conn *ethclient.Client, lightManager common.Address) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.conn = conn
+	c.lightManager = lightManager
+}
+
+// MapConn returns the connection of the map chain, nil before it is set
+func (c *MapContext) MapConn() *ethclient.Client {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	return c.conn
+}
+
+// AddChain makes the chain online for the relayer
+func (c *MapContext) AddChain(cfg *core.ChainConfig) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.chains[cfg.Id] = cfg
+}
+
+// ChainConfig returns the config of the online chain
+func (c *MapContext) ChainConfig(id msg.ChainId) (*core.ChainConfig, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	cfg, ok := c.chains[id]
+	return cfg, ok
+}
+
+// ChainName returns the name of the online chain
+func (c *MapContext) ChainName(id msg.ChainId) (string, bool) {
+	cfg, ok := c.ChainConfig(id)
+	if !ok {
+		return "", false
+	}
+	return cfg.Name, true
+}
+
+// Name returns the name of the chain for the logs and the alarms, empty when the chain is not online
+func (c *MapContext) Name(id msg.ChainId) string {
+	name, _ := c.ChainName(id)
+	return name
+}
+
+// SetMap2OtherHeight registers the height of the map light client on the chain and how to query it
+func (c *MapContext) SetMap2OtherHeight(id msg.ChainId, height *big.Int, fn GetHeight) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.syncedHeight[id] = height
+	c.heightFns[id] = fn
+}
+
+// SyncedHeight returns the height of the map light client on the chain when the chain is initialized
+func (c *MapContext) SyncedHeight(id msg.ChainId) (*big.Int, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	height, ok := c.syncedHeight[id]
+	return height, ok
+}
+
+// SyncedHeights returns a copy of the heights the map light clients had when their chains were initialized
+func (c *MapContext) SyncedHeights() map[msg.ChainId]*big.Int {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	ret := make(map[msg.ChainId]*big.Int, len(c.syncedHeight))
+	for id, height := range c.syncedHeight {
+		ret[id] = height
+	}
+	return ret
+}
+
+// Map2OtherHeight returns the query of the height of the map light client on the chain
+func (c *MapContext) Map2OtherHeight(id msg.ChainId) (GetHeight, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	fn, ok := c.heightFns[id]
+	return fn, ok
+}
+
+// SetMap2OtherVerifyRange registers how to query the verify range of the map light client on the chain
+func (c *MapContext) SetMap2OtherVerifyRange(id msg.ChainId, fn GetVerifyRange) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.rangeFns[id] = fn
+}
+
+// Map2OtherVerifyRange returns the query of the verify range of the map light client on the chain
+func (c *MapContext) Map2OtherVerifyRange(id msg.ChainId) (GetVerifyRange, bool) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+	fn, ok := c.rangeFns[id]
+	return fn, ok
+}
+
+func (c *MapContext) call(input []byte) ([]byte, error) {
+	c.lock.RLock()
+	conn, to := c.conn, c.lightManager
+	c.lock.RUnlock()
+	if conn == nil {
+		return nil, ErrMapNotConnected
+	}
+	return conn.CallContract(context.Background(), goeth.CallMsg{From: ZeroAddress, To: &to, Data: input}, nil)
+}
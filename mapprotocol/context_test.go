@@ -0,0 +1,66 @@
+package mapprotocol
+
+import (
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/mapprotocol/compass/core"
+	"github.com/mapprotocol/compass/msg"
+)
+
+func TestMapContext(t *testing.T) {
+	mainnet, testnet := NewMapContext(), NewMapContext()
+	mainnet.AddChain(&core.ChainConfig{Name: "bsc", Id: 56})
+	testnet.AddChain(&core.ChainConfig{Name: "bsc-testnet", Id: 97})
+	if name, ok := mainnet.ChainName(56); !ok || name != "bsc" {
+		t.Fatalf("chain name %q %v", name, ok)
+	}
+	if _, ok := mainnet.ChainName(97); ok {
+		t.Fatal("chain of another context is online")
+	}
+	if testnet.Name(56) != "" {
+		t.Fatal("chain of another context is named")
+	}
+
+	// the relayers set their chains concurrently
+	var wg sync.WaitGroup
+	for i := 1; i <= 20; i++ {
+		wg.Add(1)
+		go func(id msg.ChainId) {
+			defer wg.Done()
+			height := big.NewInt(int64(id) * 100)
+			mainnet.SetMap2OtherHeight(id, height, func() (*big.Int, error) { return height, nil })
+			mainnet.SetMap2OtherVerifyRange(id, func() (*big.Int, *big.Int, error) { return height, height, nil })
+			_ = mainnet.SyncedHeights()
+		}(msg.ChainId(i))
+	}
+	wg.Wait()
+
+	heights := mainnet.SyncedHeights()
+	if len(heights) != 20 || heights[7].Int64() != 700 {
+		t.Fatalf("synced heights %v", heights)
+	}
+	heights[7] = big.NewInt(0)
+	if h, _ := mainnet.SyncedHeight(7); h.Int64() != 700 {
+		t.Fatal("synced heights are not copied")
+	}
+	fn, ok := mainnet.Map2OtherHeight(3)
+	if !ok {
+		t.Fatal("height of chain 3 is not registered")
+	}
+	if h, _ := fn(); h.Int64() != 300 {
+		t.Fatalf("height of chain 3 %s", h)
+	}
+	if _, ok = testnet.Map2OtherVerifyRange(3); ok {
+		t.Fatal("verify range of another context is registered")
+	}
+
+	if _, err := testnet.Get2MapHeight(97); !errors.Is(err, ErrMapNotConnected) {
+		t.Fatalf("height without map %v", err)
+	}
+	if _, _, err := testnet.Get2MapVerifyRange(97); !errors.Is(err, ErrMapNotConnected) {
+		t.Fatalf("verify range without map %v", err)
+	}
+}
@@ -27,92 +27,73 @@ import (
 	"github.com/pkg/errors"
 )
 
-// GlobalMapConn global Map connection; assign at cmd/main
-var (
-	GlobalMapConn   *ethclient.Client
-	SyncOtherMap    = make(map[msg.ChainId]*big.Int)                                  // map to other chain init height
-	Map2OtherHeight = make(map[msg.ChainId]GetHeight)                                 // get map to other height function collect
-	Get2MapHeight   = func(chainId msg.ChainId) (*big.Int, error) { return nil, nil } // get other chain to map height
-	/*
-		InitLeftVerifyRange  = make(map[msg.ChainId]*big.Int)                                  // map to other chain init left verify range
-	*/
-	Map2OtherVerifyRange = make(map[msg.ChainId]GetVerifyRange)                                           // get map to other right verify range function collect
-	Get2MapVerifyRange   = func(chainId msg.ChainId) (*big.Int, *big.Int, error) { return nil, nil, nil } // get other chain to map verify height
-	Get2MapByLight       = func() (*big.Int, error) { return nil, nil }
-	GetEth22MapNumber    = func(chainId msg.ChainId) (*big.Int, *big.Int, error) { return nil, nil, nil }
-)
-
 type GetHeight func() (*big.Int, error)
 type GetVerifyRange func() (*big.Int, *big.Int, error)
 
-func Init2MapHeightByLight(lightNode common.Address) {
-	Get2MapByLight = func() (*big.Int, error) {
-		input, err := PackInput(Height, MethodOfHeaderHeight)
-		if err != nil {
-			return nil, errors.Wrap(err, "get other2map packInput failed")
-		}
+// Get2MapByLight returns the height of the light client on map the light manager is
+func (c *MapContext) Get2MapByLight() (*big.Int, error) {
+	input, err := PackInput(Height, MethodOfHeaderHeight)
+	if err != nil {
+		return nil, errors.Wrap(err, "get other2map packInput failed")
+	}
 
-		height, err := HeaderHeight(lightNode, input)
-		if err != nil {
-			return nil, errors.Wrap(err, "get other2map headerHeight failed")
-		}
-		return height, nil
+	height, err := c.HeaderHeight(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "get other2map headerHeight failed")
 	}
+	return height, nil
 }
 
-func Init2GetEth22MapNumber(lightNode common.Address) {
-	GetEth22MapNumber = func(chainId msg.ChainId) (*big.Int, *big.Int, error) {
-		input, err := PackInput(LightManger, MethodClientState, big.NewInt(int64(chainId)))
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "get eth22map packInput failed")
-		}
-
-		output, err := GlobalMapConn.CallContract(context.Background(),
-			goeth.CallMsg{From: ZeroAddress, To: &lightNode, Data: input}, nil)
-		if err != nil {
-			return nil, nil, err
-		}
+// GetEth22MapNumber returns the range of the numbers of the eth2 light client of the chain on map
+func (c *MapContext) GetEth22MapNumber(chainId msg.ChainId) (*big.Int, *big.Int, error) {
+	input, err := PackInput(LightManger, MethodClientState, big.NewInt(int64(chainId)))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get eth22map packInput failed")
+	}
 
-		outputs := LightManger.Methods[MethodClientState].Outputs
-		unpack, err := outputs.Unpack(output)
-		if err != nil {
-			return nil, nil, err
-		}
+	output, err := c.call(input)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		back := make([]byte, 0)
-		if err = outputs.Copy(&back, unpack); err != nil {
-			return nil, nil, err
-		}
+	outputs := LightManger.Methods[MethodClientState].Outputs
+	unpack, err := outputs.Unpack(output)
+	if err != nil {
+		return nil, nil, err
+	}
 
-		ret := struct {
-			StartNumber *big.Int
-			EndNumber   *big.Int
-		}{}
-		analysis, err := Eth2.Methods[MethodClientStateAnalysis].Outputs.Unpack(back)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "analysis")
-		}
-		if err = Eth2.Methods[MethodClientStateAnalysis].Outputs.Copy(&ret, analysis); err != nil {
-			return nil, nil, errors.Wrap(err, "analysis copy")
-		}
+	back := make([]byte, 0)
+	if err = outputs.Copy(&back, unpack); err != nil {
+		return nil, nil, err
+	}
 
-		return ret.StartNumber, ret.EndNumber, nil
+	ret := struct {
+		StartNumber *big.Int
+		EndNumber   *big.Int
+	}{}
+	analysis, err := Eth2.Methods[MethodClientStateAnalysis].Outputs.Unpack(back)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "analysis")
 	}
+	if err = Eth2.Methods[MethodClientStateAnalysis].Outputs.Copy(&ret, analysis); err != nil {
+		return nil, nil, errors.Wrap(err, "analysis copy")
+	}
+
+	return ret.StartNumber, ret.EndNumber, nil
 }
 
-func InitOtherChain2MapHeight(lightManager common.Address) {
-	Get2MapHeight = func(chainId msg.ChainId) (*big.Int, error) {
-		input, err := PackInput(LightManger, MethodOfHeaderHeight, big.NewInt(int64(chainId)))
-		if err != nil {
-			return nil, errors.Wrap(err, "get other2map packInput failed")
-		}
+// Get2MapHeight returns the height of the light client of the chain on map
+func (c *MapContext) Get2MapHeight(chainId msg.ChainId) (*big.Int, error) {
+	input, err := PackInput(LightManger, MethodOfHeaderHeight, big.NewInt(int64(chainId)))
+	if err != nil {
+		return nil, errors.Wrap(err, "get other2map packInput failed")
+	}
 
-		height, err := HeaderHeight(lightManager, input)
-		if err != nil {
-			return nil, errors.Wrap(err, "get other2map headerHeight by lightManager failed")
-		}
-		return height, nil
+	height, err := c.HeaderHeight(input)
+	if err != nil {
+		return nil, errors.Wrap(err, "get other2map headerHeight by lightManager failed")
 	}
+	return height, nil
 }
 
 func Map2EthHeight(fromUser string, lightNode common.Address, client *ethclient.Client) GetHeight {
@@ -183,8 +164,8 @@ func UnpackHeaderHeightOutput(output []byte) (*big.Int, error) {
 	return height, nil
 }
 
-func HeaderHeight(to common.Address, input []byte) (*big.Int, error) {
-	output, err := GlobalMapConn.CallContract(context.Background(), goeth.CallMsg{From: ZeroAddress, To: &to, Data: input}, nil)
+func (c *MapContext) HeaderHeight(input []byte) (*big.Int, error) {
+	output, err := c.call(input)
 	if err != nil {
 		return nil, err
 	}
@@ -195,19 +176,18 @@ func HeaderHeight(to common.Address, input []byte) (*big.Int, error) {
 	return height, nil
 }
 
-func InitOtherChain2MapVerifyRange(lightManager common.Address) {
-	Get2MapVerifyRange = func(chainId msg.ChainId) (*big.Int, *big.Int, error) {
-		input, err := PackInput(LightManger, MethodVerifiableHeaderRange, big.NewInt(int64(chainId)))
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "get other2map verifyRange packInput failed")
-		}
+// Get2MapVerifyRange returns the verify range of the light client of the chain on map
+func (c *MapContext) Get2MapVerifyRange(chainId msg.ChainId) (*big.Int, *big.Int, error) {
+	input, err := PackInput(LightManger, MethodVerifiableHeaderRange, big.NewInt(int64(chainId)))
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get other2map verifyRange packInput failed")
+	}
 
-		left, right, err := VerifyRange(lightManager, input)
-		if err != nil {
-			return nil, nil, errors.Wrap(err, "get other2map verifyRange by lightManager failed")
-		}
-		return left, right, nil
+	left, right, err := c.VerifyRange(input)
+	if err != nil {
+		return nil, nil, errors.Wrap(err, "get other2map verifyRange by lightManager failed")
 	}
+	return left, right, nil
 }
 
 func Map2EthVerifyRange(fromUser string, lightNode common.Address, client *ethclient.Client) GetVerifyRange {
@@ -257,8 +237,8 @@ func Map2NearVerifyRange(lightNode string, client *nearclient.Client) GetVerifyR
 	}
 }
 
-func VerifyRange(to common.Address, input []byte) (*big.Int, *big.Int, error) {
-	output, err := GlobalMapConn.CallContract(context.Background(), goeth.CallMsg{From: ZeroAddress, To: &to, Data: input}, nil)
+func (c *MapContext) VerifyRange(input []byte) (*big.Int, *big.Int, error) {
+	output, err := c.call(input)
 	if err != nil {
 		return nil, nil, err
 	}
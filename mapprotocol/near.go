@@ -13,14 +13,16 @@ import (
 const (
 	TransferOut = "transfer out"
 	DepositOut  = "deposit out"
+	SwapOut     = "swap out"
 )
 
 const (
 	HashOfTransferOut = "2ef1cdf83614a69568ed2c96a275dd7fb2e63a464aa3a0ffe79f55d538c8b3b5"
 	HashOfDepositOut  = "150bd848adaf4e3e699dcac82d75f111c078ce893375373593cc1b9208998377"
+	HashOfSwapOut     = "ca1cf8cebf88499429cca8f87cbca15ab8dafd06702259a5344ddce89ef3f3a5"
 )
 
-var NearEventType = []string{TransferOut, DepositOut}
+var NearEventType = []string{TransferOut, DepositOut, SwapOut}
 
 type StreamerMessage struct {
 	Block  client.BlockView `json:"block"`
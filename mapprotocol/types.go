@@ -7,7 +7,6 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	ethtypes "github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/mapprotocol/atlas/consensus/istanbul/validator"
 	"github.com/mapprotocol/atlas/core/types"
@@ -157,7 +156,7 @@ type NewReceiptProof struct {
 
 type TxProve struct {
 	Receipt     *ethtypes.Receipt
-	Prove       light.NodeList
+	Prove       []rlp.RawValue
 	BlockNumber uint64
 	TxIndex     uint
 }
@@ -0,0 +1,280 @@
+// Package bls verifies the bls signatures of the beacon chain, which bsc votes with too. Public keys are on g1 and
+// signatures on g2 in the compressed form of zcash, messages are hashed to g2 by the proof of possession ciphersuite
+package bls
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+var (
+	blsDST = []byte("BLS_SIG_BLS12381G2_XMD:SHA-256_SSWU_RO_POP_")
+
+	fpModulus, _   = new(big.Int).SetString("1a0111ea397fe69a4b1ba7b6434bacd764774b84f38512bf6730d2a0f6b0f6241eabfffeb153ffffb9feffffffffaaab", 16)
+	fpHalf         = new(big.Int).Rsh(fpModulus, 1)                                  // (p-1)/2
+	fpSqrtExp      = new(big.Int).Rsh(new(big.Int).Sub(fpModulus, big.NewInt(3)), 2) // (p-3)/4
+	errInvalidBLS  = errors.New("invalid bls point")
+	errInfinityBLS = errors.New("bls point at infinity")
+)
+
+const (
+	PublicKeyLength = 48
+	SignatureLength = 96
+)
+
+const (
+	blsFlagCompressed = 0x80
+	blsFlagInfinity   = 0x40
+	blsFlagSign       = 0x20
+)
+
+// PublicKeyFromBytes decodes a compressed public key, checking it is in the subgroup and not the infinity
+func PublicKeyFromBytes(in []byte) (*bls12381.PointG1, error) {
+	x, largest, err := decodeCompressed(in, PublicKeyLength)
+	if err != nil {
+		return nil, err
+	}
+	// y^2 = x^3 + 4
+	y2 := new(big.Int).Exp(x, big.NewInt(3), fpModulus)
+	y2.Add(y2, big.NewInt(4)).Mod(y2, fpModulus)
+	y := new(big.Int).ModSqrt(y2, fpModulus)
+	if y == nil {
+		return nil, fmt.Errorf("%w: x is not on g1", errInvalidBLS)
+	}
+	if (y.Cmp(fpHalf) > 0) != largest {
+		y.Sub(fpModulus, y)
+	}
+	raw := make([]byte, 96)
+	x.FillBytes(raw[:48])
+	y.FillBytes(raw[48:])
+	g := bls12381.NewG1()
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidBLS, err)
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, fmt.Errorf("%w: not in the subgroup of g1", errInvalidBLS)
+	}
+	return p, nil
+}
+
+// decompressG2 decodes a compressed signature, checking it is in the subgroup and not the infinity
+func decompressG2(in []byte) (*bls12381.PointG2, error) {
+	if len(in) != SignatureLength {
+		return nil, fmt.Errorf("%w: %d bytes, want %d", errInvalidBLS, len(in), SignatureLength)
+	}
+	x1, largest, err := decodeCompressed(in[:48], 48)
+	if err != nil {
+		return nil, err
+	}
+	x0 := new(big.Int).SetBytes(in[48:])
+	if x0.Cmp(fpModulus) >= 0 {
+		return nil, fmt.Errorf("%w: x is not a field element", errInvalidBLS)
+	}
+	// y^2 = x^3 + 4(1+i)
+	x := fp2{x0, x1}
+	y2 := x.mul(x).mul(x).add(fp2{big.NewInt(4), big.NewInt(4)})
+	y, ok := y2.sqrt()
+	if !ok {
+		return nil, fmt.Errorf("%w: x is not on g2", errInvalidBLS)
+	}
+	if y.largest() != largest {
+		y = y.neg()
+	}
+	raw := make([]byte, 192)
+	x1.FillBytes(raw[:48])
+	x0.FillBytes(raw[48:96])
+	y[1].FillBytes(raw[96:144])
+	y[0].FillBytes(raw[144:])
+	g := bls12381.NewG2()
+	p, err := g.FromBytes(raw)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", errInvalidBLS, err)
+	}
+	if !g.InCorrectSubgroup(p) {
+		return nil, fmt.Errorf("%w: not in the subgroup of g2", errInvalidBLS)
+	}
+	return p, nil
+}
+
+// decodeCompressed returns x of a compressed point and whether y is the lexicographically largest
+func decodeCompressed(in []byte, size int) (*big.Int, bool, error) {
+	if len(in) != size {
+		return nil, false, fmt.Errorf("%w: %d bytes, want %d", errInvalidBLS, len(in), size)
+	}
+	if in[0]&blsFlagCompressed == 0 {
+		return nil, false, fmt.Errorf("%w: not compressed", errInvalidBLS)
+	}
+	if in[0]&blsFlagInfinity != 0 {
+		return nil, false, errInfinityBLS
+	}
+	b := append([]byte{}, in...)
+	b[0] &^= blsFlagCompressed | blsFlagInfinity | blsFlagSign
+	x := new(big.Int).SetBytes(b)
+	if x.Cmp(fpModulus) >= 0 {
+		return nil, false, fmt.Errorf("%w: x is not a field element", errInvalidBLS)
+	}
+	return x, in[0]&blsFlagSign != 0, nil
+}
+
+// CompressG1 encodes the public key in the compressed form
+func CompressG1(p *bls12381.PointG1) []byte {
+	raw := bls12381.NewG1().ToBytes(p)
+	out := append([]byte{}, raw[:48]...)
+	out[0] |= blsFlagCompressed
+	if new(big.Int).SetBytes(raw[48:]).Cmp(fpHalf) > 0 {
+		out[0] |= blsFlagSign
+	}
+	return out
+}
+
+// CompressG2 encodes the signature in the compressed form
+func CompressG2(p *bls12381.PointG2) []byte {
+	raw := bls12381.NewG2().ToBytes(p)
+	out := append([]byte{}, raw[:96]...)
+	out[0] |= blsFlagCompressed
+	y := fp2{new(big.Int).SetBytes(raw[144:]), new(big.Int).SetBytes(raw[96:144])}
+	if y.largest() {
+		out[0] |= blsFlagSign
+	}
+	return out
+}
+
+// FastAggregateVerify checks the signature of the message by all of the public keys
+func FastAggregateVerify(pubkeys []*bls12381.PointG1, message, signature []byte) error {
+	if len(pubkeys) == 0 {
+		return errors.New("no public keys")
+	}
+	sig, err := decompressG2(signature)
+	if err != nil {
+		return err
+	}
+	g1 := bls12381.NewG1()
+	agg := g1.Zero()
+	for _, pk := range pubkeys {
+		g1.Add(agg, agg, pk)
+	}
+	h, err := HashToG2(message)
+	if err != nil {
+		return err
+	}
+	// e(pk, H(m)) == e(g1, sig)
+	if !bls12381.NewPairingEngine().AddPair(agg, h).AddPairInv(g1.One(), sig).Check() {
+		return errors.New("invalid bls signature")
+	}
+	return nil
+}
+
+// HashToG2 is hash_to_curve of the ciphersuite, the map of geth clears the cofactor of each point, which is the
+// same as clearing it of the sum
+func HashToG2(message []byte) (*bls12381.PointG2, error) {
+	uniform := expandMessageXMD(message, blsDST, 256)
+	g := bls12381.NewG2()
+	ret := g.Zero()
+	for i := 0; i < 2; i++ {
+		c0 := new(big.Int).SetBytes(uniform[i*128 : i*128+64])
+		c1 := new(big.Int).SetBytes(uniform[i*128+64 : i*128+128])
+		in := make([]byte, 96)
+		c1.Mod(c1, fpModulus).FillBytes(in[:48])
+		c0.Mod(c0, fpModulus).FillBytes(in[48:])
+		p, err := g.MapToCurve(in)
+		if err != nil {
+			return nil, err
+		}
+		g.Add(ret, ret, p)
+	}
+	return g.Affine(ret), nil
+}
+
+// expandMessageXMD is expand_message_xmd of rfc 9380 with sha256
+func expandMessageXMD(message, dst []byte, length int) []byte {
+	dstPrime := append(append([]byte{}, dst...), byte(len(dst)))
+	h := sha256.New()
+	h.Write(make([]byte, h.BlockSize()))
+	h.Write(message)
+	h.Write([]byte{byte(length >> 8), byte(length), 0})
+	h.Write(dstPrime)
+	b0 := h.Sum(nil)
+
+	out := make([]byte, 0, length)
+	prev := make([]byte, sha256.Size)
+	for i := 1; len(out) < length; i++ {
+		xor := make([]byte, sha256.Size)
+		for j := range xor {
+			xor[j] = b0[j] ^ prev[j]
+		}
+		h.Reset()
+		h.Write(xor)
+		h.Write([]byte{byte(i)})
+		h.Write(dstPrime)
+		prev = h.Sum(nil)
+		out = append(out, prev...)
+	}
+	return out[:length]
+}
+
+// fp2 is c0 + c1*i of the quadratic extension, only what decompression needs
+type fp2 [2]*big.Int
+
+func (a fp2) add(b fp2) fp2 {
+	return fp2{
+		new(big.Int).Mod(new(big.Int).Add(a[0], b[0]), fpModulus),
+		new(big.Int).Mod(new(big.Int).Add(a[1], b[1]), fpModulus),
+	}
+}
+
+func (a fp2) mul(b fp2) fp2 {
+	re := new(big.Int).Sub(new(big.Int).Mul(a[0], b[0]), new(big.Int).Mul(a[1], b[1]))
+	im := new(big.Int).Add(new(big.Int).Mul(a[0], b[1]), new(big.Int).Mul(a[1], b[0]))
+	return fp2{re.Mod(re, fpModulus), im.Mod(im, fpModulus)}
+}
+
+func (a fp2) neg() fp2 {
+	return fp2{
+		new(big.Int).Mod(new(big.Int).Neg(a[0]), fpModulus),
+		new(big.Int).Mod(new(big.Int).Neg(a[1]), fpModulus),
+	}
+}
+
+func (a fp2) exp(e *big.Int) fp2 {
+	ret := fp2{big.NewInt(1), big.NewInt(0)}
+	for i := e.BitLen() - 1; i >= 0; i-- {
+		ret = ret.mul(ret)
+		if e.Bit(i) == 1 {
+			ret = ret.mul(a)
+		}
+	}
+	return ret
+}
+
+func (a fp2) equal(b fp2) bool {
+	return a[0].Cmp(b[0]) == 0 && a[1].Cmp(b[1]) == 0
+}
+
+// largest is the sign of zcash, c1 decides unless it is zero
+func (a fp2) largest() bool {
+	if a[1].Sign() != 0 {
+		return a[1].Cmp(fpHalf) > 0
+	}
+	return a[0].Cmp(fpHalf) > 0
+}
+
+// sqrt is algorithm 9 of https://eprint.iacr.org/2012/685.pdf for p = 3 mod 4
+func (a fp2) sqrt() (fp2, bool) {
+	a1 := a.exp(fpSqrtExp)
+	alpha := a1.mul(a1).mul(a)
+	x0 := a1.mul(a)
+	minusOne := fp2{new(big.Int).Sub(fpModulus, big.NewInt(1)), big.NewInt(0)}
+	var x fp2
+	if alpha.equal(minusOne) {
+		x = fp2{new(big.Int).Mod(new(big.Int).Neg(x0[1]), fpModulus), x0[0]} // i * x0
+	} else {
+		b := alpha.add(fp2{big.NewInt(1), big.NewInt(0)}).exp(fpHalf)
+		x = b.mul(x0)
+	}
+	return x, x.mul(x).equal(a)
+}
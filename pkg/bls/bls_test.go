@@ -0,0 +1,70 @@
+package bls
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto/bls12381"
+)
+
+func TestDecompressGenerators(t *testing.T) {
+	g1 := bls12381.NewG1()
+	p, err := PublicKeyFromBytes(common.FromHex("0x97f1d3a73197d7942695638c4fa9ac0fc3688c4f9774b905a14e3a3f171bac586c55e83ff97a1aeffb3af00adb22c6bb"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g1.Equal(p, g1.One()) {
+		t.Fatal("not the generator of g1")
+	}
+	g2 := bls12381.NewG2()
+	q, err := decompressG2(common.FromHex("0x93e02b6052719f607dacd3a088274f65596bd0d09920b61ab5da61bbdc7f5049334cf11213945d57e5ac7d055d042b7e024aa2b2f08f0a91260805272dc51051c6e47ad4fa403b02b4510b647ae3d1770bac0326a805bbefd48056c8c121bdb8"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !g2.Equal(q, g2.One()) {
+		t.Fatal("not the generator of g2")
+	}
+}
+
+// the first test vector of BLS12381G2_XMD:SHA-256_SSWU_RO_ in rfc 9380
+func TestHashToG2(t *testing.T) {
+	dst := blsDST
+	blsDST = []byte("QUUX-V01-CS02-with-BLS12381G2_XMD:SHA-256_SSWU_RO_")
+	defer func() { blsDST = dst }()
+
+	p, err := HashToG2(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	raw := bls12381.NewG2().ToBytes(p)
+	want := common.FromHex("0x05cb8437535e20ecffaef7752baddf98034139c38452458baeefab379ba13dff5bf5dd71b72418717047f5b0f37da03d" +
+		"0141ebfbdca40eb85b87142e130ab689c673cf60f1a3e98d69335266f30d9b8d4ac44c1038e9dcdd5393faf5c41fb78a" +
+		"12424ac32561493f3fe3c260708a12b7c620e7be00099a974e259ddc7d1f6395c3c811cdd19f1e8dbf3e9ecfdcbab8d6" +
+		"0503921d7f6a12805e72940b963c0cf3471c7b2a524950ca195d11062ee75ec076daf2d4bc358c4b190c0c98064fdd92")
+	if common.Bytes2Hex(raw) != common.Bytes2Hex(want) {
+		t.Fatalf("hash of the empty message is %x", raw)
+	}
+}
+
+// a sign case of the bls tests of the consensus specs
+func TestFastAggregateVerify(t *testing.T) {
+	pk, err := PublicKeyFromBytes(common.FromHex("0xa491d1b0ecd9bb917989f0e74f0dea0422eac4a873e5e2644f368dffb9a6e20fd6e10c1b77654d067c0618f6e5a7f79a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	sk, _ := new(big.Int).SetString("263dbd792f5b1be47ed85f8938c0f29586af0d3ac7b977f21c278fe1462040e3", 16)
+	g1 := bls12381.NewG1()
+	if !g1.Equal(pk, g1.MulScalar(g1.New(), g1.One(), sk)) {
+		t.Fatal("public key is not of the secret key")
+	}
+	msg := make([]byte, 32)
+	sig := common.FromHex("0xb6ed936746e01f8ecf281f020953fbf1f01debd5657c4a383940b020b26507f6076334f91e2366c96e9ab279fb5158090352ea1c5b0c9274504f4f0e7053af24802e51e4568d164fe986834f41e55c8e850ce1f98458c0cfc9ab380b55285a55")
+	if err = FastAggregateVerify([]*bls12381.PointG1{pk}, msg, sig); err != nil {
+		t.Fatal(err)
+	}
+	msg[0] = 1
+	if err = FastAggregateVerify([]*bls12381.PointG1{pk}, msg, sig); err == nil {
+		t.Fatal("expected error of another message")
+	}
+}
@@ -0,0 +1,108 @@
+// Package receipttrie builds the receipt trie of a block, the trie whose root is the receiptsRoot of the
+// header, and assembles the merkle proof of a receipt in it. It keeps the trie in memory and does not
+// depend on the go-ethereum trie database, so the proofs are the same whatever the version of geth.
+package receipttrie
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// DerivableList is a list of values stored in a trie keyed by rlp(index), as types.DerivableList.
+type DerivableList interface {
+	Len() int
+	EncodeIndex(int, *bytes.Buffer)
+}
+
+// Receipts implements DerivableList with the consensus encoding of any receipt type. Unlike
+// types.Receipts of the geth version in use, typed receipts unknown to geth (EIP-4844 blob receipts
+// for example) are encoded too, as type || rlp([status, cumulativeGasUsed, bloom, logs]).
+type Receipts []*types.Receipt
+
+// Len returns the number of receipts in this list.
+func (rs Receipts) Len() int { return len(rs) }
+
+// EncodeIndex encodes the i'th receipt to w.
+func (rs Receipts) EncodeIndex(i int, w *bytes.Buffer) {
+	r := rs[i]
+	if r.Type != types.LegacyTxType {
+		w.WriteByte(r.Type)
+	}
+	_ = rlp.Encode(w, &receiptRLP{
+		PostStateOrStatus: statusEncoding(r),
+		CumulativeGasUsed: r.CumulativeGasUsed,
+		Bloom:             r.Bloom,
+		Logs:              r.Logs,
+	})
+}
+
+type receiptRLP struct {
+	PostStateOrStatus []byte
+	CumulativeGasUsed uint64
+	Bloom             types.Bloom
+	Logs              []*types.Log
+}
+
+var (
+	receiptStatusFailedRLP     = []byte{}
+	receiptStatusSuccessfulRLP = []byte{0x01}
+)
+
+func statusEncoding(r *types.Receipt) []byte {
+	if len(r.PostState) != 0 {
+		return r.PostState
+	}
+	if r.Status == types.ReceiptStatusFailed {
+		return receiptStatusFailedRLP
+	}
+	return receiptStatusSuccessfulRLP
+}
+
+// Encode returns the consensus encoding of the receipt, the value of the receipt in the trie.
+func Encode(r *types.Receipt) []byte {
+	buf := new(bytes.Buffer)
+	Receipts{r}.EncodeIndex(0, buf)
+	return buf.Bytes()
+}
+
+// New builds the trie of the list. Values are inserted in the same order as types.DeriveSha.
+func New(list DerivableList) *Trie {
+	var (
+		t     = &Trie{}
+		buf   = new(bytes.Buffer)
+		index []byte
+	)
+	insert := func(i int) {
+		buf.Reset()
+		list.EncodeIndex(i, buf)
+		index = rlp.AppendUint64(index[:0], uint64(i))
+		t.Update(index, common.CopyBytes(buf.Bytes()))
+	}
+	for i := 1; i < list.Len() && i <= 0x7f; i++ {
+		insert(i)
+	}
+	if list.Len() > 0 {
+		insert(0)
+	}
+	for i := 0x80; i < list.Len(); i++ {
+		insert(i)
+	}
+	return t
+}
+
+// DeriveRoot returns the root of the trie of the list, which is the receiptsRoot of the block for receipts.
+func DeriveRoot(list DerivableList) common.Hash {
+	return New(list).Hash()
+}
+
+// Prove returns the proof nodes of the index'th value of the list, from the root down.
+func Prove(list DerivableList, index uint) ([][]byte, error) {
+	if index >= uint(list.Len()) {
+		return nil, fmt.Errorf("index %d out of range, list has %d values", index, list.Len())
+	}
+	return New(list).Prove(rlp.AppendUint64(nil, uint64(index)))
+}
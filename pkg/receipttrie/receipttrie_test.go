@@ -0,0 +1,133 @@
+package receipttrie
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// block is a golden block of testdata, the receipts with the receiptsRoot of the header
+type block struct {
+	Number       uint64           `json:"number"`
+	ReceiptsRoot common.Hash      `json:"receiptsRoot"`
+	Receipts     []*types.Receipt `json:"receipts"`
+}
+
+func loadBlock(t *testing.T, name string) *block {
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	b := &block{}
+	if err = json.Unmarshal(data, b); err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+// verifyProof checks the proof with the trie of geth, which is independent of the trie of this package
+func verifyProof(t *testing.T, root common.Hash, index uint, nodes [][]byte, want []byte) {
+	db := memorydb.New()
+	for _, n := range nodes {
+		if err := db.Put(crypto.Keccak256(n), n); err != nil {
+			t.Fatal(err)
+		}
+	}
+	value, err := trie.VerifyProof(root, rlp.AppendUint64(nil, uint64(index)), db)
+	if err != nil {
+		t.Fatalf("index %d: %v", index, err)
+	}
+	if !bytes.Equal(value, want) {
+		t.Fatalf("index %d: value %x, want %x", index, value, want)
+	}
+}
+
+func TestGolden(t *testing.T) {
+	for _, name := range []string{"legacy.json", "typed.json", "single.json"} {
+		b := loadBlock(t, name)
+		rs := Receipts(b.Receipts)
+		if root := DeriveRoot(rs); root != b.ReceiptsRoot {
+			t.Fatalf("%s: root %s, header has %s", name, root.Hex(), b.ReceiptsRoot.Hex())
+		}
+		for i := range rs {
+			nodes, err := Prove(rs, uint(i))
+			if err != nil {
+				t.Fatalf("%s: %v", name, err)
+			}
+			verifyProof(t, b.ReceiptsRoot, uint(i), nodes, Encode(rs[i]))
+		}
+	}
+}
+
+func TestEncodeBlobReceipt(t *testing.T) {
+	b := loadBlock(t, "typed.json")
+	for _, r := range b.Receipts {
+		// the rlp of a typed receipt in geth is its consensus encoding as a string, whatever the type
+		env, err := rlp.EncodeToBytes(r)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := env
+		if r.Type != types.LegacyTxType {
+			if err = rlp.DecodeBytes(env, &want); err != nil {
+				t.Fatal(err)
+			}
+		}
+		if got := Encode(r); !bytes.Equal(got, want) {
+			t.Fatalf("type %d: encoding %x, want %x", r.Type, got, want)
+		}
+	}
+}
+
+func TestDeriveSha(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 16, 127, 128, 129, 300} {
+		rs := make(types.Receipts, 0, n)
+		for i := 0; i < n; i++ {
+			r := &types.Receipt{
+				Type:              uint8(i % 3),
+				Status:            uint64(i % 2),
+				CumulativeGasUsed: uint64(21000 * (i + 1)),
+				Logs: []*types.Log{{
+					Address: common.BigToAddress(big.NewInt(int64(i))),
+					Topics:  []common.Hash{common.BigToHash(big.NewInt(int64(i)))},
+					Data:    []byte{byte(i)},
+				}},
+			}
+			r.Bloom = types.CreateBloom(types.Receipts{r})
+			rs = append(rs, r)
+		}
+		want := types.DeriveSha(rs, trie.NewStackTrie(nil))
+		if root := DeriveRoot(Receipts(rs)); root != want {
+			t.Fatalf("receipts %d: root %s, want %s", n, root.Hex(), want.Hex())
+		}
+		if n == 0 {
+			if want != EmptyRoot {
+				t.Fatalf("empty root %s", want.Hex())
+			}
+			continue
+		}
+		for _, i := range []int{0, n / 2, n - 1} {
+			nodes, err := Prove(Receipts(rs), uint(i))
+			if err != nil {
+				t.Fatal(err)
+			}
+			verifyProof(t, want, uint(i), nodes, Encode(rs[i]))
+		}
+	}
+}
+
+func TestProveOutOfRange(t *testing.T) {
+	if _, err := Prove(Receipts{}, 0); err == nil {
+		t.Fatal("expected error for an empty list")
+	}
+}
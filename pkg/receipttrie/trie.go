@@ -0,0 +1,198 @@
+package receipttrie
+
+import (
+	"bytes"
+	"errors"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// EmptyRoot is the root of a trie without values.
+var EmptyRoot = common.HexToHash("56e81f171bcc55a6ff8345e692c0f86e5b48e01b996cadc001622fb5e363b421")
+
+var errNotFound = errors.New("key not found in trie")
+
+// Trie is an in-memory merkle patricia trie, enough to derive the root of a block list and prove its values.
+type Trie struct {
+	root node
+}
+
+type (
+	node interface{}
+
+	fullNode struct {
+		children [17]node // the last child is the value of the key ending at the node
+	}
+	shortNode struct {
+		key []byte // nibbles, terminated by 16 when val is a value
+		val node
+	}
+	valueNode []byte
+)
+
+// Update sets the value of the key, an empty value is not supported as nothing is deleted from the trie.
+func (t *Trie) Update(key, value []byte) {
+	t.root = insert(t.root, keyToHex(key), valueNode(value))
+}
+
+// Hash returns the root hash of the trie.
+func (t *Trie) Hash() common.Hash {
+	if t.root == nil {
+		return EmptyRoot
+	}
+	return crypto.Keccak256Hash(encode(t.root))
+}
+
+// Prove returns the encoded nodes on the path of the key, from the root down. Nodes shorter than 32 bytes
+// are embedded in their parent and not in the list, except for the root, as with trie.Prove of geth.
+func (t *Trie) Prove(key []byte) ([][]byte, error) {
+	var (
+		proof [][]byte
+		hex   = keyToHex(key)
+		n     = t.root
+	)
+	for len(hex) > 0 {
+		var next node
+		switch n := n.(type) {
+		case *shortNode:
+			if !bytes.HasPrefix(hex, n.key) {
+				return nil, errNotFound
+			}
+			hex = hex[len(n.key):]
+			next = n.val
+		case *fullNode:
+			next = n.children[hex[0]]
+			hex = hex[1:]
+		default:
+			return nil, errNotFound
+		}
+		enc := encode(n)
+		if len(proof) == 0 || len(enc) >= common.HashLength {
+			proof = append(proof, enc)
+		}
+		n = next
+	}
+	if _, ok := n.(valueNode); !ok {
+		return nil, errNotFound
+	}
+	return proof, nil
+}
+
+func insert(n node, key []byte, value valueNode) node {
+	if len(key) == 0 {
+		return value
+	}
+	switch n := n.(type) {
+	case nil:
+		return &shortNode{key: key, val: value}
+	case *shortNode:
+		matched := prefixLen(key, n.key)
+		if matched == len(n.key) {
+			n.val = insert(n.val, key[matched:], value)
+			return n
+		}
+		branch := &fullNode{}
+		branch.children[n.key[matched]] = short(n.key[matched+1:], n.val)
+		branch.children[key[matched]] = short(key[matched+1:], value)
+		if matched == 0 {
+			return branch
+		}
+		return &shortNode{key: key[:matched], val: branch}
+	case *fullNode:
+		n.children[key[0]] = insert(n.children[key[0]], key[1:], value)
+		return n
+	default:
+		// a value with a longer key, only possible when a key is a prefix of another
+		branch := &fullNode{}
+		branch.children[16] = n
+		branch.children[key[0]] = short(key[1:], value)
+		return branch
+	}
+}
+
+func short(key []byte, val node) node {
+	if len(key) == 0 {
+		return val
+	}
+	return &shortNode{key: key, val: val}
+}
+
+// encode returns the rlp of the node, with children of 32 bytes or more referenced by hash
+func encode(n node) []byte {
+	switch n := n.(type) {
+	case *shortNode:
+		return encodeList([][]byte{encodeString(hexToCompact(n.key)), ref(n.val)})
+	case *fullNode:
+		elems := make([][]byte, 17)
+		for i, child := range n.children {
+			elems[i] = ref(child)
+		}
+		return encodeList(elems)
+	case valueNode:
+		return encodeString(n)
+	default:
+		return encodeString(nil)
+	}
+}
+
+func ref(n node) []byte {
+	if n == nil {
+		return encodeString(nil)
+	}
+	enc := encode(n)
+	if _, ok := n.(valueNode); ok || len(enc) < common.HashLength {
+		return enc
+	}
+	return encodeString(crypto.Keccak256(enc))
+}
+
+func encodeString(b []byte) []byte {
+	enc, _ := rlp.EncodeToBytes(b)
+	return enc
+}
+
+func encodeList(elems [][]byte) []byte {
+	raws := make([]rlp.RawValue, len(elems))
+	for i := range elems {
+		raws[i] = elems[i]
+	}
+	enc, _ := rlp.EncodeToBytes(raws)
+	return enc
+}
+
+func prefixLen(a, b []byte) int {
+	i := 0
+	for i < len(a) && i < len(b) && a[i] == b[i] {
+		i++
+	}
+	return i
+}
+
+func keyToHex(key []byte) []byte {
+	hex := make([]byte, 0, len(key)*2+1)
+	for _, b := range key {
+		hex = append(hex, b/16, b%16)
+	}
+	return append(hex, 16)
+}
+
+// hexToCompact is the hex-prefix encoding of the nibbles of a short node
+func hexToCompact(hex []byte) []byte {
+	var flag byte
+	if len(hex) > 0 && hex[len(hex)-1] == 16 {
+		flag = 2
+		hex = hex[:len(hex)-1]
+	}
+	buf := make([]byte, len(hex)/2+1)
+	buf[0] = flag << 4
+	if len(hex)&1 == 1 {
+		buf[0] |= (1 << 4) | hex[0]
+		hex = hex[1:]
+	}
+	for i := 0; i < len(hex); i += 2 {
+		buf[i/2+1] = hex[i]<<4 | hex[i+1]
+	}
+	return buf
+}
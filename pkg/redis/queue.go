@@ -0,0 +1,52 @@
+package redis
+
+import (
+	"context"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// ProcessingPostfix is appended to the key of the list to get the list of the messages taken but not acked
+const ProcessingPostfix = "_processing"
+
+// lister is the list commands of redis the queue uses
+type lister interface {
+	LIndex(ctx context.Context, key string, index int64) *redis.StringCmd
+	RPopLPush(ctx context.Context, source, destination string) *redis.StringCmd
+	LRem(ctx context.Context, key string, count int64, value interface{}) *redis.IntCmd
+}
+
+// Queue consumes the messages pushed to the left of a list. A message taken is moved to the processing list
+// atomically, and stays there until it is acked, so a message failing to be handled or interrupted by a crash is
+// taken again before the newer ones.
+type Queue struct {
+	client     lister
+	key        string
+	processing string
+}
+
+func NewQueue(client lister, key string) *Queue {
+	return &Queue{client: client, key: key, processing: key + ProcessingPostfix}
+}
+
+// Next returns the oldest message not acked, or the next one of the list. An empty string is returned when the
+// list is empty.
+func (q *Queue) Next(ctx context.Context) (string, error) {
+	msg, err := q.client.LIndex(ctx, q.processing, -1).Result()
+	if err == nil {
+		return msg, nil
+	}
+	if err != redis.Nil {
+		return "", err
+	}
+	msg, err = q.client.RPopLPush(ctx, q.key, q.processing).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return msg, err
+}
+
+// Ack removes the message from the processing list, it must be called only when the message is handled
+func (q *Queue) Ack(ctx context.Context, msg string) error {
+	return q.client.LRem(ctx, q.processing, -1, msg).Err()
+}
@@ -0,0 +1,92 @@
+package redis
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// memLists is an in-memory stand-in of the list commands, the lists are stored from left to right
+type memLists map[string][]string
+
+func (m memLists) LIndex(_ context.Context, key string, index int64) *redis.StringCmd {
+	l := m[key]
+	if index < 0 {
+		index += int64(len(l))
+	}
+	if index < 0 || index >= int64(len(l)) {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	return redis.NewStringResult(l[index], nil)
+}
+
+func (m memLists) RPopLPush(_ context.Context, source, destination string) *redis.StringCmd {
+	l := m[source]
+	if len(l) == 0 {
+		return redis.NewStringResult("", redis.Nil)
+	}
+	v := l[len(l)-1]
+	m[source] = l[:len(l)-1]
+	m[destination] = append([]string{v}, m[destination]...)
+	return redis.NewStringResult(v, nil)
+}
+
+func (m memLists) LRem(_ context.Context, key string, count int64, value interface{}) *redis.IntCmd {
+	if count != -1 {
+		panic("only the last one is removed")
+	}
+	l := m[key]
+	for i := len(l) - 1; i >= 0; i-- {
+		if l[i] == value {
+			m[key] = append(l[:i:i], l[i+1:]...)
+			return redis.NewIntResult(1, nil)
+		}
+	}
+	return redis.NewIntResult(0, nil)
+}
+
+func (m memLists) lpush(key string, vs ...string) {
+	for _, v := range vs {
+		m[key] = append([]string{v}, m[key]...)
+	}
+}
+
+func TestQueue(t *testing.T) {
+	ctx := context.Background()
+	lists := memLists{}
+	q := NewQueue(lists, ListKey)
+	if msg, err := q.Next(ctx); err != nil || msg != "" {
+		t.Fatalf("empty queue returned %q, %v", msg, err)
+	}
+
+	lists.lpush(ListKey, "m1", "m2", "m3")
+	msg, err := q.Next(ctx)
+	if err != nil || msg != "m1" {
+		t.Fatalf("next %q, %v", msg, err)
+	}
+	// not acked, it is taken again before the newer ones
+	if msg, _ = q.Next(ctx); msg != "m1" {
+		t.Fatalf("next %q, want m1 again", msg)
+	}
+	if err = q.Ack(ctx, msg); err != nil {
+		t.Fatal(err)
+	}
+	if msg, _ = q.Next(ctx); msg != "m2" {
+		t.Fatalf("next %q, want m2", msg)
+	}
+
+	// a consumer restarted before the ack reclaims the message
+	q = NewQueue(lists, ListKey)
+	if msg, _ = q.Next(ctx); msg != "m2" {
+		t.Fatalf("reclaimed %q, want m2", msg)
+	}
+	_ = q.Ack(ctx, msg)
+	if msg, _ = q.Next(ctx); msg != "m3" {
+		t.Fatalf("next %q, want m3", msg)
+	}
+	_ = q.Ack(ctx, msg)
+	if len(lists[ListKey]) != 0 || len(lists[ListKey+ProcessingPostfix]) != 0 {
+		t.Fatalf("lists left %v", lists)
+	}
+}
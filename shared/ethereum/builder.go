@@ -0,0 +1,110 @@
+// Copyright 2021 Compass Systems
+// SPDX-License-Identifier: LGPL-3.0-only
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/mapprotocol/compass/chains"
+	iproof "github.com/mapprotocol/compass/internal/proof"
+	"github.com/mapprotocol/compass/mapprotocol"
+	"github.com/mapprotocol/compass/msg"
+	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
+)
+
+func init() {
+	iproof.Register(chains.Map, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		return &MapBuilder{client: cfg.Client, mc: cfg.Map, fId: cfg.Id}, nil
+	})
+	iproof.Register(chains.Ethereum, func(cfg *iproof.Config) (iproof.ProofBuilder, error) {
+		return &EthBuilder{client: cfg.Client, mcs: cfg.Mcs, fId: cfg.Id, tId: cfg.MapChainID}, nil
+	})
+}
+
+// MapBuilder assembles the proof of a map log for the chain the log is sent to
+type MapBuilder struct {
+	client *ethclient.Client
+	mc     *mapprotocol.MapContext
+	fId    msg.ChainId
+}
+
+// Receipts of the last block in an epoch also contain the receipt of the epoch change
+func (b *MapBuilder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	txsHash, err := mapprotocol.GetMapTransactionsHashByBlockNumber(b.client, number)
+	if err != nil {
+		return nil, fmt.Errorf("idSame unable to get tx hashes Logs: %w", err)
+	}
+	receipts, err := mapprotocol.GetReceiptsByTxsHash(b.client, txsHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
+	}
+	remainder := big.NewInt(0).Mod(number, big.NewInt(mapprotocol.EpochOfMap))
+	if remainder.Cmp(mapprotocol.Big0) == 0 {
+		lr, err := mapprotocol.GetLastReceipt(b.client, number)
+		if err != nil {
+			return nil, fmt.Errorf("unable to get last receipts in epoch last %w", err)
+		}
+		receipts = append(receipts, lr)
+	}
+	return receipts, nil
+}
+
+func (b *MapBuilder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	header, err := b.client.MAPHeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, fmt.Errorf("unable to query header Logs: %w", err)
+	}
+	return AssembleMapProof(b.client, b.mc, *log, receipts, header, b.fId, iproof.Method(log.Topics[0]))
+}
+
+// EthBuilder assembles the proof of a log on an ethereum chain for map
+type EthBuilder struct {
+	client *ethclient.Client
+	mcs    common.Address
+	fId    msg.ChainId
+	tId    msg.ChainId
+}
+
+func (b *EthBuilder) Receipts(_ context.Context, number *big.Int) ([]*types.Receipt, error) {
+	txsHash, err := mapprotocol.GetTransactionsHashByBlockNumber(b.client, number)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get tx hashes Logs: %w", err)
+	}
+	receipts, err := mapprotocol.GetReceiptsByTxsHash(b.client, txsHash)
+	if err != nil {
+		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
+	}
+	return receipts, nil
+}
+
+func (b *EthBuilder) Build(ctx context.Context, log *types.Log, receipts []*types.Receipt) (*iproof.Proof, error) {
+	method := iproof.Method(log.Topics[0])
+	data, err := ParseEthLogIntoSwapWithProofArgs(*log, b.mcs, receipts, method, b.fId, b.tId)
+	if err != nil {
+		return nil, err
+	}
+	header, err := b.client.HeaderByNumber(ctx, new(big.Int).SetUint64(log.BlockNumber))
+	if err != nil {
+		return nil, err
+	}
+	nodes, err := receipttrie.Prove(receipttrie.Receipts(receipts), log.TxIndex)
+	if err != nil {
+		return nil, err
+	}
+	key := rlp.AppendUint64(nil, uint64(log.TxIndex))
+	return &iproof.Proof{
+		Data:         data,
+		Method:       method,
+		ReceiptsRoot: header.ReceiptHash,
+		KeyIndex:     Key2Hex(key, len(nodes)),
+		Nodes:        nodes,
+		Receipt:      receipttrie.Encode(receipts[log.TxIndex]),
+	}, nil
+}
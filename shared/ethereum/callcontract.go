@@ -5,25 +5,20 @@ package utils
 
 import (
 	"bytes"
-	"context"
 	"encoding/binary"
 	"encoding/json"
-	"fmt"
 	"math/big"
 	"strings"
-	"sync"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
-	"github.com/ethereum/go-ethereum/ethdb/memorydb"
-	"github.com/ethereum/go-ethereum/light"
 	"github.com/ethereum/go-ethereum/rlp"
-	"github.com/ethereum/go-ethereum/trie"
 	maptypes "github.com/mapprotocol/atlas/core/types"
 	iproof "github.com/mapprotocol/compass/internal/proof"
 	"github.com/mapprotocol/compass/mapprotocol"
 	"github.com/mapprotocol/compass/msg"
 	"github.com/mapprotocol/compass/pkg/ethclient"
+	"github.com/mapprotocol/compass/pkg/receipttrie"
 	"github.com/pkg/errors"
 )
 
@@ -40,7 +35,7 @@ type TxParams struct {
 type TxProve struct {
 	Tx          *TxParams
 	Receipt     *types.Receipt
-	Prove       light.NodeList
+	Prove       []rlp.RawValue
 	BlockNumber uint64
 	TxIndex     uint
 }
@@ -50,37 +45,18 @@ func ParseEthLogIntoSwapWithProofArgs(log types.Log, bridgeAddr common.Address,
 	blockNumber := log.BlockNumber
 	transactionIndex := log.TxIndex
 
-	proof := light.NewNodeSet()
-	key, err := rlp.EncodeToBytes(transactionIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), transactionIndex)
 	if err != nil {
 		return nil, err
 	}
-
-	// assemble trie tree
-	tr, err := trie.New(common.Hash{}, trie.NewDatabase(memorydb.New()))
-	if err != nil {
-		return nil, err
-	}
-	for i, r := range receipts {
-		key, err := rlp.EncodeToBytes(uint(i))
-		if err != nil {
-			return nil, err
-		}
-		value, err := rlp.EncodeToBytes(r)
-		if err != nil {
-			return nil, err
-		}
-		tr.Update(key, value)
-	}
-
-	tr = DeriveTire(receipts, tr)
-	if err = tr.Prove(key, 0, proof); err != nil {
-		return nil, err
+	nodes := make([]rlp.RawValue, 0, len(proof))
+	for _, n := range proof {
+		nodes = append(nodes, n)
 	}
 
 	txProve := mapprotocol.TxProve{
 		Receipt:     receipts[transactionIndex],
-		Prove:       proof.NodeList(),
+		Prove:       nodes,
 		BlockNumber: blockNumber,
 		TxIndex:     transactionIndex,
 	}
@@ -115,58 +91,46 @@ func ParseEthLogIntoSwapWithProofArgs(log types.Log, bridgeAddr common.Address,
 type MapTxProve struct {
 	Header      *maptypes.Header
 	Receipt     *types.Receipt
-	Prove       light.NodeList
+	Prove       []rlp.RawValue
 	BlockNumber uint64
 	TxIndex     uint
 }
 
-func GetProof(client *ethclient.Client, latestBlock *big.Int, log *types.Log, method string, fId msg.ChainId) ([]byte, error) {
-	header, err := client.MAPHeaderByNumber(context.Background(), latestBlock)
-	if err != nil {
-		return nil, fmt.Errorf("unable to query header Logs: %w", err)
-	}
-	txsHash, err := mapprotocol.GetMapTransactionsHashByBlockNumber(client, latestBlock)
-	if err != nil {
-		return nil, fmt.Errorf("idSame unable to get tx hashes Logs: %w", err)
-	}
-	receipts, err := mapprotocol.GetReceiptsByTxsHash(client, txsHash)
-	if err != nil {
-		return nil, fmt.Errorf("unable to get receipts hashes Logs: %w", err)
-	}
-	//
-	remainder := big.NewInt(0).Mod(latestBlock, big.NewInt(mapprotocol.EpochOfMap))
-	if remainder.Cmp(mapprotocol.Big0) == 0 {
-		lr, err := mapprotocol.GetLastReceipt(client, latestBlock)
-		if err != nil {
-			return nil, fmt.Errorf("unable to get last receipts in epoch last %w", err)
-		}
-		receipts = append(receipts, lr)
-	}
-	_, data, err := AssembleMapProof(client, *log, receipts, header, fId, method)
-	return data, err
-}
-
-func AssembleMapProof(cli *ethclient.Client, log types.Log, receipts []*types.Receipt,
-	header *maptypes.Header, fId msg.ChainId, method string) (uint64, []byte, error) {
+// AssembleMapProof assembles the proof of the map log for the chain it is sent to, the chains other than near
+// take it in the form of the mcs on map
+func AssembleMapProof(cli *ethclient.Client, mc *mapprotocol.MapContext, log types.Log, receipts []*types.Receipt,
+	header *maptypes.Header, fId msg.ChainId, method string) (*iproof.Proof, error) {
 	//toChainID := log.Data[128:160]
 	toChainID := log.Topics[2]
 	uToChainID := binary.BigEndian.Uint64(toChainID[len(toChainID)-8:])
 	txIndex := log.TxIndex
 	aggPK, ist, aggPKBytes, err := mapprotocol.GetAggPK(cli, new(big.Int).Sub(header.Number, big.NewInt(1)), header.Extra)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	receipt, err := mapprotocol.GetTxReceipt(receipts[txIndex])
-	proof, err := iproof.Get(receipts, txIndex)
+	proof, err := receipttrie.Prove(receipttrie.Receipts(receipts), txIndex)
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	var key []byte
 	key = rlp.AppendUint64(key[:0], uint64(txIndex))
 	ek := Key2Hex(key, len(proof))
-	if name, ok := mapprotocol.OnlineChaId[msg.ChainId(uToChainID)]; ok && strings.ToLower(name) != "near" {
+	encReceipt, err := iproof.EncodeReceipt(receipt)
+	if err != nil {
+		return nil, err
+	}
+	ret := &iproof.Proof{
+		Method:       method,
+		ToChainId:    msg.ChainId(uToChainID),
+		ReceiptsRoot: header.ReceiptHash,
+		KeyIndex:     ek,
+		Nodes:        proof,
+		Receipt:      encReceipt,
+	}
+	if name, ok := mc.ChainName(msg.ChainId(uToChainID)); ok && strings.ToLower(name) != "near" {
 		istanbulExtra := mapprotocol.ConvertIstanbulExtra(ist)
 		nr := mapprotocol.MapTxReceipt{
 			PostStateOrStatus: receipt.PostStateOrStatus,
@@ -177,7 +141,7 @@ func AssembleMapProof(cli *ethclient.Client, log types.Log, receipts []*types.Re
 
 		nrRlp, err := rlp.EncodeToBytes(nr)
 		if err != nil {
-			return 0, nil, err
+			return nil, err
 		}
 		rp := mapprotocol.NewMapReceiptProof{
 			Header:   mapprotocol.ConvertHeader(header),
@@ -193,24 +157,23 @@ func AssembleMapProof(cli *ethclient.Client, log types.Log, receipts []*types.Re
 
 		pack, err := mapprotocol.Mcs.Methods[mapprotocol.MethodOfGetBytes].Inputs.Pack(rp)
 		if err != nil {
-			return 0, nil, errors.Wrap(err, "getBytes failed")
+			return nil, errors.Wrap(err, "getBytes failed")
 		}
 
-		fmt.Println("map getBytes after hex ------------ ", "0x"+common.Bytes2Hex(pack))
-		fmt.Println("map id ------------ ", fId)
 		payloads, err := mapprotocol.PackInput(mapprotocol.Mcs, method, big.NewInt(0).SetUint64(uint64(fId)), pack)
 		//payloads, err := mapprotocol.PackInput(mapprotocol.Near, mapprotocol.MethodVerifyProofData, pack)
 		if err != nil {
-			return 0, nil, errors.Wrap(err, "eth pack failed")
+			return nil, errors.Wrap(err, "eth pack failed")
 		}
 
-		return uToChainID, payloads, nil
+		ret.Data = payloads
+		return ret, nil
 	}
 
 	bytesBuffer := bytes.NewBuffer([]byte{})
 	err = binary.Write(bytesBuffer, binary.LittleEndian, uint64(txIndex))
 	if err != nil {
-		return 0, nil, err
+		return nil, err
 	}
 
 	nProof := make([]string, 0, len(proof))
@@ -248,7 +211,8 @@ func AssembleMapProof(cli *ethclient.Client, log types.Log, receipts []*types.Re
 		"receipt_proof": m,
 		"index":         idx,
 	})
-	return uToChainID, data, nil
+	ret.Data = data
+	return ret, nil
 }
 
 func Key2Hex(str []byte, proofLength int) []byte {
@@ -299,48 +263,3 @@ func ConvertNearReceipt(h *mapprotocol.TxReceipt) *TxReceipt {
 		Logs:              logs,
 	}
 }
-
-// deriveBufferPool holds temporary encoder buffers for DeriveSha and TX encoding.
-var encodeBufferPool = sync.Pool{
-	New: func() interface{} { return new(bytes.Buffer) },
-}
-
-// DerivableList is the input to DeriveSha.
-// It is implemented by the 'Transactions' and 'Receipts' types.
-// This is internal, do not use these methods.
-type DerivableList interface {
-	Len() int
-	EncodeIndex(int, *bytes.Buffer)
-}
-
-func encodeForDerive(list DerivableList, i int, buf *bytes.Buffer) []byte {
-	buf.Reset()
-	list.EncodeIndex(i, buf)
-	// It's really unfortunate that we need to do perform this copy.
-	// StackTrie holds onto the values until Hash is called, so the values
-	// written to it must not alias.
-	return common.CopyBytes(buf.Bytes())
-}
-
-func DeriveTire(rs types.Receipts, tr *trie.Trie) *trie.Trie {
-	valueBuf := encodeBufferPool.Get().(*bytes.Buffer)
-	defer encodeBufferPool.Put(valueBuf)
-
-	var indexBuf []byte
-	for i := 1; i < rs.Len() && i <= 0x7f; i++ {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], uint64(i))
-		value := encodeForDerive(rs, i, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	if rs.Len() > 0 {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], 0)
-		value := encodeForDerive(rs, 0, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	for i := 0x80; i < rs.Len(); i++ {
-		indexBuf = rlp.AppendUint64(indexBuf[:0], uint64(i))
-		value := encodeForDerive(rs, i, valueBuf)
-		tr.Update(indexBuf, value)
-	}
-	return tr
-}